@@ -0,0 +1,427 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorcore"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// app is the state shared by a command run
+type app struct {
+	provider *xplorcore.XplorProvider
+	settings settings
+	options  *options
+	resource string   // Resource of the running command, e.g. "classes"
+	session  *session // Set when running in the shell
+}
+
+// apiError is an error returned by the API, reported with exit status 1
+type apiError struct {
+	*xplorentities.ErrorResponse
+}
+
+func (e apiError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", strings.TrimSpace(e.Message), e.Code)
+}
+
+func fromAPI(err *xplorentities.ErrorResponse) error {
+	if err == nil {
+		return nil
+	}
+	return apiError{err}
+}
+
+// usageError is a command line error, reported with the command usage and exit status 2
+type usageError struct {
+	message string
+}
+
+func (e usageError) Error() string {
+	return e.message
+}
+
+// command is an action on a resource, e.g. "contacts list"
+type command struct {
+	summary string
+	args    string // Positional arguments shown in the usage, e.g. "ID"
+	// flags registers the command flags and returns the function running it
+	flags func(fs *flag.FlagSet) func(a *app, args []string) error
+}
+
+// commands lists the actions of each resource
+var commands = map[string]map[string]command{
+	"contacts": {
+		"list": {summary: "List contacts", flags: listContacts},
+		"get": {summary: "Show a contact", args: "ID", flags: getOne(func(a *app, node, id string) (*xplorentities.XPlorContact, *xplorentities.ErrorResponse) {
+			return a.provider.Contact(node, id)
+		}, contactColumns)},
+	},
+	"classes": {
+		"list": {summary: "List classes", flags: listClasses},
+		"get": {summary: "Show a class", args: "ID", flags: getOne(func(a *app, node, id string) (*xplorentities.XPlorClass, *xplorentities.ErrorResponse) {
+			return a.provider.Class(node, id)
+		}, classColumns)},
+	},
+	"subscriptions": {
+		"list": {summary: "List subscriptions", flags: listSubscriptions},
+		"get": {summary: "Show a subscription", args: "ID", flags: getOne(func(a *app, node, id string) (*xplorentities.XPlorSubscription, *xplorentities.ErrorResponse) {
+			return a.provider.Subscription(node, id)
+		}, subscriptionColumns)},
+	},
+	"attendees": {
+		"list": {summary: "List the attendees of a class", flags: listAttendees},
+	},
+	"clubs":      genericCommands(clubColumns),
+	"studios":    genericCommands(studioColumns),
+	"coaches":    genericCommands(coachColumns),
+	"activities": genericCommands(activityColumns),
+	"zones":      genericCommands(zoneColumns),
+	"articles":   genericCommands(articleColumns),
+}
+
+// collect fetches the requested page, or every page up to --max-pages with --all
+func collect[T any](a *app, fetch func(pagination *xplorentities.XPlorPagination) ([]T, xplorentities.PageInfo, *xplorentities.ErrorResponse)) ([]T, error) {
+	pagination := &xplorentities.XPlorPagination{Page: a.options.page, ItemsPerPage: a.options.perPage}
+	var items []T
+	for fetched := 1; ; fetched++ {
+		page, info, err := fetch(pagination)
+		if err != nil {
+			return items, fromAPI(err)
+		}
+		items = append(items, page...)
+		if !a.options.all || info.NextPage == 0 || (a.options.maxPages > 0 && fetched >= a.options.maxPages) {
+			record(a, items)
+			return items, nil
+		}
+		pagination = &xplorentities.XPlorPagination{Page: info.NextPage, ItemsPerPage: a.options.perPage}
+	}
+}
+
+// getOne builds a "get ID" command from a provider lookup
+func getOne[T any](get func(a *app, node, id string) (*T, *xplorentities.ErrorResponse), columns []column[T]) func(fs *flag.FlagSet) func(a *app, args []string) error {
+	return func(fs *flag.FlagSet) func(a *app, args []string) error {
+		return func(a *app, args []string) error {
+			if len(args) != 1 {
+				return usageError{"expected exactly one ID"}
+			}
+			node, err := a.node()
+			if err != nil {
+				return err
+			}
+			item, apiErr := get(a, node, args[0])
+			if apiErr != nil {
+				return fromAPI(apiErr)
+			}
+			record(a, []T{*item})
+			if a.options.output == formatJSON {
+				return renderJSON(stdout, item)
+			}
+			return render(stdout, a.options.output, columns, []T{*item})
+		}
+	}
+}
+
+// genericCommands lists and shows any entity through the generic collection API
+func genericCommands[T xplorentities.Entity](columns []column[T]) map[string]command {
+	var zero T
+	resource := string(zero.Resource())
+	return map[string]command{
+		"list": {summary: "List " + resource, flags: func(fs *flag.FlagSet) func(a *app, args []string) error {
+			return func(a *app, args []string) error {
+				node, err := a.node()
+				if err != nil {
+					return err
+				}
+				items, err := collect(a, func(pagination *xplorentities.XPlorPagination) ([]T, xplorentities.PageInfo, *xplorentities.ErrorResponse) {
+					page, err := xplorcore.List[T](a.provider, node, nil, pagination)
+					if err != nil {
+						return nil, xplorentities.PageInfo{}, err
+					}
+					return page.Members, page.PageInfo(), nil
+				})
+				if err != nil {
+					return err
+				}
+				return render(stdout, a.options.output, columns, items)
+			}
+		}},
+		"get": {summary: "Show one of " + resource, args: "ID", flags: getOne(func(a *app, node, id string) (*T, *xplorentities.ErrorResponse) {
+			return xplorcore.Get[T](a.provider, node, id)
+		}, columns)},
+	}
+}
+
+func listContacts(fs *flag.FlagSet) func(a *app, args []string) error {
+	var params xplorentities.XPlorContactsParams
+	fs.StringVar(&params.Email, "email", "", "filter by e-mail address")
+	fs.StringVar(&params.Mobile, "mobile", "", "filter by mobile number")
+	fs.StringVar(&params.Number, "number", "", "filter by contact number")
+	fs.StringVar(&params.ClubID, "club", "", "filter by club ID")
+	fs.StringVar(&params.State, "state", "", "filter by state")
+	fs.StringVar(&params.FamilyName, "family-name", "", "filter by family name")
+	fs.StringVar(&params.GivenName, "given-name", "", "filter by given name")
+	return func(a *app, args []string) error {
+		node, err := a.node()
+		if err != nil {
+			return err
+		}
+		items, err := collect(a, func(pagination *xplorentities.XPlorPagination) ([]xplorentities.XPlorContact, xplorentities.PageInfo, *xplorentities.ErrorResponse) {
+			page, err := a.provider.Contacts(node, &params, pagination)
+			if err != nil {
+				return nil, xplorentities.PageInfo{}, err
+			}
+			return page.Members, page.PageInfo(), nil
+		})
+		if err != nil {
+			return err
+		}
+		return render(stdout, a.options.output, contactColumns, items)
+	}
+}
+
+func listClasses(fs *flag.FlagSet) func(a *app, args []string) error {
+	club := fs.String("club", "", "filter by club ID")
+	coach := fs.String("coach", "", "filter by coach ID")
+	activity := fs.String("activity", "", "filter by activity ID")
+	studio := fs.String("studio", "", "filter by studio ID")
+	from := fs.String("from", "", "classes starting at or after `DATE` (2006-01-02 or 2006-01-02T15:04:05)")
+	to := fs.String("to", "", "classes starting at or before `DATE`; a date includes the whole day")
+	return func(a *app, args []string) error {
+		node, err := a.node()
+		if err != nil {
+			return err
+		}
+		params := xplorentities.XPlorClassesParams{Order: []xplorentities.Order{xplorentities.OrderBy("startedAt")}}
+		if *club != "" {
+			params.Club = club
+		}
+		params.Coach = a.iri(xplorentities.ResourceCoaches, *coach)
+		params.Activity = a.iri(xplorentities.ResourceActivities, *activity)
+		params.Studio = a.iri(xplorentities.ResourceStudios, *studio)
+		if *from != "" || *to != "" {
+			params.StartedAt = &xplorentities.DateFilter{}
+			if params.StartedAt.After, err = parseDate("from", *from, false); err != nil {
+				return err
+			}
+			if params.StartedAt.Before, err = parseDate("to", *to, true); err != nil {
+				return err
+			}
+		}
+		items, err := collect(a, func(pagination *xplorentities.XPlorPagination) ([]xplorentities.XPlorClass, xplorentities.PageInfo, *xplorentities.ErrorResponse) {
+			page, err := a.provider.Classes(node, &params, pagination)
+			if err != nil {
+				return nil, xplorentities.PageInfo{}, err
+			}
+			return page.Members, page.PageInfo(), nil
+		})
+		if err != nil {
+			return err
+		}
+		return render(stdout, a.options.output, classColumns, items)
+	}
+}
+
+func listSubscriptions(fs *flag.FlagSet) func(a *app, args []string) error {
+	var params xplorentities.XPlorSubscriptionsParams
+	fs.StringVar(&params.ContactId, "contact", "", "filter by contact ID")
+	fs.StringVar(&params.ClubId, "club", "", "filter by club ID")
+	fs.StringVar(&params.ArticleId, "article", "", "filter by article ID")
+	fs.StringVar(&params.Name, "name", "", "filter by name")
+	fs.BoolFunc("active", "only active subscriptions; --active=false for the inactive ones", func(value string) error {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		params.IsActive = &active
+		return nil
+	})
+	return func(a *app, args []string) error {
+		node, err := a.node()
+		if err != nil {
+			return err
+		}
+		items, err := collect(a, func(pagination *xplorentities.XPlorPagination) ([]xplorentities.XPlorSubscription, xplorentities.PageInfo, *xplorentities.ErrorResponse) {
+			page, err := a.provider.Subscriptions(node, &params, pagination)
+			if err != nil {
+				return nil, xplorentities.PageInfo{}, err
+			}
+			return page.Members, page.PageInfo(), nil
+		})
+		if err != nil {
+			return err
+		}
+		return render(stdout, a.options.output, subscriptionColumns, items)
+	}
+}
+
+func listAttendees(fs *flag.FlagSet) func(a *app, args []string) error {
+	class := fs.String("class", "", "class `ID` (required)")
+	return func(a *app, args []string) error {
+		if *class == "" {
+			return usageError{"--class is required"}
+		}
+		node, err := a.node()
+		if err != nil {
+			return err
+		}
+		items, err := collect(a, func(pagination *xplorentities.XPlorPagination) ([]xplorentities.XPlorAttendee, xplorentities.PageInfo, *xplorentities.ErrorResponse) {
+			page, err := a.provider.Attendees(node, class, pagination)
+			if err != nil {
+				return nil, xplorentities.PageInfo{}, err
+			}
+			return page.Members, page.PageInfo(), nil
+		})
+		if err != nil {
+			return err
+		}
+		return render(stdout, a.options.output, attendeeColumns, items)
+	}
+}
+
+// node returns the network node the commands run against
+func (a *app) node() (string, error) {
+	if strings.TrimSpace(a.options.node) == "" {
+		return "", usageError{"a network node is required: use --node or XPLOR_NODE"}
+	}
+	return a.options.node, nil
+}
+
+// iri turns a bare ID into the IRI filters expect; IRIs are kept as given
+func (a *app) iri(resource xplorentities.Resource, id string) *string {
+	if id == "" {
+		return nil
+	}
+	if !strings.HasPrefix(id, "/") {
+		id = "/" + a.settings.Enterprise + resource.Path() + "/" + id
+	}
+	return &id
+}
+
+// parseDate reads a date or datetime flag in local time; a date given as end of range covers the whole day
+func parseDate(name, value string, end bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		if end {
+			t = t.Add(24*time.Hour - time.Second)
+		}
+		return &t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, usageError{fmt.Sprintf("invalid --%s %q, expected 2006-01-02 or 2006-01-02T15:04:05", name, value)}
+}
+
+var contactColumns = []column[xplorentities.XPlorContact]{
+	{"ID", func(c xplorentities.XPlorContact) string { return xplorentities.ShortID(util.Deref(c.ID)) }},
+	{"NUMBER", func(c xplorentities.XPlorContact) string { return c.Number }},
+	{"GIVEN NAME", func(c xplorentities.XPlorContact) string { return c.GivenName }},
+	{"FAMILY NAME", func(c xplorentities.XPlorContact) string { return c.FamilyName }},
+	{"EMAIL", func(c xplorentities.XPlorContact) string { return c.Email }},
+	{"MOBILE", func(c xplorentities.XPlorContact) string { return util.Deref(c.Mobile) }},
+	{"CLUB", func(c xplorentities.XPlorContact) string { return xplorentities.ShortID(util.Deref(c.ClubID)) }},
+}
+
+var classColumns = []column[xplorentities.XPlorClass]{
+	{"ID", func(c xplorentities.XPlorClass) string { return xplorentities.ShortID(util.Deref(c.ID)) }},
+	{"STARTED AT", func(c xplorentities.XPlorClass) string { return localTime(c.StartedAt) }},
+	{"ENDED AT", func(c xplorentities.XPlorClass) string { return localTime(c.EndedAt) }},
+	{"SUMMARY", func(c xplorentities.XPlorClass) string { return c.Summary }},
+	{"ACTIVITY", func(c xplorentities.XPlorClass) string { return xplorentities.ShortID(util.Deref(c.Activity)) }},
+	{"COACH", func(c xplorentities.XPlorClass) string { return xplorentities.ShortID(util.Deref(c.Coach)) }},
+	{"STUDIO", func(c xplorentities.XPlorClass) string { return xplorentities.ShortID(util.Deref(c.Studio)) }},
+	{"BOOKED", func(c xplorentities.XPlorClass) string { return strconv.Itoa(len(c.BookedAttendees)) }},
+	{"REMAINING", func(c xplorentities.XPlorClass) string { return strconv.Itoa(c.AttendeeRemaining) }},
+}
+
+var subscriptionColumns = []column[xplorentities.XPlorSubscription]{
+	{"ID", func(s xplorentities.XPlorSubscription) string { return xplorentities.ShortID(util.Deref(s.Id)) }},
+	{"NAME", func(s xplorentities.XPlorSubscription) string { return s.Name }},
+	{"CONTACT", func(s xplorentities.XPlorSubscription) string { return xplorentities.ShortID(util.Deref(s.Contact.Id)) }},
+	{"CLUB", func(s xplorentities.XPlorSubscription) string { return xplorentities.ShortID(s.ClubId) }},
+	{"VALID FROM", func(s xplorentities.XPlorSubscription) string { return s.ValidFrom }},
+	{"VALID THROUGH", func(s xplorentities.XPlorSubscription) string { return s.ValidThrough }},
+	{"TERMINATED AT", func(s xplorentities.XPlorSubscription) string { return s.TerminatedAt }},
+}
+
+var attendeeColumns = []column[xplorentities.XPlorAttendee]{
+	{"ID", func(a xplorentities.XPlorAttendee) string { return xplorentities.ShortID(util.Deref(a.AtID)) }},
+	{"CONTACT", func(a xplorentities.XPlorAttendee) string { return xplorentities.ShortID(util.Deref(a.ContactId)) }},
+	{"GIVEN NAME", func(a xplorentities.XPlorAttendee) string { return util.Deref(a.ContactGivenName) }},
+	{"FAMILY NAME", func(a xplorentities.XPlorAttendee) string { return util.Deref(a.ContactFamilyName) }},
+	{"CREATED AT", func(a xplorentities.XPlorAttendee) string { return util.Deref(a.CreatedAt) }},
+	{"CANCELED AT", func(a xplorentities.XPlorAttendee) string { return util.Deref(a.CanceledAt) }},
+}
+
+var clubColumns = []column[xplorentities.XPlorClub]{
+	{"ID", func(c xplorentities.XPlorClub) string { return xplorentities.ShortID(c.ID) }},
+	{"CODE", func(c xplorentities.XPlorClub) string { return c.Code }},
+	{"NAME", func(c xplorentities.XPlorClub) string { return c.Name }},
+	{"LOCALITY", func(c xplorentities.XPlorClub) string { return c.AddressLocality }},
+	{"COUNTRY", func(c xplorentities.XPlorClub) string { return c.AddressCountryIso }},
+}
+
+var studioColumns = []column[xplorentities.XPlorStudio]{
+	{"ID", func(s xplorentities.XPlorStudio) string { return xplorentities.ShortID(util.Deref(s.ID)) }},
+	{"NAME", func(s xplorentities.XPlorStudio) string { return s.Name }},
+	{"CLUB", func(s xplorentities.XPlorStudio) string { return xplorentities.ShortID(util.Deref(s.Club)) }},
+	{"CAPACITY", func(s xplorentities.XPlorStudio) string { return number(s.Capacity) }},
+}
+
+var coachColumns = []column[xplorentities.XPloreCoach]{
+	{"ID", func(c xplorentities.XPloreCoach) string { return xplorentities.ShortID(util.Deref(c.Id)) }},
+	{"GIVEN NAME", func(c xplorentities.XPloreCoach) string { return util.Deref(c.GivenName) }},
+	{"FAMILY NAME", func(c xplorentities.XPloreCoach) string { return util.Deref(c.FamilyName) }},
+	{"EMAIL", func(c xplorentities.XPloreCoach) string { return util.Deref(c.Email) }},
+	{"ARCHIVED AT", func(c xplorentities.XPloreCoach) string { return localTimePtr(c.ArchivedAt) }},
+}
+
+var activityColumns = []column[xplorentities.XPlorActivity]{
+	{"ID", func(a xplorentities.XPlorActivity) string { return xplorentities.ShortID(util.Deref(a.ID)) }},
+	{"NAME", func(a xplorentities.XPlorActivity) string { return a.Name }},
+	{"CLUB", func(a xplorentities.XPlorActivity) string { return xplorentities.ShortID(util.Deref(a.ClubId)) }},
+	{"BOOKABLE", func(a xplorentities.XPlorActivity) string { return strconv.FormatBool(a.IsBookable) }},
+}
+
+var zoneColumns = []column[xplorentities.XPlorZone]{
+	{"ID", func(z xplorentities.XPlorZone) string { return xplorentities.ShortID(util.Deref(z.ID)) }},
+	{"NAME", func(z xplorentities.XPlorZone) string { return z.Name }},
+	{"CLUB", func(z xplorentities.XPlorZone) string { return xplorentities.ShortID(z.ClubID) }},
+	{"ENTRANCE", func(z xplorentities.XPlorZone) string { return strconv.FormatBool(z.Entrance) }},
+}
+
+var articleColumns = []column[xplorentities.XPlorArticle]{
+	{"ID", func(a xplorentities.XPlorArticle) string { return xplorentities.ShortID(util.Deref(a.ID)) }},
+	{"CODE", func(a xplorentities.XPlorArticle) string { return a.ProductCode }},
+	{"NAME", func(a xplorentities.XPlorArticle) string { return a.ProductName }},
+	{"TYPE", func(a xplorentities.XPlorArticle) string { return a.ProductType }},
+	{"PRICE", func(a xplorentities.XPlorArticle) string {
+		return strconv.FormatFloat(a.PriceTI, 'f', 2, 64) + " " + a.PriceCurrency
+	}},
+}
+
+// errorMessage formats err for the terminal
+func errorMessage(err error) string {
+	var api apiError
+	if errors.As(err, &api) {
+		var body struct {
+			Description string `json:"hydra:description"`
+		}
+		if raw, ok := strings.CutPrefix(api.Message, "Response: "); ok && json.Unmarshal([]byte(raw), &body) == nil && body.Description != "" {
+			return fmt.Sprintf("%s (HTTP %d)", body.Description, api.Code)
+		}
+	}
+	return err.Error()
+}
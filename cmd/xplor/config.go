@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// settings are the credentials and defaults of the CLI.
+// They are read from the config file, then overridden by XPLOR_* environment variables and flags.
+type settings struct {
+	Host         string `json:"host"`
+	APIVersion   string `json:"apiVersion"`
+	Enterprise   string `json:"enterprise"`
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+	Node         string `json:"node"`
+}
+
+// defaultConfigPath returns ~/.config/xplor/config.json or its platform equivalent
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "xplor", "config.json")
+}
+
+// loadSettings reads the config file at path, when it exists, and applies the environment.
+// An explicit path that does not exist is an error; the default one is optional.
+func loadSettings(path string) (settings, error) {
+	var s settings
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv("XPLOR_CONFIG")
+		explicit = path != ""
+	}
+	if !explicit {
+		path = defaultConfigPath()
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist) && !explicit:
+		case err != nil:
+			return s, err
+		default:
+			if err := json.Unmarshal(data, &s); err != nil {
+				return s, fmt.Errorf("invalid config file %s: %w", path, err)
+			}
+		}
+	}
+
+	for name, field := range map[string]*string{
+		"XPLOR_HOST":          &s.Host,
+		"XPLOR_API_VERSION":   &s.APIVersion,
+		"XPLOR_ENTERPRISE":    &s.Enterprise,
+		"XPLOR_CLIENT_ID":     &s.ClientID,
+		"XPLOR_CLIENT_SECRET": &s.ClientSecret,
+		"XPLOR_NODE":          &s.Node,
+	} {
+		if value, ok := os.LookupEnv(name); ok {
+			*field = value
+		}
+	}
+	if s.APIVersion == "" {
+		s.APIVersion = "v1"
+	}
+	return s, nil
+}
+
+// validate reports the missing credentials
+func (s settings) validate() error {
+	var missing []string
+	for name, value := range map[string]string{
+		"host (XPLOR_HOST)":                  s.Host,
+		"enterprise (XPLOR_ENTERPRISE)":      s.Enterprise,
+		"clientId (XPLOR_CLIENT_ID)":         s.ClientID,
+		"clientSecret (XPLOR_CLIENT_SECRET)": s.ClientSecret,
+	} {
+		if strings.TrimSpace(value) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.New("missing settings: " + strings.Join(sorted(missing), ", "))
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// lineReader reads the shell input one line at a time
+type lineReader interface {
+	readLine(prompt string) (string, error)
+}
+
+// newLineReader returns a line editor with completion and history on terminals, a plain reader otherwise
+func newLineReader(in *os.File, out io.Writer, complete func(words []string, current string) []string, history func() []string) lineReader {
+	if isTerminal(int(in.Fd())) {
+		return &editor{fd: int(in.Fd()), in: bufio.NewReader(in), out: out, complete: complete, history: history}
+	}
+	return &plainReader{in: bufio.NewReader(in), out: out}
+}
+
+// plainReader reads lines from a pipe or a terminal that cannot be switched to raw mode
+type plainReader struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (r *plainReader) readLine(prompt string) (string, error) {
+	fmt.Fprint(r.out, prompt)
+	line, err := r.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// editor is a minimal line editor: typing at the end of the line, Tab completion, Up/Down history,
+// Backspace, Ctrl-U, Ctrl-W, Ctrl-C to drop the line and Ctrl-D to quit
+type editor struct {
+	fd       int
+	in       *bufio.Reader
+	out      io.Writer
+	complete func(words []string, current string) []string
+	history  func() []string
+}
+
+const (
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyBackspace = 8
+	keyTab       = 9
+	keyEnter     = 13
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+func (e *editor) readLine(prompt string) (string, error) {
+	restore, err := makeRaw(e.fd)
+	if err != nil {
+		return (&plainReader{in: e.in, out: e.out}).readLine(prompt)
+	}
+	defer restore()
+
+	var line []rune
+	history := e.history()
+	position := len(history)
+	redraw := func() {
+		fmt.Fprintf(e.out, "\r\x1b[K%s%s", prompt, string(line))
+	}
+	redraw()
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch r {
+		case keyEnter, '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(line), nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			line = line[:0]
+		case keyCtrlD:
+			if len(line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+		case keyBackspace, keyDelete:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+			}
+		case keyCtrlU:
+			line = line[:0]
+		case keyCtrlW:
+			trimmed := strings.TrimRight(string(line), " ")
+			line = []rune(trimmed[:strings.LastIndex(trimmed, " ")+1])
+		case keyTab:
+			line = e.completeLine(prompt, line)
+		case keyEscape:
+			// Arrow keys arrive as ESC [ A (up) and ESC [ B (down); other sequences are ignored
+			if next, _, _ := e.in.ReadRune(); next != '[' {
+				continue
+			}
+			switch key, _, _ := e.in.ReadRune(); key {
+			case 'A':
+				if position > 0 {
+					position--
+					line = []rune(history[position])
+				}
+			case 'B':
+				if position < len(history) {
+					position++
+					line = nil
+					if position < len(history) {
+						line = []rune(history[position])
+					}
+				}
+			}
+		default:
+			if r >= ' ' {
+				line = append(line, r)
+			}
+		}
+		redraw()
+	}
+}
+
+// completeLine completes the word under the cursor, or lists the candidates when they share no longer prefix
+func (e *editor) completeLine(prompt string, line []rune) []rune {
+	text := string(line)
+	start := strings.LastIndex(text, " ") + 1
+	current := text[start:]
+	var candidates []string
+	for _, candidate := range e.complete(strings.Fields(text[:start]), current) {
+		if strings.HasPrefix(candidate, current) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		fmt.Fprint(e.out, "\a")
+		return line
+	case 1:
+		return []rune(text[:start] + candidates[0] + " ")
+	}
+	if prefix := commonPrefix(candidates); len(prefix) > len(current) {
+		return []rune(text[:start] + prefix)
+	}
+	fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+	return line
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,210 @@
+// Command xplor runs ad-hoc queries against the XPlor API.
+//
+// Usage:
+//
+//	xplor <resource> <action> [flags] [ID]
+//	xplor shell [flags]
+//
+// Credentials come from the config file (~/.config/xplor/config.json, --config or XPLOR_CONFIG)
+// overridden by the XPLOR_HOST, XPLOR_API_VERSION, XPLOR_ENTERPRISE, XPLOR_CLIENT_ID,
+// XPLOR_CLIENT_SECRET and XPLOR_NODE environment variables.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/angelbarreiros/XPlorGo/xplorcore"
+)
+
+var (
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+// options are the flags shared by every command
+type options struct {
+	config   string
+	node     string
+	output   string
+	verbose  bool
+	page     int
+	perPage  int
+	all      bool
+	maxPages int
+}
+
+// newOptions returns the default options
+func newOptions() *options {
+	return &options{output: formatTable, page: 1, perPage: 30}
+}
+
+// register adds the query flags, defaulting to the current values of o
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.node, "node", o.node, "network node `ID`, XPLOR_NODE when not set")
+	fs.StringVar(&o.output, "o", o.output, "output `format`: table, json or csv")
+	fs.IntVar(&o.page, "page", o.page, "page to fetch")
+	fs.IntVar(&o.perPage, "per-page", o.perPage, "items per page")
+	fs.BoolVar(&o.all, "all", o.all, "follow the next pages")
+	fs.IntVar(&o.maxPages, "max-pages", o.maxPages, "stop --all after `n` pages; zero means no limit")
+}
+
+// validate rejects option values the flag package accepts but no command can use
+func (o *options) validate() error {
+	switch o.output {
+	case formatTable, formatJSON, formatCSV:
+		return nil
+	}
+	return usageError{fmt.Sprintf("unknown output format %q, expected table, json or csv", o.output)}
+}
+
+// registerSession adds the flags read once, when the provider is created
+func (o *options) registerSession(fs *flag.FlagSet) {
+	fs.StringVar(&o.config, "config", o.config, "config file `path` (default $XPLOR_CONFIG or "+defaultConfigPath()+")")
+	fs.BoolVar(&o.verbose, "verbose", o.verbose, "log each request as curl and its response to stderr")
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run executes a command line and returns the exit status: 1 for API and I/O errors, 2 for usage errors
+func run(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		return 2
+	}
+	if args[0] == "shell" {
+		return runShell(args[1:])
+	}
+	actions, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "xplor: unknown resource %q\n\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	if len(args) < 2 {
+		fmt.Fprintf(stderr, "xplor: missing action for %s\n\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	cmd, ok := actions[args[1]]
+	if !ok {
+		fmt.Fprintf(stderr, "xplor: unknown action %q for %s\n\n", args[1], args[0])
+		usage(stderr)
+		return 2
+	}
+
+	opts := newOptions()
+	fs, execute := prepare(args[0], args[1], cmd, opts)
+	opts.registerSession(fs)
+	positional, err := parseInterspersed(fs, args[2:])
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+	if err := opts.validate(); err != nil {
+		fmt.Fprintln(stderr, "xplor:", err)
+		fs.Usage()
+		return 2
+	}
+
+	a, err := connect(opts)
+	if err != nil {
+		fmt.Fprintln(stderr, "xplor:", err)
+		return 2
+	}
+	if err := execute(a, positional); err != nil {
+		fmt.Fprintln(stderr, "xplor:", errorMessage(err))
+		var usageErr usageError
+		if errors.As(err, &usageErr) {
+			fs.Usage()
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+// prepare returns the flag set of a command and the function running it once the flags are parsed
+func prepare(resource, action string, cmd command, opts *options) (*flag.FlagSet, func(a *app, args []string) error) {
+	name := "xplor " + resource + " " + action
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts.register(fs)
+	execute := cmd.flags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s\n\n%s.\n\nFlags:\n", strings.TrimSpace(name+" [flags] "+cmd.args), cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs, func(a *app, args []string) error {
+		a.resource = resource
+		return execute(a, args)
+	}
+}
+
+// connect loads the settings and creates the provider
+func connect(opts *options) (*app, error) {
+	s, err := loadSettings(opts.config)
+	if err == nil {
+		err = s.validate()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if opts.node == "" {
+		opts.node = s.Node
+	}
+	return &app{
+		provider: xplorcore.Init(xplorcore.NewConfig(s.Host, s.APIVersion, s.Enterprise, s.ClientID, s.ClientSecret, nil, opts.verbose)),
+		settings: s,
+		options:  opts,
+	}, nil
+}
+
+// parseInterspersed parses flags placed before and after positional arguments, e.g. "get 42 -o json"
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: xplor <resource> <action> [flags] [ID]")
+	fmt.Fprintln(w, "\nCommands:")
+	resources := make([]string, 0, len(commands))
+	for resource := range commands {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	for _, resource := range resources {
+		actions := make([]string, 0, len(commands[resource]))
+		for action := range commands[resource] {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+		for _, action := range actions {
+			cmd := commands[resource][action]
+			line := strings.TrimSpace(resource + " " + action + " " + cmd.args)
+			fmt.Fprintf(w, "  %-26s %s\n", line, cmd.summary)
+		}
+	}
+	fmt.Fprintf(w, "  %-26s %s\n", "shell", "Start an interactive shell")
+	fmt.Fprintln(w, "\nRun \"xplor <resource> <action> -h\" for the flags of a command.")
+}
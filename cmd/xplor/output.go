@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+)
+
+// Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// column renders one field of T in table and CSV output
+type column[T any] struct {
+	header string
+	value  func(item T) string
+}
+
+// render writes items in the given format; JSON output keeps every field, tables and CSV the columns
+func render[T any](w io.Writer, format string, columns []column[T], items []T) error {
+	switch format {
+	case formatJSON:
+		if items == nil {
+			items = []T{}
+		}
+		return renderJSON(w, items)
+	case formatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(headers(columns)); err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := writer.Write(row(columns, item)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case formatTable:
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, strings.Join(headers(columns), "\t"))
+		for _, item := range items {
+			fmt.Fprintln(writer, strings.Join(row(columns, item), "\t"))
+		}
+		return writer.Flush()
+	}
+	return fmt.Errorf("unknown output format %q, expected table, json or csv", format)
+}
+
+// renderJSON writes value as indented JSON
+func renderJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func headers[T any](columns []column[T]) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.header
+	}
+	return names
+}
+
+func row[T any](columns []column[T], item T) []string {
+	values := make([]string, len(columns))
+	for i, c := range columns {
+		values[i] = c.value(item)
+	}
+	return values
+}
+
+func number(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func localTime(value util.LocalTime) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.Format(time.DateTime)
+}
+
+func localTimePtr(value *util.LocalTime) string {
+	if value == nil {
+		return ""
+	}
+	return localTime(*value)
+}
+
+func sorted(values []string) []string {
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,499 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+const (
+	maxRecentIDs = 20  // Recently seen IDs kept per resource for completion
+	maxHistory   = 500 // Lines kept in the history file
+)
+
+// commandResources ties each CLI resource to its API collection, to follow IRIs and remember IDs
+var commandResources = map[string]xplorentities.Resource{
+	"activities":    xplorentities.ResourceActivities,
+	"articles":      xplorentities.ResourceArticles,
+	"attendees":     xplorentities.ResourceAttendees,
+	"classes":       xplorentities.ResourceClasses,
+	"clubs":         xplorentities.ResourceClubs,
+	"coaches":       xplorentities.ResourceCoaches,
+	"contacts":      xplorentities.ResourceContacts,
+	"studios":       xplorentities.ResourceStudios,
+	"subscriptions": xplorentities.ResourceSubscriptions,
+	"zones":         xplorentities.ResourceZones,
+}
+
+// flagResources completes the values of the filters taking an ID with the IDs seen for their resource
+var flagResources = map[string]string{
+	"activity": "activities",
+	"article":  "articles",
+	"class":    "classes",
+	"club":     "clubs",
+	"coach":    "coaches",
+	"contact":  "contacts",
+	"studio":   "studios",
+}
+
+// builtins are the shell commands besides "<resource> <action>"
+var builtins = map[string]string{
+	"node":    "node [ID]           show or select the network node",
+	"output":  "output FORMAT       select the output: table, json or csv",
+	"open":    "open [FIELD [N]]    follow an IRI of the last entity shown, e.g. \"open coach\"; N picks an item of a list",
+	"recent":  "recent [RESOURCE]   list the IDs seen in this session",
+	"history": "history             list the previous commands",
+	"help":    "help                show this help",
+	"exit":    "exit                leave the shell (or Ctrl-D)",
+}
+
+// session is the shell state kept between commands
+type session struct {
+	app         *app
+	defaults    options             // Flags applied to every command unless overridden
+	recent      map[string][]string // Recently seen IDs per resource, most recent first
+	current     map[string]any      // Last entity shown, followed by open
+	history     []string
+	historyPath string
+}
+
+// runShell starts the interactive shell with the provider kept authenticated between commands
+func runShell(args []string) int {
+	opts := newOptions()
+	fs := flag.NewFlagSet("xplor shell", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts.register(fs)
+	opts.registerSession(fs)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, "Usage: xplor shell [flags]\n\nStart an interactive shell. The flags are the defaults of every command.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	a, err := connect(opts)
+	if err != nil {
+		fmt.Fprintln(stderr, "xplor:", err)
+		return 2
+	}
+
+	s := &session{app: a, defaults: *opts, recent: make(map[string][]string)}
+	a.session = s
+	if dir, err := os.UserConfigDir(); err == nil {
+		s.historyPath = filepath.Join(dir, "xplor", "history")
+		s.loadHistory()
+	}
+	reader := newLineReader(os.Stdin, stdout, s.candidates, func() []string { return s.history })
+	fmt.Fprintln(stdout, `xplor shell: type "help" for the commands, Tab to complete`)
+	for {
+		line, err := reader.readLine(s.prompt())
+		if err == io.EOF {
+			return 0
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, "xplor:", err)
+			return 1
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		s.addHistory(line)
+		if quit := s.execute(line); quit {
+			return 0
+		}
+	}
+}
+
+func (s *session) prompt() string {
+	if s.defaults.node == "" {
+		return "xplor> "
+	}
+	return "xplor:" + s.defaults.node + "> "
+}
+
+// execute runs one line and reports whether the shell should exit
+func (s *session) execute(line string) bool {
+	words, err := splitWords(line)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return false
+	}
+	switch words[0] {
+	case "exit", "quit":
+		return true
+	case "help":
+		s.help()
+	case "node":
+		if len(words) > 1 {
+			s.defaults.node = words[1]
+		}
+		fmt.Fprintln(stdout, "node:", s.defaults.node)
+	case "output":
+		if len(words) != 2 || (words[1] != formatTable && words[1] != formatJSON && words[1] != formatCSV) {
+			fmt.Fprintln(stderr, "error: expected output table, json or csv")
+			break
+		}
+		s.defaults.output = words[1]
+	case "open":
+		s.open(words[1:])
+	case "recent":
+		s.printRecent(words[1:])
+	case "history":
+		for i, entry := range s.history {
+			fmt.Fprintf(stdout, "%4d  %s\n", i+1, entry)
+		}
+	default:
+		s.run(words)
+	}
+	return false
+}
+
+// run executes a "<resource> <action> [flags] [ID]" command with the session defaults
+func (s *session) run(words []string) {
+	actions, ok := commands[words[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "error: unknown command %q, type \"help\" for the commands\n", words[0])
+		return
+	}
+	if len(words) < 2 {
+		fmt.Fprintf(stderr, "error: missing action for %s: %s\n", words[0], strings.Join(sortedKeys(actions), ", "))
+		return
+	}
+	cmd, ok := actions[words[1]]
+	if !ok {
+		fmt.Fprintf(stderr, "error: unknown action %q for %s\n", words[1], words[0])
+		return
+	}
+	opts := s.defaults
+	fs, execute := prepare(words[0], words[1], cmd, &opts)
+	positional, err := parseInterspersed(fs, words[2:])
+	if err != nil {
+		return
+	}
+	if err := opts.validate(); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		fs.Usage()
+		return
+	}
+	a := *s.app
+	a.options = &opts
+	if err := execute(&a, positional); err != nil {
+		fmt.Fprintln(stderr, "error:", errorMessage(err))
+		var usageErr usageError
+		if errors.As(err, &usageErr) {
+			fs.Usage()
+		}
+	}
+}
+
+// open shows the entity an IRI of the last entity points to; without a field it lists the IRIs
+func (s *session) open(args []string) {
+	if s.current == nil {
+		fmt.Fprintln(stderr, "error: nothing to open, show an entity first, e.g. \"classes get ID\"")
+		return
+	}
+	if len(args) == 0 {
+		links := links(s.current)
+		for _, field := range sortedKeys(links) {
+			fmt.Fprintf(stdout, "%-24s %s\n", field, strings.Join(links[field], ", "))
+		}
+		return
+	}
+	iris, ok := links(s.current)[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "error: %s is not an IRI of the current entity\n", args[0])
+		return
+	}
+	index := 1
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 || n > len(iris) {
+			fmt.Fprintf(stderr, "error: %s has %d IRIs, expected a number between 1 and %d\n", args[0], len(iris), len(iris))
+			return
+		}
+		index = n
+	} else if len(iris) > 1 {
+		fmt.Fprintf(stderr, "error: %s has %d IRIs, pick one with \"open %s N\"\n", args[0], len(iris), args[0])
+		return
+	}
+	iri := iris[index-1]
+	resource, ok := xplorentities.ResourceFromPath(iri)
+	name, known := commandFor(resource)
+	if !ok || !known {
+		fmt.Fprintf(stderr, "error: no command shows %s\n", iri)
+		return
+	}
+	if _, ok := commands[name]["get"]; !ok {
+		fmt.Fprintf(stderr, "error: %s cannot be shown one by one\n", name)
+		return
+	}
+	s.run([]string{name, "get", xplorentities.ShortID(iri)})
+}
+
+// links returns the fields of an entity holding IRIs, directly or as embedded objects
+func links(entity map[string]any) map[string][]string {
+	found := make(map[string][]string)
+	var iriOf func(value any) (string, bool)
+	iriOf = func(value any) (string, bool) {
+		switch v := value.(type) {
+		case string:
+			_, ok := xplorentities.ResourceFromPath(v)
+			return v, ok && strings.HasPrefix(v, "/")
+		case map[string]any:
+			return iriOf(v["@id"])
+		}
+		return "", false
+	}
+	for field, value := range entity {
+		if strings.HasPrefix(field, "@") {
+			continue
+		}
+		if iri, ok := iriOf(value); ok {
+			found[field] = []string{iri}
+			continue
+		}
+		if list, ok := value.([]any); ok {
+			for _, item := range list {
+				if iri, ok := iriOf(item); ok {
+					found[field] = append(found[field], iri)
+				}
+			}
+		}
+	}
+	return found
+}
+
+// record remembers the IDs of the items a command showed, and the IRIs they hold, for completion and open
+func record[T any](a *app, items []T) {
+	if a.session == nil {
+		return
+	}
+	var last map[string]any
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			continue
+		}
+		var fields map[string]any
+		if json.Unmarshal(data, &fields) != nil {
+			continue
+		}
+		if id, ok := fields["@id"].(string); ok {
+			a.session.remember(a.resource, xplorentities.ShortID(id))
+		}
+		for _, iris := range links(fields) {
+			for _, iri := range iris {
+				resource, _ := xplorentities.ResourceFromPath(iri)
+				if name, ok := commandFor(resource); ok {
+					a.session.remember(name, xplorentities.ShortID(iri))
+				}
+			}
+		}
+		last = fields
+	}
+	if len(items) == 1 {
+		a.session.current = last
+	}
+}
+
+func (s *session) remember(resource, id string) {
+	if id == "" {
+		return
+	}
+	ids := []string{id}
+	for _, seen := range s.recent[resource] {
+		if seen != id && len(ids) < maxRecentIDs {
+			ids = append(ids, seen)
+		}
+	}
+	s.recent[resource] = ids
+}
+
+func (s *session) printRecent(args []string) {
+	resources := sortedKeys(s.recent)
+	if len(args) > 0 {
+		resources = args
+	}
+	for _, resource := range resources {
+		fmt.Fprintf(stdout, "%-14s %s\n", resource, strings.Join(s.recent[resource], " "))
+	}
+}
+
+// candidates returns the completions of the current word after words
+func (s *session) candidates(words []string, current string) []string {
+	if len(words) == 0 {
+		return append(sortedKeys(commands), sortedKeys(builtins)...)
+	}
+	switch words[0] {
+	case "open":
+		if len(words) == 1 && s.current != nil {
+			return sortedKeys(links(s.current))
+		}
+		return nil
+	case "output":
+		return []string{formatCSV, formatJSON, formatTable}
+	case "recent":
+		return sortedKeys(s.recent)
+	}
+	actions, ok := commands[words[0]]
+	if !ok {
+		return nil
+	}
+	if len(words) == 1 {
+		return sortedKeys(actions)
+	}
+	cmd, ok := actions[words[1]]
+	if !ok {
+		return nil
+	}
+	opts := s.defaults
+	fs, _ := prepare(words[0], words[1], cmd, &opts)
+
+	if strings.HasPrefix(current, "-") {
+		dashes := "-"
+		if strings.HasPrefix(current, "--") {
+			dashes = "--"
+		}
+		var names []string
+		fs.VisitAll(func(f *flag.Flag) { names = append(names, dashes+f.Name) })
+		return names
+	}
+	if previous := words[len(words)-1]; strings.HasPrefix(previous, "-") && !strings.Contains(previous, "=") {
+		name := strings.TrimLeft(previous, "-")
+		if f := fs.Lookup(name); f != nil && !isBoolFlag(f) {
+			switch {
+			case name == "o":
+				return []string{formatCSV, formatJSON, formatTable}
+			case flagResources[name] != "":
+				return s.recent[flagResources[name]]
+			}
+			return nil
+		}
+	}
+	if cmd.args != "" {
+		return s.recent[words[0]]
+	}
+	return nil
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
+
+func (s *session) help() {
+	fmt.Fprintln(stdout, "Commands:")
+	for _, resource := range sortedKeys(commands) {
+		for _, action := range sortedKeys(commands[resource]) {
+			cmd := commands[resource][action]
+			fmt.Fprintf(stdout, "  %-26s %s\n", strings.TrimSpace(resource+" "+action+" [flags] "+cmd.args), cmd.summary)
+		}
+	}
+	fmt.Fprintln(stdout, "\nShell commands:")
+	for _, name := range sortedKeys(builtins) {
+		fmt.Fprintln(stdout, "  "+builtins[name])
+	}
+	fmt.Fprintln(stdout, "\nAppend -h to a command for its flags. Tab completes commands, flags and the IDs seen in the session.")
+}
+
+func (s *session) loadHistory() {
+	data, err := os.ReadFile(s.historyPath)
+	if err != nil {
+		return
+	}
+	s.history = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(s.history) > maxHistory {
+		s.history = s.history[len(s.history)-maxHistory:]
+	}
+}
+
+// addHistory keeps line in memory and in the history file; a file that cannot be written is ignored
+func (s *session) addHistory(line string) {
+	if len(s.history) > 0 && s.history[len(s.history)-1] == line {
+		return
+	}
+	s.history = append(s.history, line)
+	if s.historyPath == "" {
+		return
+	}
+	if len(s.history) > maxHistory {
+		s.history = s.history[len(s.history)-maxHistory:]
+		os.WriteFile(s.historyPath, []byte(strings.Join(s.history, "\n")+"\n"), 0o600)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(s.historyPath), 0o700); err != nil {
+		return
+	}
+	file, err := os.OpenFile(s.historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintln(file, line)
+}
+
+// commandFor returns the CLI resource of an API collection
+func commandFor(resource xplorentities.Resource) (string, bool) {
+	for name, r := range commandResources {
+		if r == resource {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// splitWords splits a line on spaces, keeping quoted words together
+func splitWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	var quote rune
+	inWord := false
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
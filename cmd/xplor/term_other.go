@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package main
+
+import "errors"
+
+// makeRaw is not supported on this platform; the shell reads plain lines without completion
+func makeRaw(fd int) (func() error, error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
+
+func isTerminal(fd int) bool {
+	return false
+}
@@ -0,0 +1,39 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// makeRaw switches the terminal fd to raw input, keeping output processing, and returns the function restoring it.
+// It fails when fd is not a terminal.
+func makeRaw(fd int) (func() error, error) {
+	var saved syscall.Termios
+	if err := termios(fd, ioctlGetTermios, &saved); err != nil {
+		return nil, err
+	}
+	raw := saved
+	raw.Iflag &^= syscall.ICRNL | syscall.INLCR | syscall.IXON | syscall.ISTRIP
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := termios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	return func() error { return termios(fd, ioctlSetTermios, &saved) }, nil
+}
+
+// isTerminal reports whether fd is a terminal
+func isTerminal(fd int) bool {
+	var t syscall.Termios
+	return termios(fd, ioctlGetTermios, &t) == nil
+}
+
+func termios(fd int, request uintptr, t *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), request, uintptr(unsafe.Pointer(t))); errno != 0 {
+		return errno
+	}
+	return nil
+}
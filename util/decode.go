@@ -0,0 +1,346 @@
+package util
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DecodeMode selects how response bodies are unmarshalled
+type DecodeMode int
+
+const (
+	// DecodeStandard fails on the first type mismatch and ignores unknown fields
+	DecodeStandard DecodeMode = iota
+	// DecodeLenient leaves mismatched values at their zero value and reports them as warnings
+	DecodeLenient
+	// DecodeStrict fails on type mismatches and on fields the entities do not declare,
+	// so tests detect API schema drift early. Undeclared JSON-LD keywords (@type) and Hydra terms (hydra:search) are ignored.
+	DecodeStrict
+)
+
+// DecodeWarningKind classifies a decode warning
+type DecodeWarningKind string
+
+const (
+	WarningTypeMismatch DecodeWarningKind = "type_mismatch"
+	WarningUnknownField DecodeWarningKind = "unknown_field"
+)
+
+// DecodeWarning reports a value that did not fit the entity it was decoded into
+type DecodeWarning struct {
+	Kind     DecodeWarningKind
+	Path     string // JSON path of the value, e.g. hydra:member[3].onlineLimit
+	Expected string // Go type of the field; empty for unknown fields
+	Got      string // JSON type of the value: string, number, bool, object, array or null
+}
+
+func (w DecodeWarning) String() string {
+	if w.Kind == WarningUnknownField {
+		return "unknown field " + w.Path + " (" + w.Got + ")"
+	}
+	return "type mismatch at " + w.Path + ": expected " + w.Expected + ", got " + w.Got
+}
+
+// Unmarshal decodes data into v, which must be a non-nil pointer, according to mode.
+// In lenient mode the warnings list the values left at their zero value and only syntax errors fail.
+// In strict mode the warnings list the unknown fields, which are also reported as an error.
+func Unmarshal(data []byte, v any, mode DecodeMode) ([]DecodeWarning, error) {
+	err := json.Unmarshal(data, v)
+	switch mode {
+	case DecodeLenient:
+		var syntaxErr *json.SyntaxError
+		if err == nil || errors.As(err, &syntaxErr) {
+			return nil, err
+		}
+		target := reflect.ValueOf(v)
+		if target.Kind() != reflect.Pointer || target.IsNil() {
+			return nil, err
+		}
+		var warnings []DecodeWarning
+		decodeLenient(data, target.Elem(), "", &warnings)
+		return warnings, nil
+	case DecodeStrict:
+		if err != nil {
+			return nil, err
+		}
+		var warnings []DecodeWarning
+		findUnknownFields(data, reflect.TypeOf(v), "", &warnings)
+		if len(warnings) > 0 {
+			paths := make([]string, len(warnings))
+			for i, warning := range warnings {
+				paths[i] = warning.Path
+			}
+			return warnings, errors.New("unknown fields: " + strings.Join(paths, ", "))
+		}
+		return nil, nil
+	}
+	return nil, err
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// decodesItself reports whether values of t are decoded as a whole rather than field by field
+func decodesItself(t reflect.Type) bool {
+	if t.Kind() == reflect.Interface {
+		return true
+	}
+	pointer := reflect.PointerTo(t)
+	return t.Implements(jsonUnmarshalerType) || pointer.Implements(jsonUnmarshalerType) ||
+		t.Implements(textUnmarshalerType) || pointer.Implements(textUnmarshalerType)
+}
+
+// decodeLenient decodes data into v, descending into objects and arrays to isolate the values that don't fit
+func decodeLenient(data []byte, v reflect.Value, path string, warnings *[]DecodeWarning) {
+	if json.Unmarshal(data, v.Addr().Interface()) == nil {
+		return
+	}
+	t := v.Type()
+	mismatch := func() {
+		v.Set(reflect.Zero(t))
+		*warnings = append(*warnings, DecodeWarning{Kind: WarningTypeMismatch, Path: path, Expected: t.String(), Got: jsonType(data)})
+	}
+	if decodesItself(t) {
+		mismatch()
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(t.Elem())
+		decodeLenient(data, elem.Elem(), path, warnings)
+		v.Set(elem)
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(data, &object) != nil {
+			mismatch()
+			return
+		}
+		v.Set(reflect.Zero(t))
+		fields := jsonFields(t)
+		for _, key := range sortedKeys(object) {
+			field, ok := fields.lookup(key)
+			if !ok {
+				continue
+			}
+			if fieldValue, ok := settableField(v, field.index); ok {
+				decodeLenient(object[key], fieldValue, joinPath(path, key), warnings)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			mismatch()
+			return
+		}
+		if t.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(t, len(items), len(items)))
+		} else {
+			v.Set(reflect.Zero(t))
+		}
+		for i := 0; i < len(items) && i < v.Len(); i++ {
+			decodeLenient(items[i], v.Index(i), path+"["+strconv.Itoa(i)+"]", warnings)
+		}
+	case reflect.Map:
+		var object map[string]json.RawMessage
+		if t.Key().Kind() != reflect.String || json.Unmarshal(data, &object) != nil {
+			mismatch()
+			return
+		}
+		decoded := reflect.MakeMapWithSize(t, len(object))
+		for _, key := range sortedKeys(object) {
+			elem := reflect.New(t.Elem()).Elem()
+			decodeLenient(object[key], elem, joinPath(path, key), warnings)
+			decoded.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), elem)
+		}
+		v.Set(decoded)
+	default:
+		mismatch()
+	}
+}
+
+// findUnknownFields lists the object keys of data that t does not declare
+func findUnknownFields(data []byte, t reflect.Type, path string, warnings *[]DecodeWarning) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if decodesItself(t) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(data, &object) != nil {
+			return
+		}
+		fields := jsonFields(t)
+		for _, key := range sortedKeys(object) {
+			field, ok := fields.lookup(key)
+			if !ok {
+				if !strings.HasPrefix(key, "@") && !strings.HasPrefix(key, "hydra:") {
+					*warnings = append(*warnings, DecodeWarning{Kind: WarningUnknownField, Path: joinPath(path, key), Got: jsonType(object[key])})
+				}
+				continue
+			}
+			findUnknownFields(object[key], field.typ, joinPath(path, key), warnings)
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return
+		}
+		for i, item := range items {
+			findUnknownFields(item, t.Elem(), path+"["+strconv.Itoa(i)+"]", warnings)
+		}
+	case reflect.Map:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(data, &object) != nil {
+			return
+		}
+		for _, key := range sortedKeys(object) {
+			findUnknownFields(object[key], t.Elem(), joinPath(path, key), warnings)
+		}
+	}
+}
+
+type jsonField struct {
+	name  string
+	index []int
+	typ   reflect.Type
+}
+
+type jsonFieldSet []jsonField
+
+// lookup matches a key like encoding/json: exact name first, then case-insensitively
+func (fs jsonFieldSet) lookup(key string) (jsonField, bool) {
+	for _, field := range fs {
+		if field.name == key {
+			return field, true
+		}
+	}
+	for _, field := range fs {
+		if strings.EqualFold(field.name, key) {
+			return field, true
+		}
+	}
+	return jsonField{}, false
+}
+
+// jsonFields lists the JSON fields of a struct type, promoting untagged embedded structs;
+// shallower fields hide deeper ones of the same name
+func jsonFields(t reflect.Type) jsonFieldSet {
+	var fields jsonFieldSet
+	seen := make(map[string]bool)
+	level := []jsonField{{typ: t}}
+	for len(level) > 0 {
+		var next []jsonField
+		var current jsonFieldSet
+		for _, parent := range level {
+			for i := 0; i < parent.typ.NumField(); i++ {
+				sf := parent.typ.Field(i)
+				index := append(append([]int(nil), parent.index...), i)
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, _, _ := strings.Cut(tag, ",")
+				fieldType := sf.Type
+				if sf.Anonymous && name == "" {
+					for fieldType.Kind() == reflect.Pointer {
+						fieldType = fieldType.Elem()
+					}
+					if fieldType.Kind() == reflect.Struct {
+						next = append(next, jsonField{index: index, typ: fieldType})
+						continue
+					}
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				if name == "" {
+					name = sf.Name
+				}
+				current = append(current, jsonField{name: name, index: index, typ: sf.Type})
+			}
+		}
+		for _, field := range current {
+			if !seen[field.name] {
+				seen[field.name] = true
+				fields = append(fields, field)
+			}
+		}
+		level = next
+	}
+	return fields
+}
+
+// settableField returns the field at index, allocating embedded struct pointers on the way
+func settableField(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, position := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(position)
+	}
+	return v, v.CanSet()
+}
+
+func sortedKeys(object map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonType names the JSON type of a raw value
+func jsonType(data []byte) string {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return "empty"
+	}
+	switch data[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	}
+	return "number"
+}
+
+// FormatWarnings joins warnings into a single line
+func FormatWarnings(warnings []DecodeWarning) string {
+	parts := make([]string, len(warnings))
+	for i, warning := range warnings {
+		parts[i] = warning.String()
+	}
+	return strings.Join(parts, "; ")
+}
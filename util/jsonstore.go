@@ -0,0 +1,87 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONStore keeps JSON documents by key, in memory or as files of a directory.
+// It backs the cursor and snapshot stores of the sync and watch packages.
+type JSONStore struct {
+	dir  string
+	mu   sync.Mutex
+	docs map[string][]byte
+}
+
+// NewMemoryJSONStore returns an empty JSONStore held in memory
+func NewMemoryJSONStore() *JSONStore {
+	return &JSONStore{docs: make(map[string][]byte)}
+}
+
+// NewFileJSONStore returns a JSONStore keeping each document in dir/<key>.json, created when missing.
+// Keys must be valid file names; files are replaced atomically on save.
+func NewFileJSONStore(dir string) (*JSONStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &JSONStore{dir: dir}, nil
+}
+
+// Load decodes the document of key into target and reports whether there was one
+func (s *JSONStore) Load(key string, target any) (bool, error) {
+	var data []byte
+	if s.docs != nil {
+		s.mu.Lock()
+		stored, ok := s.docs[key]
+		s.mu.Unlock()
+		if !ok {
+			return false, nil
+		}
+		data = stored
+	} else {
+		read, err := os.ReadFile(s.path(key))
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		data = read
+	}
+	return true, json.Unmarshal(data, target)
+}
+
+// Save stores value as the document of key
+func (s *JSONStore) Save(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if s.docs != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.docs[key] = data
+		return nil
+	}
+	file, err := os.CreateTemp(s.dir, "."+key+"-*")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), s.path(key))
+}
+
+func (s *JSONStore) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
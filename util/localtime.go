@@ -0,0 +1,211 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	naiveDateTimeLayout = "2006-01-02T15:04:05"
+	naiveDateLayout     = "2006-01-02"
+)
+
+// LocalTime handles API datetime values.
+// Values sent with a UTC offset (RFC3339) keep that offset and denote an exact instant.
+// Naive values (without offset) are kept as wall-clock readings stored in UTC;
+// use InLocation to turn them into instants for the club's time zone.
+type LocalTime struct {
+	time.Time
+	hasOffset bool
+}
+
+// NewLocalTime wraps a time.Time that denotes an exact instant
+func NewLocalTime(t time.Time) LocalTime {
+	return LocalTime{Time: t, hasOffset: true}
+}
+
+// parseAPITime parses API date/datetime strings.
+// RFC3339 values keep their offset; naive values are parsed as UTC wall-clock readings.
+// The boolean result reports whether the value carried an offset.
+func parseAPITime(s string, dateTimeLayouts []string, dateLayouts []string) (time.Time, bool, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05Z0700", "2006-01-02 15:04:05Z07:00"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true, nil
+		}
+	}
+
+	var lastErr error
+	for _, layout := range dateTimeLayouts {
+		// Parse naive datetime directly in UTC location (no conversion)
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, false, nil
+		}
+		lastErr = err
+	}
+
+	for _, layout := range dateLayouts {
+		// Parse naive date directly in UTC location (no conversion)
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, false, nil
+		}
+		lastErr = err
+	}
+
+	return time.Time{}, false, lastErr
+}
+
+func (lt *LocalTime) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		return nil
+	}
+	t, hasOffset, err := parseAPITime(
+		s,
+		[]string{naiveDateTimeLayout, "2006-01-02 15:04:05", "2006-01-02T15:04:05.999999999"},
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	lt.Time = t
+	lt.hasOffset = hasOffset
+	return nil
+}
+
+func (lt LocalTime) MarshalJSON() ([]byte, error) {
+	if lt.hasOffset {
+		return json.Marshal(lt.Format(time.RFC3339))
+	}
+	// Keep API-compatible datetime format without timezone suffix.
+	return json.Marshal(lt.Format(naiveDateTimeLayout))
+}
+
+// HasOffset reports whether the API sent the value with a UTC offset
+func (lt LocalTime) HasOffset() bool {
+	return lt.hasOffset
+}
+
+// Offset returns the original UTC offset in seconds and whether one was present
+func (lt LocalTime) Offset() (int, bool) {
+	if !lt.hasOffset {
+		return 0, false
+	}
+	_, offset := lt.Zone()
+	return offset, true
+}
+
+// InLocation returns the instant denoted by the value in loc.
+// Values with an offset are converted to loc. Naive values are read as wall-clock time in loc:
+// a time skipped by a DST transition is moved forward by the length of the gap,
+// and an ambiguous time repeated by a DST transition resolves to its first occurrence.
+func (lt LocalTime) InLocation(loc *time.Location) time.Time {
+	if lt.IsZero() {
+		return time.Time{}
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	if lt.hasOffset {
+		return lt.In(loc)
+	}
+	return WallClockIn(lt.Time, loc)
+}
+
+// InstantUTC returns the instant of value in UTC, reading naive values in loc (nil uses UTC); zero when value is nil
+func InstantUTC(value *LocalTime, loc *time.Location) time.Time {
+	if value == nil {
+		return time.Time{}
+	}
+	return value.InLocation(loc).UTC()
+}
+
+// ParseLocalTime parses a datetime the API sends as a plain string, nil when it is empty or invalid
+func ParseLocalTime(value string) *LocalTime {
+	var parsed LocalTime
+	if value == "" || parsed.UnmarshalJSON([]byte(strconv.Quote(value))) != nil {
+		return nil
+	}
+	return &parsed
+}
+
+// WallClockIn interprets the date and clock reading of t (ignoring its location) as a time in loc.
+// Non-existent times (DST gap) move forward by the gap; ambiguous times (DST overlap) pick the earlier instant.
+func WallClockIn(t time.Time, loc *time.Location) time.Time {
+	naive := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+
+	// The offsets in effect around the reading are the only candidates
+	_, before := naive.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := naive.Add(24 * time.Hour).In(loc).Zone()
+
+	var matches []time.Time
+	for _, offset := range []int{before, after} {
+		candidate := naive.Add(-time.Duration(offset) * time.Second)
+		if _, actual := candidate.In(loc).Zone(); actual == offset {
+			matches = append(matches, candidate.In(loc))
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		// Gap: keep the offset in effect before the transition, which lands after it
+		return naive.Add(-time.Duration(before) * time.Second).In(loc)
+	case len(matches) == 2 && matches[1].Before(matches[0]):
+		return matches[1]
+	default:
+		return matches[0]
+	}
+}
+
+// LocalDate handles API date values as calendar dates without time zone.
+// The date components are kept as parsed (stored at midnight UTC); datetime inputs keep only their date.
+type LocalDate struct {
+	time.Time
+}
+
+func (ld *LocalDate) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		return nil
+	}
+	t, _, err := parseAPITime(
+		s,
+		[]string{naiveDateTimeLayout, "2006-01-02 15:04:05"},
+		[]string{naiveDateLayout},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to parse date: %s", s)
+	}
+	ld.Time = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return nil
+}
+
+func (ld LocalDate) MarshalJSON() ([]byte, error) {
+	// Keep API-compatible date format.
+	return json.Marshal(ld.Format(naiveDateLayout))
+}
+
+// StartIn returns the first instant of the date in loc
+func (ld LocalDate) StartIn(loc *time.Location) time.Time {
+	if ld.IsZero() {
+		return time.Time{}
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return WallClockIn(ld.Time, loc)
+}
+
+// EndIn returns the first instant of the following day in loc (exclusive end of the date)
+func (ld LocalDate) EndIn(loc *time.Location) time.Time {
+	if ld.IsZero() {
+		return time.Time{}
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return WallClockIn(ld.AddDate(0, 0, 1), loc)
+}
@@ -0,0 +1,148 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redacted replaces sensitive values in logs and debug output
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys lists normalized field, query and form names whose values are never logged
+var sensitiveKeys = map[string]bool{
+	"accesstoken":   true,
+	"authorization": true,
+	"clientsecret":  true,
+	"password":      true,
+	"refreshtoken":  true,
+	"email":         true,
+	"emails":        true,
+	"contactemails": true,
+	"mobile":        true,
+	"phone":         true,
+	"phonenumber":   true,
+	"iban":          true,
+	"bic":           true,
+}
+
+// sensitivePrefixes lists normalized name prefixes whose values are never logged, e.g. nationalIdDocumentId
+var sensitivePrefixes = []string{"nationalid"}
+
+// sensitiveHeaders lists the headers whose values are never logged
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// IsSensitiveKey reports whether values stored under key must be redacted.
+// Keys are compared without case, separators or array suffix, and only their last dotted segment counts
+// (e.g. "contact.email[]" and "client_secret" are sensitive); national ID keys match on their prefix.
+func IsSensitiveKey(key string) bool {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		key = key[i+1:]
+	}
+	key = strings.TrimSuffix(key, "[]")
+	key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	if sensitiveKeys[key] {
+		return true
+	}
+	for _, prefix := range sensitivePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactHeader returns a copy of header with credentials replaced
+func RedactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if len(redacted.Values(name)) > 0 {
+			redacted.Set(name, Redacted)
+		}
+	}
+	return redacted
+}
+
+// RedactValues returns a copy of query or form values with sensitive entries replaced
+func RedactValues(values url.Values) url.Values {
+	redacted := make(url.Values, len(values))
+	for key, vs := range values {
+		if IsSensitiveKey(key) {
+			redacted[key] = []string{Redacted}
+			continue
+		}
+		redacted[key] = append([]string(nil), vs...)
+	}
+	return redacted
+}
+
+// RedactURL returns the URL as a string with sensitive query parameters replaced
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	clean := *u
+	clean.RawQuery = RedactValues(u.Query()).Encode()
+	return clean.String()
+}
+
+// RedactBody removes sensitive values from a JSON or form-encoded body.
+// Other bodies only have e-mail addresses masked.
+func RedactBody(body []byte) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return body
+	}
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		var document any
+		if err := json.Unmarshal(trimmed, &document); err == nil {
+			if redacted, err := json.Marshal(redactJSON(document)); err == nil {
+				return redacted
+			}
+		}
+	}
+	if values, err := url.ParseQuery(string(trimmed)); err == nil && bytes.Contains(trimmed, []byte("=")) && !bytes.ContainsAny(trimmed, " \n{") {
+		return []byte(RedactValues(values).Encode())
+	}
+	return emailPattern.ReplaceAll(body, []byte(Redacted))
+}
+
+func redactJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if IsSensitiveKey(key) && child != nil {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redactJSON(child)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = redactJSON(child)
+		}
+		return v
+	case string:
+		return emailPattern.ReplaceAllString(v, Redacted)
+	}
+	return value
+}
+
+// TruncateBody returns body as a string of at most limit bytes, marking cut content
+func TruncateBody(body []byte, limit int) string {
+	if limit <= 0 || len(body) <= limit {
+		return string(body)
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return string(body[:cut]) + "...(truncated)"
+}
@@ -0,0 +1,71 @@
+package util_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+func TestRedactContactBody(t *testing.T) {
+	iri := "/enjoy/contacts/42"
+	mobile := "+34600111222"
+	document := "/enjoy/national_id_documents/7"
+	contact := xplorentities.XPlorContact{
+		ID:                   &iri,
+		GivenName:            "Jane",
+		Email:                "jane@example.com",
+		Mobile:               &mobile,
+		NationalID:           "12345678Z",
+		NationalIdDocumentID: &document,
+		Address:              xplorentities.XPlorAddress{Locality: "Madrid"},
+	}
+	body, err := json.Marshal(xplorentities.XPlorContacts{Collection: xplorentities.Collection[xplorentities.XPlorContact]{
+		Members: []xplorentities.XPlorContact{contact},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redacted := string(util.RedactBody(body))
+	for _, secret := range []string{"jane@example.com", "+34600111222", "12345678Z", "national_id_documents"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("redacted body still contains %q: %s", secret, redacted)
+		}
+	}
+
+	var page struct {
+		Members []map[string]any `json:"hydra:member"`
+	}
+	if err := json.Unmarshal([]byte(redacted), &page); err != nil {
+		t.Fatal(err)
+	}
+	member := page.Members[0]
+	for _, field := range []string{"email", "mobile", "nationalId", "nationalIdDocumentId"} {
+		if member[field] != util.Redacted {
+			t.Errorf("%s = %v, want %s", field, member[field], util.Redacted)
+		}
+	}
+	if member["givenName"] != "Jane" || member["@id"] != iri {
+		t.Errorf("non-sensitive fields changed: givenName %v, @id %v", member["givenName"], member["@id"])
+	}
+}
+
+func TestIsSensitiveKey(t *testing.T) {
+	tests := map[string]bool{
+		"client_secret":        true,
+		"contact.email[]":      true,
+		"nationalId":           true,
+		"nationalIdDocumentId": true,
+		"national_id_number":   true,
+		"givenName":            false,
+		"nation":               false,
+	}
+	for key, want := range tests {
+		if got := util.IsSensitiveKey(key); got != want {
+			t.Errorf("IsSensitiveKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
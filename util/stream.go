@@ -0,0 +1,125 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrBodyTooLarge is returned when a response body exceeds the configured maximum size
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// LimitBody returns a reader failing with ErrBodyTooLarge once more than max bytes are read.
+// A max of zero or less means no limit.
+func LimitBody(r io.Reader, max int64) io.Reader {
+	if max <= 0 {
+		return r
+	}
+	return &limitedBody{reader: r, remaining: max, max: max}
+}
+
+type limitedBody struct {
+	reader    io.Reader
+	remaining int64
+	max       int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, fmt.Errorf("%w: more than %d bytes", ErrBodyTooLarge, l.max)
+	}
+	// Read one byte past the limit to tell a body of exactly max bytes from a larger one
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.reader.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), fmt.Errorf("%w: more than %d bytes", ErrBodyTooLarge, l.max)
+	}
+	return n, err
+}
+
+// MemberError reports a collection member that could not be decoded
+type MemberError struct {
+	Index int    // Position of the member in the page
+	ID    string // @id of the member when it could be read
+	Err   error
+}
+
+func (e MemberError) Error() string {
+	if e.ID != "" {
+		return fmt.Sprintf("member %d (%s): %v", e.Index, e.ID, e.Err)
+	}
+	return fmt.Sprintf("member %d: %v", e.Index, e.Err)
+}
+
+func (e MemberError) Unwrap() error {
+	return e.Err
+}
+
+// StreamMembers reads a JSON object from r, passing each element of its field array to yield
+// without holding the whole document in memory. The other top-level fields are returned as-is.
+// Returning false from yield stops decoding; the fields read so far are returned.
+// Syntax errors abort decoding since the rest of the document cannot be located.
+func StreamMembers(r io.Reader, field string, yield func(index int, member json.RawMessage) bool) (map[string]json.RawMessage, error) {
+	decoder := json.NewDecoder(r)
+	fields := make(map[string]json.RawMessage)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return nil, err
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return fields, err
+		}
+		key, _ := token.(string)
+		if key != field {
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				return fields, err
+			}
+			fields[key] = value
+			continue
+		}
+
+		token, err = decoder.Token()
+		if err != nil {
+			return fields, err
+		}
+		if token == nil {
+			continue
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return fields, fmt.Errorf("expected %s to be an array", field)
+		}
+		for index := 0; decoder.More(); index++ {
+			var member json.RawMessage
+			if err := decoder.Decode(&member); err != nil {
+				return fields, err
+			}
+			if !yield(index, member) {
+				return fields, nil
+			}
+		}
+		if _, err := decoder.Token(); err != nil {
+			return fields, err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return fields, err
+	}
+	return fields, nil
+}
+
+func expectDelim(decoder *json.Decoder, want json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %q at the start of the response", want)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// countryTimezones maps ISO 3166-1 alpha-2 country codes to an IANA time zone.
+// Countries spanning several zones map to the zone of their capital.
+var countryTimezones = map[string]string{
+	"AD": "Europe/Andorra",
+	"AE": "Asia/Dubai",
+	"AR": "America/Argentina/Buenos_Aires",
+	"AT": "Europe/Vienna",
+	"AU": "Australia/Sydney",
+	"BE": "Europe/Brussels",
+	"BG": "Europe/Sofia",
+	"BR": "America/Sao_Paulo",
+	"CA": "America/Toronto",
+	"CH": "Europe/Zurich",
+	"CI": "Africa/Abidjan",
+	"CL": "America/Santiago",
+	"CM": "Africa/Douala",
+	"CO": "America/Bogota",
+	"CY": "Asia/Nicosia",
+	"CZ": "Europe/Prague",
+	"DE": "Europe/Berlin",
+	"DK": "Europe/Copenhagen",
+	"DZ": "Africa/Algiers",
+	"EE": "Europe/Tallinn",
+	"EG": "Africa/Cairo",
+	"ES": "Europe/Madrid",
+	"FI": "Europe/Helsinki",
+	"FR": "Europe/Paris",
+	"GB": "Europe/London",
+	"GF": "America/Cayenne",
+	"GP": "America/Guadeloupe",
+	"GR": "Europe/Athens",
+	"HR": "Europe/Zagreb",
+	"HU": "Europe/Budapest",
+	"IE": "Europe/Dublin",
+	"IL": "Asia/Jerusalem",
+	"IT": "Europe/Rome",
+	"JP": "Asia/Tokyo",
+	"LI": "Europe/Vaduz",
+	"LT": "Europe/Vilnius",
+	"LU": "Europe/Luxembourg",
+	"LV": "Europe/Riga",
+	"MA": "Africa/Casablanca",
+	"MC": "Europe/Monaco",
+	"MQ": "America/Martinique",
+	"MT": "Europe/Malta",
+	"MU": "Indian/Mauritius",
+	"MX": "America/Mexico_City",
+	"NC": "Pacific/Noumea",
+	"NL": "Europe/Amsterdam",
+	"NO": "Europe/Oslo",
+	"NZ": "Pacific/Auckland",
+	"PF": "Pacific/Tahiti",
+	"PL": "Europe/Warsaw",
+	"PM": "America/Miquelon",
+	"PT": "Europe/Lisbon",
+	"RE": "Indian/Reunion",
+	"RO": "Europe/Bucharest",
+	"RS": "Europe/Belgrade",
+	"SA": "Asia/Riyadh",
+	"SE": "Europe/Stockholm",
+	"SG": "Asia/Singapore",
+	"SI": "Europe/Ljubljana",
+	"SK": "Europe/Bratislava",
+	"SN": "Africa/Dakar",
+	"TN": "Africa/Tunis",
+	"TR": "Europe/Istanbul",
+	"UA": "Europe/Kyiv",
+	"US": "America/New_York",
+	"WF": "Pacific/Wallis",
+	"YT": "Indian/Mayotte",
+	"ZA": "Africa/Johannesburg",
+}
+
+// TimezoneForCountry returns the IANA time zone name for an ISO 3166-1 alpha-2 country code
+func TimezoneForCountry(countryIso string) (string, bool) {
+	name, ok := countryTimezones[strings.ToUpper(strings.TrimSpace(countryIso))]
+	return name, ok
+}
+
+// LocationForCountry loads the time zone of a country.
+// Loading requires the system tz database or an import of time/tzdata.
+func LocationForCountry(countryIso string) (*time.Location, error) {
+	name, ok := TimezoneForCountry(countryIso)
+	if !ok {
+		return nil, fmt.Errorf("no time zone known for country %q", countryIso)
+	}
+	return time.LoadLocation(name)
+}
+
+// LocationForLocale loads the time zone of the region of a locale such as "fr_FR" or "es-ES"
+func LocationForLocale(locale string) (*time.Location, error) {
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '_' || r == '-' })
+	for i := len(parts) - 1; i > 0; i-- {
+		if len(parts[i]) == 2 {
+			return LocationForCountry(parts[i])
+		}
+	}
+	return nil, fmt.Errorf("locale %q has no region", locale)
+}
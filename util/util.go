@@ -3,15 +3,13 @@ package util
 import (
 	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"os"
 	"sort"
 	"strings"
-	"time"
 )
 
 // ErrorResponse represents an error response from the API
@@ -20,165 +18,174 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
-// LocalTime handles API datetime values as naive timestamps (without timezone).
-// All timestamps are returned as-is from the API, allowing timezone handling
-// to be managed at the application level.
-type LocalTime struct {
-	time.Time
+// RequestResult encapsulates the possible outcomes of an API request
+type RequestResult[T any] struct {
+	Response T
+	Error    *ErrorResponse
+	// Warnings lists the values skipped by lenient decoding or the unknown fields found by strict decoding
+	Warnings []DecodeWarning
 }
 
-// parseTimeAsNaive parses API date/datetime strings and returns naive timestamps.
-// RFC3339 values with timezone info have the timezone stripped, keeping only the date/time components.
-// Naive values are parsed directly without timezone assumptions.
-func parseTimeAsNaive(s string, dateTimeLayouts []string, dateLayouts []string) (time.Time, error) {
-	// If payload includes timezone info (RFC3339), strip it and use only date/time components
-	if t, err := time.Parse(time.RFC3339, s); err == nil {
-		// Return as naive: extract the date/time components without timezone
-		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC), nil
-	}
-
-	var lastErr error
-	for _, layout := range dateTimeLayouts {
-		// Parse naive datetime directly in UTC location (no conversion)
-		t, err := time.Parse(layout, s)
-		if err == nil {
-			return t, nil
-		}
-		lastErr = err
-	}
-
-	for _, layout := range dateLayouts {
-		// Parse naive date directly in UTC location (no conversion)
-		t, err := time.Parse(layout, s)
-		if err == nil {
-			return t, nil
+// ExecuteRequest handles common HTTP request execution pattern including error handling and response processing
+// It takes a context, http client, request, debug flag, and returns a typed RequestResult
+func ExecuteRequest[T any](ctx context.Context, client *http.Client, request *http.Request, debug bool) RequestResult[T] {
+	bodyBytes, errResp := FetchResponse(client, request, debug)
+	if errResp != nil {
+		var zero T
+		return RequestResult[T]{
+			Response: zero,
+			Error:    errResp,
 		}
-		lastErr = err
 	}
+	return DecodeResponse[T](bodyBytes)
+}
 
-	return time.Time{}, lastErr
+// RawResponse is the status, headers and body of a completed HTTP exchange
+type RawResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Stream is set instead of Body by OpenRawResponse on success; the caller must close it
+	Stream io.ReadCloser
 }
 
-func (lt *LocalTime) UnmarshalJSON(b []byte) error {
-	s := strings.Trim(string(b), `"`)
-	if s == "" {
-		return nil
-	}
-	// Parse as naive timestamp without timezone conversion
-	t, err := parseTimeAsNaive(
-		s,
-		[]string{"2006-01-02T15:04:05", "2006-01-02 15:04:05"},
-		nil,
-	)
+// FetchResponse executes the request and returns the raw body of a successful response.
+// Transport failures and non-2xx statuses are returned as an ErrorResponse.
+func FetchResponse(client *http.Client, request *http.Request, debug bool) ([]byte, *ErrorResponse) {
+	response, err := FetchRawResponse(client, request, debug)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	lt.Time = t
-	return nil
+	return response.Body, nil
 }
 
-func (lt LocalTime) MarshalJSON() ([]byte, error) {
-	// Keep API-compatible datetime format without timezone suffix.
-	return json.Marshal(lt.Format("2006-01-02T15:04:05"))
+// FetchRawResponse executes the request like FetchResponse and also returns the status and headers.
+// The response is nil only when the request could not be sent or its body could not be read.
+// Debug output goes to DebugLogger with credentials and personal data redacted.
+func FetchRawResponse(client *http.Client, request *http.Request, debug bool) (*RawResponse, *ErrorResponse) {
+	var logger *slog.Logger
+	if debug {
+		logger = DebugLogger()
+	}
+	return FetchRawResponseLimit(client, request, logger, 0)
 }
 
-// LocalDate handles API date values and normalizes them to UTC.
-// Naive inputs are assumed to be in UTC+1.
-type LocalDate struct {
-	time.Time
+// FetchRawResponseLimit is FetchRawResponse failing when the body exceeds maxBodyBytes; zero means no limit.
+// The exchange is logged to debug when it is not nil.
+func FetchRawResponseLimit(client *http.Client, request *http.Request, debug *slog.Logger, maxBodyBytes int64) (*RawResponse, *ErrorResponse) {
+	response, errResp := doRequest(client, request, debug)
+	if errResp != nil {
+		return nil, errResp
+	}
+	defer response.Body.Close()
+	return readRawResponse(response, debug, maxBodyBytes)
 }
 
-func (ld *LocalDate) UnmarshalJSON(b []byte) error {
-	s := strings.Trim(string(b), `"`)
-	if s == "" {
-		return nil
+// OpenRawResponse executes the request and returns the body of a successful response unread in Stream,
+// limited to maxBodyBytes when positive. Error responses are read and reported like FetchRawResponse.
+func OpenRawResponse(client *http.Client, request *http.Request, debug *slog.Logger, maxBodyBytes int64) (*RawResponse, *ErrorResponse) {
+	response, errResp := doRequest(client, request, debug)
+	if errResp != nil {
+		return nil, errResp
 	}
-	t, err := parseTimeAsNaive(
-		s,
-		[]string{"2006-01-02T15:04:05", "2006-01-02 15:04:05"},
-		[]string{"2006-01-02"},
-	)
-	if err != nil {
-		return fmt.Errorf("unable to parse date: %s", s)
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		defer response.Body.Close()
+		return readRawResponse(response, debug, maxBodyBytes)
 	}
-	ld.Time = t
-	return nil
+	logDebugResponse(debug, response, "(streamed)")
+	return &RawResponse{
+		StatusCode: response.StatusCode,
+		Header:     response.Header,
+		Stream: struct {
+			io.Reader
+			io.Closer
+		}{LimitBody(response.Body, maxBodyBytes), response.Body},
+	}, nil
 }
 
-func (ld LocalDate) MarshalJSON() ([]byte, error) {
-	// Keep API-compatible date format.
-	return json.Marshal(ld.Format("2006-01-02"))
+// DebugLogger returns the logger used for debug output when none is configured: text records on stderr
+func DebugLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
 }
 
-// RequestResult encapsulates the possible outcomes of an API request
-type RequestResult[T any] struct {
-	Response T
-	Error    *ErrorResponse
-}
-
-// ExecuteRequest handles common HTTP request execution pattern including error handling and response processing
-// It takes a context, http client, request, debug flag, and returns a typed RequestResult
-func ExecuteRequest[T any](ctx context.Context, client *http.Client, request *http.Request, debug bool) RequestResult[T] {
-	var zero T
-	if debug {
+// doRequest sends the request, logging it as a cURL command to debug when set
+func doRequest(client *http.Client, request *http.Request, debug *slog.Logger) (*http.Response, *ErrorResponse) {
+	if debug != nil {
 		curlCommand, curlErr := formatCurlCommand(request)
 		if curlErr != nil {
-			log.Printf("Failed to format cURL command: %v", curlErr)
-		} else {
-			fmt.Println(curlCommand)
+			curlCommand = "(unavailable: " + curlErr.Error() + ")"
 		}
+		debug.LogAttrs(request.Context(), slog.LevelDebug, "xplor debug request", slog.String("curl", curlCommand))
 	}
 
 	response, clientErr := client.Do(request)
 	if clientErr != nil {
-		return RequestResult[T]{
-			Response: zero,
-			Error: &ErrorResponse{
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to execute request: " + clientErr.Error(),
-			},
+		return nil, &ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to execute request: " + clientErr.Error(),
 		}
 	}
-	defer response.Body.Close()
+	return response, nil
+}
 
-	if debug {
-		fmt.Printf("Response status: %s\n", response.Status)
-		for _, key := range sortedHeaderKeys(response.Header) {
-			for _, value := range response.Header.Values(key) {
-				fmt.Printf("Response header %s: %s\n", key, value)
-			}
-		}
+// logDebugResponse logs the status, redacted headers and body of a response to debug when set
+func logDebugResponse(debug *slog.Logger, response *http.Response, body string) {
+	if debug == nil {
+		return
+	}
+	ctx := context.Background()
+	if response.Request != nil {
+		ctx = response.Request.Context()
 	}
+	header := RedactHeader(response.Header)
+	headers := make([]any, 0, len(header))
+	for _, key := range sortedHeaderKeys(header) {
+		headers = append(headers, slog.String(key, strings.Join(header.Values(key), ", ")))
+	}
+	debug.LogAttrs(ctx, slog.LevelDebug, "xplor debug response",
+		slog.String("status", response.Status),
+		slog.Group("headers", headers...),
+		slog.String("body", body),
+	)
+}
 
-	bodyBytes, err := io.ReadAll(response.Body)
+// readRawResponse reads the body of response, reporting non-2xx statuses as errors
+func readRawResponse(response *http.Response, debug *slog.Logger, maxBodyBytes int64) (*RawResponse, *ErrorResponse) {
+	bodyBytes, err := io.ReadAll(LimitBody(response.Body, maxBodyBytes))
 	if err != nil {
-		return RequestResult[T]{
-			Response: zero,
-			Error: &ErrorResponse{
-				Code:    http.StatusInternalServerError,
-				Message: "Failed to read response body: " + err.Error(),
-			},
+		return nil, &ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to read response body: " + err.Error(),
 		}
 	}
-	if debug {
-		fmt.Printf("Body: %s\n", string(bodyBytes))
-	}
+	logDebugResponse(debug, response, string(RedactBody(bodyBytes)))
 
+	raw := &RawResponse{StatusCode: response.StatusCode, Header: response.Header, Body: bodyBytes}
 	// If we received a non-success status code, return an error
 	if response.StatusCode < 200 || response.StatusCode >= 300 {
-		return RequestResult[T]{
-			Response: zero,
-			Error: &ErrorResponse{
-				Code:    response.StatusCode,
-				Message: "Response: " + string(bodyBytes),
-			},
+		return raw, &ErrorResponse{
+			Code:    response.StatusCode,
+			Message: "Response: " + string(bodyBytes),
 		}
 	}
 
+	return raw, nil
+}
+
+// DecodeResponse unmarshals a successful response body into a typed RequestResult.
+// An empty body yields the zero value of T.
+func DecodeResponse[T any](bodyBytes []byte) RequestResult[T] {
+	return DecodeResponseMode[T](bodyBytes, DecodeStandard)
+}
+
+// DecodeResponseMode is DecodeResponse with the given decode mode
+func DecodeResponseMode[T any](bodyBytes []byte, mode DecodeMode) RequestResult[T] {
+	var zero T
+
 	// Only try to unmarshal if we have response body
 	if len(bodyBytes) > 0 {
 		var target T
-		err = json.Unmarshal(bodyBytes, &target)
+		warnings, err := Unmarshal(bodyBytes, &target, mode)
 		if err != nil {
 			return RequestResult[T]{
 				Response: zero,
@@ -186,11 +193,13 @@ func ExecuteRequest[T any](ctx context.Context, client *http.Client, request *ht
 					Code:    http.StatusInternalServerError,
 					Message: "Failed to unmarshal response: " + err.Error(),
 				},
+				Warnings: warnings,
 			}
 		}
 		return RequestResult[T]{
 			Response: target,
 			Error:    nil,
+			Warnings: warnings,
 		}
 	}
 
@@ -200,6 +209,15 @@ func ExecuteRequest[T any](ctx context.Context, client *http.Client, request *ht
 	}
 }
 
+// Deref returns the value value points to, or the zero value when it is nil
+func Deref[T any](value *T) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+	return *value
+}
+
 func AddQueryParam(name string, value *string, values *url.Values) {
 	if value != nil && *value != "" {
 		values.Add(name, *value)
@@ -212,11 +230,12 @@ func formatCurlCommand(request *http.Request) (string, error) {
 	builder.WriteString("curl -X ")
 	builder.WriteString(request.Method)
 	builder.WriteString(" \\\n'")
-	builder.WriteString(shellSingleQuote(request.URL.String()))
+	builder.WriteString(shellSingleQuote(RedactURL(request.URL)))
 	builder.WriteString("'")
 
-	for _, key := range sortedHeaderKeys(request.Header) {
-		for _, value := range request.Header.Values(key) {
+	header := RedactHeader(request.Header)
+	for _, key := range sortedHeaderKeys(header) {
+		for _, value := range header.Values(key) {
 			builder.WriteString(" \\\n-H '")
 			builder.WriteString(shellSingleQuote(key))
 			builder.WriteString(": ")
@@ -235,7 +254,7 @@ func formatCurlCommand(request *http.Request) (string, error) {
 
 		if len(bodyBytes) > 0 {
 			builder.WriteString(" \\\n-d '")
-			builder.WriteString(shellSingleQuote(string(bodyBytes)))
+			builder.WriteString(shellSingleQuote(string(RedactBody(bodyBytes))))
 			builder.WriteString("'")
 		}
 	}
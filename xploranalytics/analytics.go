@@ -0,0 +1,290 @@
+// Package xploranalytics reports class occupancy and attendance: fill rate, waitlist pressure,
+// late-cancel rate and no-show rate per class, activity, coach, studio, weekday and hour,
+// so managers can see which slots of the timetable are over or under used.
+package xploranalytics
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// Dimension is a grouping of the classes of a report
+type Dimension string
+
+const (
+	ByClass    Dimension = "class"
+	ByActivity Dimension = "activity"
+	ByCoach    Dimension = "coach"
+	ByStudio   Dimension = "studio"
+	ByWeekday  Dimension = "weekday"
+	ByHour     Dimension = "hour"
+)
+
+// Dimensions lists every grouping, in the order reports render them
+var Dimensions = []Dimension{ByClass, ByActivity, ByCoach, ByStudio, ByWeekday, ByHour}
+
+// Metrics aggregates the classes of one group.
+// Rates are zero when their denominator is.
+type Metrics struct {
+	Key   string `json:"key"`   // Class, activity, coach or studio IRI, weekday number (0 is Sunday) or hour
+	Label string `json:"label"` // Class summary and start, weekday name, "18:00", or the IRI
+
+	Classes     int `json:"classes"`
+	Capacity    int `json:"capacity"`    // Places of the classes with an attending limit
+	Occupied    int `json:"occupied"`    // Booked places of those classes
+	FullClasses int `json:"fullClasses"` // Classes with every place booked
+
+	Booked       int `json:"booked"` // Attendees holding a place
+	Queued       int `json:"queued"` // Attendees on the waiting list
+	Canceled     int `json:"canceled"`
+	LateCanceled int `json:"lateCanceled"` // Canceled after the cancellation delay
+	Showed       int `json:"showed"`       // Attendees of ended classes who showed up or were validated
+	NoShows      int `json:"noShows"`      // Attendees of ended classes with attendance taken who did not
+
+	States map[string]int `json:"states,omitempty"` // Attendees by state
+
+	FillRate         float64 `json:"fillRate"`         // Occupied / Capacity
+	WaitlistPressure float64 `json:"waitlistPressure"` // Queued of the classes with a limit / Capacity: people waiting per place
+	LateCancelRate   float64 `json:"lateCancelRate"`   // LateCanceled / (Booked + Canceled)
+	NoShowRate       float64 `json:"noShowRate"`       // NoShows / (Showed + NoShows)
+
+	limitedQueued int    // Queued attendees of the classes counted in Capacity
+	order         string // Sorts the groups of the dimension
+}
+
+// Report holds the metrics of the classes of a date range
+type Report struct {
+	From   time.Time               `json:"from"`
+	To     time.Time               `json:"to"`
+	Total  Metrics                 `json:"total"`
+	Groups map[Dimension][]Metrics `json:"groups"`
+}
+
+// Analyzer accumulates classes into a report
+type Analyzer struct {
+	location *time.Location
+	now      time.Time
+	total    *Metrics
+	groups   map[Dimension]map[string]*Metrics
+}
+
+// NewAnalyzer returns an Analyzer reading naive API datetimes in location (nil uses UTC).
+// Attendance only counts for the classes ended before now.
+func NewAnalyzer(location *time.Location, now time.Time) *Analyzer {
+	if location == nil {
+		location = time.UTC
+	}
+	groups := make(map[Dimension]map[string]*Metrics, len(Dimensions))
+	for _, dimension := range Dimensions {
+		groups[dimension] = make(map[string]*Metrics)
+	}
+	return &Analyzer{location: location, now: now, total: &Metrics{Key: "total", Label: "Total"}, groups: groups}
+}
+
+// attendance is one attendee of a class, whichever endpoint it comes from
+type attendance struct {
+	queued, canceled, late, showed bool
+	state                          string
+}
+
+// attended tells whether the attendee was checked in, by the showed flag or the validated state
+func (a attendance) attended() bool {
+	return a.showed || a.state == "validated"
+}
+
+// AddClass adds a class and the attendees embedded in its booked and queued lists.
+// Deleted classes and deleted attendees are left out.
+func (a *Analyzer) AddClass(class xplorentities.XPlorClass) {
+	if happened(class.DeletedAt) {
+		return
+	}
+	var attendees []attendance
+	for _, list := range []struct {
+		attendees []xplorentities.Attendee
+		queued    bool
+	}{{class.BookedAttendees, false}, {class.QueuedAttendees, true}} {
+		for _, attendee := range list.attendees {
+			if happened(attendee.DeletedAt) {
+				continue
+			}
+			attendees = append(attendees, attendance{
+				queued:   list.queued,
+				canceled: happened(attendee.CanceledAt),
+				late:     attendee.CancelDelayOver,
+				showed:   attendee.Showed || happened(attendee.ValidatedAt),
+				state:    attendee.State,
+			})
+		}
+	}
+	a.add(class, attendees)
+}
+
+// AddClassAttendees adds a class with the attendee records read from the attendees endpoint instead of its embedded lists.
+// Records of the contacts in the queued list of the class count as queued.
+func (a *Analyzer) AddClassAttendees(class xplorentities.XPlorClass, records []xplorentities.XPlorAttendee) {
+	if happened(class.DeletedAt) {
+		return
+	}
+	queued := make(map[string]bool, len(class.QueuedAttendees))
+	for _, attendee := range class.QueuedAttendees {
+		if attendee.ContactID != nil {
+			queued[*attendee.ContactID] = true
+		}
+	}
+	attendees := make([]attendance, 0, len(records))
+	for _, record := range records {
+		if present(record.DeletedAt) {
+			continue
+		}
+		attendees = append(attendees, attendance{
+			queued:   record.ContactId != nil && queued[*record.ContactId],
+			canceled: present(record.CanceledAt),
+			late:     record.CancelDelayOver,
+			showed:   record.Showed || present(record.ValidatedAt),
+			state:    util.Deref(record.State),
+		})
+	}
+	a.add(class, attendees)
+}
+
+func (a *Analyzer) add(class xplorentities.XPlorClass, attendees []attendance) {
+	start := class.StartedAt.InLocation(a.location).In(a.location)
+	ended := !class.EndedAt.IsZero() && !class.EndedAt.InLocation(a.location).After(a.now)
+
+	// Attendance was taken when somebody was checked in; otherwise nobody counts as a no-show
+	taken := false
+	for _, attendee := range attendees {
+		if !attendee.canceled && !attendee.queued && attendee.attended() {
+			taken = true
+		}
+	}
+
+	var counts Metrics
+	counts.Classes = 1
+	for _, attendee := range attendees {
+		if attendee.state != "" {
+			if counts.States == nil {
+				counts.States = make(map[string]int)
+			}
+			counts.States[attendee.state]++
+		}
+		switch {
+		case attendee.canceled:
+			counts.Canceled++
+			if attendee.late {
+				counts.LateCanceled++
+			}
+		case attendee.queued:
+			counts.Queued++
+		default:
+			counts.Booked++
+			if ended && attendee.attended() {
+				counts.Showed++
+			} else if ended && taken {
+				counts.NoShows++
+			}
+		}
+	}
+	if class.AttendingLimit != nil && *class.AttendingLimit > 0 {
+		limit := *class.AttendingLimit
+		counts.Capacity = limit
+		counts.limitedQueued = counts.Queued
+		counts.Occupied = counts.Booked
+		if counts.Booked == 0 {
+			// Listings without attendees still tell the remaining places
+			counts.Occupied = max(limit-class.AttendeeRemaining, 0)
+		}
+		counts.Occupied = min(counts.Occupied, limit)
+		if counts.Occupied >= limit {
+			counts.FullClasses = 1
+		}
+	}
+
+	id := util.Deref(class.ID)
+	a.total.merge(counts)
+	a.group(ByClass, id, fmt.Sprintf("%s %s", class.Summary, start.Format("2006-01-02 15:04")), start.UTC().Format(time.RFC3339)+id).merge(counts)
+	a.group(ByActivity, util.Deref(class.Activity), "", "").merge(counts)
+	a.group(ByCoach, util.Deref(class.Coach), "", "").merge(counts)
+	a.group(ByStudio, util.Deref(class.Studio), "", "").merge(counts)
+	weekday := start.Weekday()
+	// Weeks start on Monday
+	a.group(ByWeekday, fmt.Sprint(int(weekday)), weekday.String(), fmt.Sprint((int(weekday)+6)%7)).merge(counts)
+	a.group(ByHour, fmt.Sprint(start.Hour()), fmt.Sprintf("%02d:00", start.Hour()), fmt.Sprintf("%02d", start.Hour())).merge(counts)
+}
+
+// group returns the metrics of key in dimension, created with label and order when missing; both default to key
+func (a *Analyzer) group(dimension Dimension, key, label, order string) *Metrics {
+	metrics, ok := a.groups[dimension][key]
+	if !ok {
+		metrics = &Metrics{Key: key, Label: cmp.Or(label, key, "(none)"), order: cmp.Or(order, key)}
+		a.groups[dimension][key] = metrics
+	}
+	return metrics
+}
+
+func (m *Metrics) merge(counts Metrics) {
+	m.Classes += counts.Classes
+	m.Capacity += counts.Capacity
+	m.Occupied += counts.Occupied
+	m.FullClasses += counts.FullClasses
+	m.Booked += counts.Booked
+	m.Queued += counts.Queued
+	m.limitedQueued += counts.limitedQueued
+	m.Canceled += counts.Canceled
+	m.LateCanceled += counts.LateCanceled
+	m.Showed += counts.Showed
+	m.NoShows += counts.NoShows
+	for state, n := range counts.States {
+		if m.States == nil {
+			m.States = make(map[string]int)
+		}
+		m.States[state] += n
+	}
+}
+
+func (m *Metrics) rates() {
+	m.FillRate = rate(m.Occupied, m.Capacity)
+	m.WaitlistPressure = rate(m.limitedQueued, m.Capacity)
+	m.LateCancelRate = rate(m.LateCanceled, m.Booked+m.Canceled)
+	m.NoShowRate = rate(m.NoShows, m.Showed+m.NoShows)
+}
+
+// Report returns the metrics of the classes added so far for the range [from, to)
+func (a *Analyzer) Report(from, to time.Time) *Report {
+	report := &Report{From: from, To: to, Total: *a.total, Groups: make(map[Dimension][]Metrics, len(Dimensions))}
+	report.Total.States = maps.Clone(report.Total.States)
+	report.Total.rates()
+	for _, dimension := range Dimensions {
+		metrics := make([]Metrics, 0, len(a.groups[dimension]))
+		for _, group := range a.groups[dimension] {
+			group.rates()
+			metric := *group
+			metric.States = maps.Clone(metric.States)
+			metrics = append(metrics, metric)
+		}
+		slices.SortFunc(metrics, func(x, y Metrics) int { return cmp.Compare(x.order, y.order) })
+		report.Groups[dimension] = metrics
+	}
+	return report
+}
+
+func rate(n, of int) float64 {
+	if of == 0 {
+		return 0
+	}
+	return float64(n) / float64(of)
+}
+
+func happened(value *util.LocalTime) bool {
+	return value != nil && !value.IsZero()
+}
+
+func present(value *string) bool {
+	return value != nil && *value != ""
+}
@@ -0,0 +1,189 @@
+package xploranalytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+var (
+	now       = time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	endedAt   = time.Date(2025, 6, 9, 18, 0, 0, 0, time.UTC)
+	upcoming  = time.Date(2025, 6, 11, 18, 0, 0, 0, time.UTC)
+	canceled  = &util.LocalTime{Time: time.Date(2025, 6, 9, 10, 0, 0, 0, time.UTC)}
+	validated = &util.LocalTime{Time: time.Date(2025, 6, 9, 18, 5, 0, 0, time.UTC)}
+)
+
+// class builds a class of activity starting at start; a limit of zero leaves it unlimited
+func class(id, activity string, start time.Time, limit int, booked, queued []xplorentities.Attendee) xplorentities.XPlorClass {
+	iri := "/enjoy/class_events/" + id
+	activityIRI := "/enjoy/activities/" + activity
+	c := xplorentities.XPlorClass{
+		ID:              &iri,
+		Activity:        &activityIRI,
+		StartedAt:       util.LocalTime{Time: start},
+		EndedAt:         util.LocalTime{Time: start.Add(time.Hour)},
+		BookedAttendees: booked,
+		QueuedAttendees: queued,
+	}
+	if limit > 0 {
+		c.AttendingLimit = &limit
+		c.AttendeeRemaining = max(limit-len(booked), 0)
+	}
+	return c
+}
+
+func attendees(n int, attendee xplorentities.Attendee) []xplorentities.Attendee {
+	list := make([]xplorentities.Attendee, n)
+	for i := range list {
+		list[i] = attendee
+	}
+	return list
+}
+
+func TestAddClass(t *testing.T) {
+	booked := xplorentities.Attendee{State: "booked"}
+	showed := xplorentities.Attendee{State: "booked", Showed: true}
+	queued := xplorentities.Attendee{State: "queued"}
+	tests := []struct {
+		name  string
+		class xplorentities.XPlorClass
+		want  Metrics
+	}{
+		{
+			name:  "full class with waiting list",
+			class: class("1", "yoga", upcoming, 2, attendees(2, booked), attendees(3, queued)),
+			want:  Metrics{Classes: 1, Capacity: 2, Occupied: 2, FullClasses: 1, Booked: 2, Queued: 3, FillRate: 1, WaitlistPressure: 1.5},
+		},
+		{
+			name:  "unlimited class has no capacity nor pressure",
+			class: class("1", "yoga", upcoming, 0, attendees(2, booked), attendees(3, queued)),
+			want:  Metrics{Classes: 1, Booked: 2, Queued: 3},
+		},
+		{
+			name: "listing without attendees reads the remaining places",
+			class: func() xplorentities.XPlorClass {
+				c := class("1", "yoga", upcoming, 10, nil, nil)
+				c.AttendeeRemaining = 4
+				return c
+			}(),
+			want: Metrics{Classes: 1, Capacity: 10, Occupied: 6, FillRate: 0.6},
+		},
+		{
+			name: "ended class with attendance taken",
+			class: class("1", "yoga", endedAt, 4, []xplorentities.Attendee{
+				showed, {State: "validated"}, {ValidatedAt: validated}, booked,
+			}, nil),
+			want: Metrics{Classes: 1, Capacity: 4, Occupied: 4, FullClasses: 1, Booked: 4, Showed: 3, NoShows: 1, FillRate: 1, NoShowRate: 0.25},
+		},
+		{
+			name:  "ended class without attendance taken has no no-shows",
+			class: class("1", "yoga", endedAt, 4, attendees(3, booked), nil),
+			want:  Metrics{Classes: 1, Capacity: 4, Occupied: 3, Booked: 3, FillRate: 0.75},
+		},
+		{
+			name:  "upcoming class has no attendance",
+			class: class("1", "yoga", upcoming, 4, []xplorentities.Attendee{showed, booked}, nil),
+			want:  Metrics{Classes: 1, Capacity: 4, Occupied: 2, Booked: 2, FillRate: 0.5},
+		},
+		{
+			name: "cancellations",
+			class: class("1", "yoga", upcoming, 4, []xplorentities.Attendee{
+				booked, {CanceledAt: canceled}, {CanceledAt: canceled, CancelDelayOver: true}, {DeletedAt: canceled},
+			}, nil),
+			want: Metrics{Classes: 1, Capacity: 4, Occupied: 1, Booked: 1, Canceled: 2, LateCanceled: 1, FillRate: 0.25, LateCancelRate: 1.0 / 3},
+		},
+		{
+			name: "deleted class",
+			class: func() xplorentities.XPlorClass {
+				c := class("1", "yoga", upcoming, 4, attendees(2, booked), nil)
+				c.DeletedAt = canceled
+				return c
+			}(),
+			want: Metrics{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := NewAnalyzer(time.UTC, now)
+			analyzer.AddClass(tt.class)
+			assertCounts(t, analyzer.Report(now, now).Total, tt.want)
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	booked := xplorentities.Attendee{State: "booked"}
+	showed := xplorentities.Attendee{State: "booked", Showed: true}
+	queued := xplorentities.Attendee{State: "queued"}
+
+	analyzer := NewAnalyzer(time.UTC, now)
+	// Yoga mixes a full limited class with an unlimited one whose waiting list must not weigh on the places
+	analyzer.AddClass(class("1", "yoga", endedAt, 2, []xplorentities.Attendee{showed, booked}, attendees(2, queued)))
+	analyzer.AddClass(class("2", "yoga", upcoming, 0, attendees(5, booked), attendees(6, queued)))
+	// Spinning never takes attendance
+	analyzer.AddClass(class("3", "spinning", endedAt.Add(time.Hour), 10, attendees(5, booked), nil))
+	report := analyzer.Report(now, now)
+
+	tests := []struct {
+		dimension Dimension
+		key       string
+		want      Metrics
+	}{
+		{ByActivity, "/enjoy/activities/yoga", Metrics{
+			Classes: 2, Capacity: 2, Occupied: 2, FullClasses: 1, Booked: 7, Queued: 8, Showed: 1, NoShows: 1,
+			FillRate: 1, WaitlistPressure: 1, NoShowRate: 0.5,
+		}},
+		{ByActivity, "/enjoy/activities/spinning", Metrics{Classes: 1, Capacity: 10, Occupied: 5, Booked: 5, FillRate: 0.5}},
+		{ByHour, "18", Metrics{
+			Classes: 2, Capacity: 2, Occupied: 2, FullClasses: 1, Booked: 7, Queued: 8, Showed: 1, NoShows: 1,
+			FillRate: 1, WaitlistPressure: 1, NoShowRate: 0.5,
+		}},
+		{ByHour, "19", Metrics{Classes: 1, Capacity: 10, Occupied: 5, Booked: 5, FillRate: 0.5}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.dimension)+" "+tt.key, func(t *testing.T) {
+			for _, metrics := range report.Groups[tt.dimension] {
+				if metrics.Key == tt.key {
+					assertCounts(t, metrics, tt.want)
+					return
+				}
+			}
+			t.Fatalf("no %s group %s in %+v", tt.dimension, tt.key, report.Groups[tt.dimension])
+		})
+	}
+
+	total := report.Total
+	assertCounts(t, total, Metrics{
+		Classes: 3, Capacity: 12, Occupied: 7, FullClasses: 1, Booked: 12, Queued: 8, Showed: 1, NoShows: 1,
+		FillRate: 7.0 / 12, WaitlistPressure: 2.0 / 12, NoShowRate: 0.5,
+	})
+	if total.States["booked"] != 12 || total.States["queued"] != 8 {
+		t.Errorf("states = %v, want 12 booked and 8 queued", total.States)
+	}
+	if hours := report.Groups[ByHour]; len(hours) != 2 || hours[0].Label != "18:00" {
+		t.Errorf("hours = %+v, want 18:00 then 19:00", hours)
+	}
+}
+
+func assertCounts(t *testing.T, got, want Metrics) {
+	t.Helper()
+	counts := func(m Metrics) [10]int {
+		return [10]int{m.Classes, m.Capacity, m.Occupied, m.FullClasses, m.Booked, m.Queued, m.Canceled, m.LateCanceled, m.Showed, m.NoShows}
+	}
+	if counts(got) != counts(want) {
+		t.Errorf("counts [classes capacity occupied full booked queued canceled late showed noShows] = %v, want %v", counts(got), counts(want))
+	}
+	rates := func(m Metrics) [4]float64 {
+		return [4]float64{m.FillRate, m.WaitlistPressure, m.LateCancelRate, m.NoShowRate}
+	}
+	const epsilon = 1e-9
+	for i, rate := range rates(got) {
+		if diff := rate - rates(want)[i]; diff > epsilon || diff < -epsilon {
+			t.Errorf("rates [fill pressure lateCancel noShow] = %v, want %v", rates(got), rates(want))
+			break
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package xploranalytics
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/angelbarreiros/XPlorGo/xplorexport"
+)
+
+// row is one line of the CSV rendering
+type row struct {
+	dimension Dimension
+	metrics   Metrics
+}
+
+var csvColumns = []xplorexport.Column[row]{
+	{Header: "dimension", Value: func(r row) string { return string(r.dimension) }},
+	{Header: "key", Value: func(r row) string { return r.metrics.Key }},
+	{Header: "label", Value: func(r row) string { return r.metrics.Label }},
+	{Header: "classes", Value: func(r row) string { return strconv.Itoa(r.metrics.Classes) }},
+	{Header: "capacity", Value: func(r row) string { return strconv.Itoa(r.metrics.Capacity) }},
+	{Header: "occupied", Value: func(r row) string { return strconv.Itoa(r.metrics.Occupied) }},
+	{Header: "fullClasses", Value: func(r row) string { return strconv.Itoa(r.metrics.FullClasses) }},
+	{Header: "booked", Value: func(r row) string { return strconv.Itoa(r.metrics.Booked) }},
+	{Header: "queued", Value: func(r row) string { return strconv.Itoa(r.metrics.Queued) }},
+	{Header: "canceled", Value: func(r row) string { return strconv.Itoa(r.metrics.Canceled) }},
+	{Header: "lateCanceled", Value: func(r row) string { return strconv.Itoa(r.metrics.LateCanceled) }},
+	{Header: "showed", Value: func(r row) string { return strconv.Itoa(r.metrics.Showed) }},
+	{Header: "noShows", Value: func(r row) string { return strconv.Itoa(r.metrics.NoShows) }},
+	{Header: "fillRate", Value: func(r row) string { return ratio(r.metrics.FillRate) }},
+	{Header: "waitlistPressure", Value: func(r row) string { return ratio(r.metrics.WaitlistPressure) }},
+	{Header: "lateCancelRate", Value: func(r row) string { return ratio(r.metrics.LateCancelRate) }},
+	{Header: "noShowRate", Value: func(r row) string { return ratio(r.metrics.NoShowRate) }},
+}
+
+// WriteCSV writes the total and the groups of dimensions, every dimension when none is given,
+// as one table whose first column names the dimension of each row
+func (r *Report) WriteCSV(w io.Writer, dimensions ...Dimension) error {
+	if len(dimensions) == 0 {
+		dimensions = Dimensions
+	}
+	rows := []row{{dimension: "total", metrics: r.Total}}
+	for _, dimension := range dimensions {
+		for _, metrics := range r.Groups[dimension] {
+			rows = append(rows, row{dimension: dimension, metrics: metrics})
+		}
+	}
+	_, err := xplorexport.WriteCSV(w, xplorexport.Slice(rows), csvColumns)
+	return err
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func ratio(value float64) string {
+	return strconv.FormatFloat(value, 'f', 4, 64)
+}
@@ -0,0 +1,100 @@
+package xploranalytics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/xplorcore"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+	"github.com/angelbarreiros/XPlorGo/xplorexport"
+)
+
+// Options tunes Build
+type Options struct {
+	// Params filters the classes, e.g. by club or activity; their startedAt filters are replaced by the range
+	Params xplorentities.XPlorClassesParams
+	// Location is the time zone of naive API datetimes, weekdays and hours; nil uses UTC
+	Location *time.Location
+	// ItemsPerPage is the page size; zero uses xplorexport.DefaultItemsPerPage
+	ItemsPerPage int
+	// FetchAttendees reads the attendee records of each class instead of the attendees embedded in the classes,
+	// for listings that leave them out. It costs at least one request per class.
+	FetchAttendees bool
+	// Now returns the current time, before which classes have ended; nil uses time.Now
+	Now func() time.Time
+}
+
+// Build reads the classes of the node starting in [from, to) and returns their report
+func Build(ctx context.Context, xe *xplorcore.XplorProvider, nodeId string, from, to time.Time, options Options) (*Report, error) {
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+	now := time.Now
+	if options.Now != nil {
+		now = options.Now
+	}
+	analyzer := NewAnalyzer(options.Location, now())
+
+	params := options.Params
+	params.StartedAtBefore, params.StartedAtStrictlyBefore, params.StartedAtAfter, params.StartedAtStrictlyAfter = nil, nil, nil, nil
+	params.StartedAt = xplorentities.DateBetween(from.In(options.Location), to.In(options.Location))
+
+	classes := xplorexport.Pages(options.ItemsPerPage, func(pagination *xplorentities.XPlorPagination) ([]xplorentities.XPlorClass, xplorentities.PageInfo, *xplorentities.ErrorResponse) {
+		if err := cancelled(ctx); err != nil {
+			return nil, xplorentities.PageInfo{}, err
+		}
+		page, err := xe.ClassesContext(ctx, nodeId, &params, pagination)
+		if err != nil {
+			return nil, xplorentities.PageInfo{}, err
+		}
+		return page.Members, page.PageInfo(), nil
+	})
+	var attendeesErr error
+	err := classes(func(class xplorentities.XPlorClass) bool {
+		if !options.FetchAttendees || happened(class.DeletedAt) {
+			analyzer.AddClass(class)
+			return true
+		}
+		classId, err := class.ClassEventID()
+		if err != nil {
+			analyzer.AddClass(class)
+			return true
+		}
+		var records []xplorentities.XPlorAttendee
+		attendees := xplorexport.Pages(options.ItemsPerPage, func(pagination *xplorentities.XPlorPagination) ([]xplorentities.XPlorAttendee, xplorentities.PageInfo, *xplorentities.ErrorResponse) {
+			if err := cancelled(ctx); err != nil {
+				return nil, xplorentities.PageInfo{}, err
+			}
+			page, err := xe.AttendeesContext(ctx, nodeId, &classId, pagination)
+			if err != nil {
+				return nil, xplorentities.PageInfo{}, err
+			}
+			return page.Members, page.PageInfo(), nil
+		})
+		if attendeesErr = attendees(func(record xplorentities.XPlorAttendee) bool {
+			records = append(records, record)
+			return true
+		}); attendeesErr != nil {
+			attendeesErr = fmt.Errorf("attendees of class %s: %w", classId, attendeesErr)
+			return false
+		}
+		analyzer.AddClassAttendees(class, records)
+		return true
+	})
+	if err == nil {
+		err = attendeesErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("xploranalytics: %w", err)
+	}
+	return analyzer.Report(from, to), nil
+}
+
+func cancelled(ctx context.Context) *xplorentities.ErrorResponse {
+	if err := ctx.Err(); err != nil {
+		return &xplorentities.ErrorResponse{Code: http.StatusRequestTimeout, Message: "Report cancelled: " + err.Error()}
+	}
+	return nil
+}
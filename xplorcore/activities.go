@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) activities(accesToken string, queryParams *xplorentities.XPlorActivitiesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorActivities, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorActivities], 1)
 
@@ -23,7 +23,7 @@ func (xe xplorExecutor) activities(accesToken string, queryParams *xplorentities
 
 		var request = xe.config.generateRequest(http.MethodGet, "/activities", xe.generateHeaders(accesToken), paginatedParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorActivities](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorActivities](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -43,7 +43,7 @@ func (xe xplorExecutor) activities(accesToken string, queryParams *xplorentities
 }
 
 func (xe xplorExecutor) activity(accesToken string, activityId string) (*xplorentities.XPlorActivity, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorActivity], 1)
 
@@ -53,7 +53,7 @@ func (xe xplorExecutor) activity(accesToken string, activityId string) (*xploren
 
 		var request = xe.config.generateRequest(http.MethodGet, "/activities/"+activityId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorActivity](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorActivity](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
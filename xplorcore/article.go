@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) articles(accesToken string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorArticles, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorArticles], 1)
 
@@ -20,7 +20,7 @@ func (xe xplorExecutor) articles(accesToken string, pagination *xplorentities.XP
 
 		var request = xe.config.generateRequest(http.MethodGet, "/articles", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorArticles](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorArticles](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -39,7 +39,7 @@ func (xe xplorExecutor) articles(accesToken string, pagination *xplorentities.XP
 
 }
 func (xe xplorExecutor) article(accesToken string, articleId string) (*xplorentities.XPlorArticle, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorArticle], 1)
 
@@ -48,7 +48,7 @@ func (xe xplorExecutor) article(accesToken string, articleId string) (*xplorenti
 
 		var request = xe.config.generateRequest(http.MethodGet, "/articles/"+articleId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorArticle](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorArticle](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) attendees(accesToken string, classId *string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorAttendees, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorAttendees], 1)
 
@@ -21,7 +21,7 @@ func (xe xplorExecutor) attendees(accesToken string, classId *string, pagination
 
 		var request = xe.config.generateRequest(http.MethodGet, "/attendees", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorAttendees](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorAttendees](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -9,8 +9,9 @@ import (
 	xplorentities "github.com/angelbarreiros/XPlorGo/xplorentities"
 )
 
-func (xe xplorExecutor) authenticate() (*xplorentities.XPlorTokenResponse, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+// authenticate requests a token; ctx carries the authenticate span, so the token request is traced as its child
+func (xe xplorExecutor) authenticate(ctx context.Context) (*xplorentities.XPlorTokenResponse, *xplorentities.ErrorResponse) {
+	var ctxWithTimeout, cancel = context.WithTimeout(ctx, xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorTokenResponse], 1)
 
@@ -26,7 +27,7 @@ func (xe xplorExecutor) authenticate() (*xplorentities.XPlorTokenResponse, *xplo
 
 		var request = xe.config.generateRequest(http.MethodPost, "/oauth/v2/token", header, nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorTokenResponse](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorTokenResponse](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -0,0 +1,259 @@
+package xplorcore
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// Cache stores raw API response bodies.
+// Implementations must be safe for concurrent use; an external store (Redis, memcached...)
+// only needs to implement this interface to be plugged into the provider.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(key string)
+	// DeletePrefix removes every entry whose key starts with prefix
+	DeletePrefix(prefix string)
+	Clear()
+}
+
+// DefaultCacheTTLs returns TTLs for reference data that rarely changes.
+// Resources without a TTL are never cached.
+func DefaultCacheTTLs() map[xplorentities.Resource]time.Duration {
+	return map[xplorentities.Resource]time.Duration{
+		xplorentities.ResourceClubs:        time.Hour,
+		xplorentities.ResourceNetworkNodes: time.Hour,
+		xplorentities.ResourceStudios:      30 * time.Minute,
+		xplorentities.ResourceZones:        30 * time.Minute,
+		xplorentities.ResourceActivities:   15 * time.Minute,
+		xplorentities.ResourceCoaches:      15 * time.Minute,
+		xplorentities.ResourceClassTypes:   15 * time.Minute,
+	}
+}
+
+// LRUCache is an in-memory Cache bounded by entry count with per-entry expiry
+type LRUCache struct {
+	mutex      sync.Mutex
+	maxEntries int
+	entries    *list.List
+	index      map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUCache creates an in-memory cache holding at most maxEntries responses
+func NewLRUCache(maxEntries int) *LRUCache {
+	if maxEntries <= 0 {
+		maxEntries = 1024
+	}
+	return &LRUCache{
+		maxEntries: maxEntries,
+		entries:    list.New(),
+		index:      make(map[string]*list.Element),
+	}
+}
+
+// Get returns a cached value if present and not expired
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.index[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.removeElement(element)
+		return nil, false
+	}
+	c.entries.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set stores a value, evicting the least recently used entry when full.
+// A non-positive ttl keeps the entry until it is evicted.
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	if element, ok := c.index[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.entries.MoveToFront(element)
+		return
+	}
+	c.index[key] = c.entries.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.entries.Len() > c.maxEntries {
+		c.removeElement(c.entries.Back())
+	}
+}
+
+// Delete removes a single entry
+func (c *LRUCache) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.index[key]; ok {
+		c.removeElement(element)
+	}
+}
+
+// DeletePrefix removes every entry whose key starts with prefix
+func (c *LRUCache) DeletePrefix(prefix string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, element := range c.index {
+		if strings.HasPrefix(key, prefix) {
+			c.removeElement(element)
+		}
+	}
+}
+
+// Clear removes every entry
+func (c *LRUCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries.Init()
+	clear(c.index)
+}
+
+// Len returns the number of entries currently stored, including expired ones not yet evicted
+func (c *LRUCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.entries.Len()
+}
+
+func (c *LRUCache) removeElement(element *list.Element) {
+	c.entries.Remove(element)
+	delete(c.index, element.Value.(*lruEntry).key)
+}
+
+// responseCache is the read-through layer placed in front of the executors
+type responseCache struct {
+	store   Cache
+	ttls    map[xplorentities.Resource]time.Duration
+	flights flightGroup
+}
+
+func newResponseCache(cfg *xplorConfig) *responseCache {
+	if cfg == nil || cfg.Cache == nil {
+		return nil
+	}
+	ttls := cfg.CacheTTLs
+	if ttls == nil {
+		ttls = DefaultCacheTTLs()
+	}
+	return &responseCache{store: cfg.Cache, ttls: ttls}
+}
+
+// cacheKey builds "resource|node|path?normalized-query".
+// The resource comes first so a whole resource can be invalidated by prefix.
+func cacheKey(resource xplorentities.Resource, nodeId string, requestURL *url.URL) string {
+	return cacheKeyPrefix(resource, nodeId) + requestURL.Path + "?" + normalizeQuery(requestURL.Query())
+}
+
+func cacheKeyPrefix(resource xplorentities.Resource, nodeId string) string {
+	return string(resource) + "|" + nodeId + "|"
+}
+
+// normalizeQuery encodes values with sorted keys and sorted values per key
+func normalizeQuery(values url.Values) string {
+	normalized := make(url.Values, len(values))
+	for key, vals := range values {
+		sorted := slices.Clone(vals)
+		slices.Sort(sorted)
+		normalized[key] = sorted
+	}
+	return normalized.Encode()
+}
+
+// flightGroup de-duplicates concurrent calls sharing the same key
+type flightGroup struct {
+	mutex sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done  chan struct{}
+	body  []byte
+	store func()
+	err   *xplorentities.ErrorResponse
+}
+
+// do runs fn once for all concurrent callers of the same key and shares its result, store func included.
+// fn runs detached from the callers, each of which stops waiting when its own ctx is done.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() ([]byte, func(), *xplorentities.ErrorResponse)) ([]byte, func(), *xplorentities.ErrorResponse) {
+	g.mutex.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[key] = call
+		go func() {
+			call.body, call.store, call.err = fn()
+			g.mutex.Lock()
+			delete(g.calls, key)
+			g.mutex.Unlock()
+			close(call.done)
+		}()
+	}
+	g.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return call.body, call.store, call.err
+	case <-ctx.Done():
+		return nil, nil, &xplorentities.ErrorResponse{
+			Code:    http.StatusRequestTimeout,
+			Message: "Request timeout: " + context.Cause(ctx).Error(),
+		}
+	}
+}
+
+// InvalidateCache removes cached responses of a resource for one node.
+// An empty nodeId targets requests made without a node (e.g. NetworkNodes).
+func (xe *XplorProvider) InvalidateCache(nodeId string, resource xplorentities.Resource) {
+	if xe.cache == nil {
+		return
+	}
+	xe.cache.store.DeletePrefix(cacheKeyPrefix(resource, strings.TrimSpace(nodeId)))
+}
+
+// InvalidateResource removes cached responses of a resource for every node
+func (xe *XplorProvider) InvalidateResource(resource xplorentities.Resource) {
+	if xe.cache == nil {
+		return
+	}
+	xe.cache.store.DeletePrefix(string(resource) + "|")
+}
+
+// ClearCache removes every cached response
+func (xe *XplorProvider) ClearCache() {
+	if xe.cache == nil {
+		return
+	}
+	xe.cache.store.Clear()
+}
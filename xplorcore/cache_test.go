@@ -0,0 +1,202 @@
+package xplorcore
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+func TestLRUCacheEviction(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", []byte("1"), 0)
+	cache.Set("b", []byte("2"), 0)
+	// Reading a makes b the least recently used entry
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("a missing")
+	}
+	cache.Set("c", []byte("3"), 0)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("b was kept, want it evicted as least recently used")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Errorf("len = %d, want 2", cache.Len())
+	}
+
+	// Replacing an entry refreshes it without growing the cache
+	cache.Set("a", []byte("4"), 0)
+	cache.Set("d", []byte("5"), 0)
+	if value, ok := cache.Get("a"); !ok || string(value) != "4" {
+		t.Errorf("a = %q, %v, want the replaced value", value, ok)
+	}
+	if _, ok := cache.Get("c"); ok {
+		t.Error("c was kept, want it evicted after a was replaced")
+	}
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	cache := NewLRUCache(10)
+	cache.Set("short", []byte("1"), 20*time.Millisecond)
+	cache.Set("forever", []byte("2"), 0)
+	if _, ok := cache.Get("short"); !ok {
+		t.Fatal("short expired right away")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := cache.Get("short"); ok {
+		t.Error("short is still served after its TTL")
+	}
+	if _, ok := cache.Get("forever"); !ok {
+		t.Error("an entry without TTL expired")
+	}
+	if cache.Len() != 1 {
+		t.Errorf("len = %d, want the expired entry removed on read", cache.Len())
+	}
+}
+
+func TestCacheKeyNormalizesQuery(t *testing.T) {
+	key := func(raw string) string {
+		requestURL, err := url.Parse(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cacheKey(xplorentities.ResourceClasses, "2675", requestURL)
+	}
+	base := key("/enjoy/classes?page=1&activity[]=b&activity[]=a")
+	for _, same := range []string{
+		"/enjoy/classes?activity[]=b&page=1&activity[]=a",
+		"/enjoy/classes?activity[]=a&activity[]=b&page=1",
+	} {
+		if got := key(same); got != base {
+			t.Errorf("key(%s) = %q, want %q", same, got, base)
+		}
+	}
+	for _, other := range []string{
+		"/enjoy/classes?page=2&activity[]=a&activity[]=b",
+		"/enjoy/classes?page=1&activity[]=a",
+		"/enjoy/studios?page=1&activity[]=a&activity[]=b",
+	} {
+		if got := key(other); got == base {
+			t.Errorf("key(%s) = %q, same as a different request", other, got)
+		}
+	}
+	if !strings.HasPrefix(base, cacheKeyPrefix(xplorentities.ResourceClasses, "2675")) {
+		t.Errorf("key %q does not start with its resource prefix", base)
+	}
+}
+
+func TestFlightGroupCollapsesCalls(t *testing.T) {
+	var group flightGroup
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func() ([]byte, func(), *xplorentities.ErrorResponse) {
+		calls.Add(1)
+		<-release
+		return []byte("body"), nil, nil
+	}
+
+	const callers = 8
+	var wg sync.WaitGroup
+	bodies := make([]string, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, _, err := group.do(context.Background(), "key", fn)
+			if err != nil {
+				t.Error(err.Message)
+			}
+			bodies[i] = string(body)
+		}()
+	}
+	// The call is held until the other callers had time to join it
+	for {
+		group.mutex.Lock()
+		started := group.calls["key"] != nil
+		group.mutex.Unlock()
+		if started && calls.Load() == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("fn ran %d times, want 1", calls.Load())
+	}
+	for i, body := range bodies {
+		if body != "body" {
+			t.Errorf("caller %d got %q", i, body)
+		}
+	}
+
+	// A caller whose context is done stops waiting without cancelling the shared call
+	block := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := group.do(ctx, "slow", func() ([]byte, func(), *xplorentities.ErrorResponse) {
+		<-block
+		return nil, nil, nil
+	})
+	close(block)
+	if err == nil || err.Code != http.StatusRequestTimeout {
+		t.Errorf("cancelled caller: err = %v, want 408", err)
+	}
+}
+
+func TestCacheStoresDecodedBodiesOnly(t *testing.T) {
+	config := NewConfig("api.test", "v1", "enjoy", "id", "secret", nil, false)
+	config.Cache = NewLRUCache(10)
+	provider := Init(config)
+
+	var clubRequests atomic.Int32
+	var clubsBody atomic.Value
+	clubsBody.Store(`{"hydra:member":`)
+	provider.Use(func(Handler) Handler {
+		return func(r *http.Request) (*util.RawResponse, *xplorentities.ErrorResponse) {
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/oauth/v2/token"):
+				return CannedResponse(http.StatusOK, []byte(`{"access_token":"token","expires_in":3600,"token_type":"bearer"}`))
+			case strings.Contains(r.URL.Path, "/network_nodes/"):
+				return CannedResponse(http.StatusOK, []byte(`{"@id":"/enjoy/network_nodes/2675","id":2675,"name":"Club","type":"club","clubId":"/enjoy/clubs/1249","children":[]}`))
+			case strings.HasSuffix(r.URL.Path, "/clubs"):
+				clubRequests.Add(1)
+				return CannedResponse(http.StatusOK, []byte(clubsBody.Load().(string)))
+			}
+			return CannedResponse(http.StatusNotFound, []byte(`{}`))
+		}
+	})
+
+	if _, err := provider.Clubs("2675"); err == nil {
+		t.Fatal("truncated body decoded")
+	}
+	clubsBody.Store(`{"hydra:member":[{"@id":"/enjoy/clubs/1249","name":"Club"}]}`)
+	for range 2 {
+		clubs, err := provider.Clubs("2675")
+		if err != nil {
+			t.Fatalf("clubs: %s", err.Message)
+		}
+		if len(clubs.Members) != 1 {
+			t.Fatalf("clubs = %+v, want one member", clubs.Members)
+		}
+	}
+	// The truncated body was not cached, the valid one was
+	if got := clubRequests.Load(); got != 2 {
+		t.Errorf("sent %d clubs requests, want 2", got)
+	}
+}
@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) classes(accesToken string, queryParams *xplorentities.XPlorClassesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorClasses, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorClasses], 1)
 
@@ -23,7 +23,7 @@ func (xe xplorExecutor) classes(accesToken string, queryParams *xplorentities.XP
 
 		var request = xe.config.generateRequest(http.MethodGet, "/class_events", xe.generateHeaders(accesToken), paginatedParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorClasses](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorClasses](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -42,7 +42,7 @@ func (xe xplorExecutor) classes(accesToken string, queryParams *xplorentities.XP
 
 }
 func (xe xplorExecutor) class(accesToken string, classId string) (*xplorentities.XPlorClass, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorClass], 1)
 
@@ -51,7 +51,7 @@ func (xe xplorExecutor) class(accesToken string, classId string) (*xplorentities
 
 		var request = xe.config.generateRequest(http.MethodGet, "/class_events/"+classId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorClass](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorClass](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
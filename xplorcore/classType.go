@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) classType(accesToken string, classTypeId string) (*xplorentities.XPlorClassType, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorClassType], 1)
 
@@ -19,7 +19,7 @@ func (xe xplorExecutor) classType(accesToken string, classTypeId string) (*xplor
 
 		var request = xe.config.generateRequest(http.MethodGet, "/class_event_types/"+classTypeId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorClassType](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorClassType](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
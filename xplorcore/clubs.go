@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) clubs(accesToken string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPloreClubs, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPloreClubs], 1)
 
@@ -20,7 +20,7 @@ func (xe xplorExecutor) clubs(accesToken string, pagination *xplorentities.XPlor
 
 		var request = xe.config.generateRequest(http.MethodGet, "/clubs", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPloreClubs](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPloreClubs](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -40,7 +40,7 @@ func (xe xplorExecutor) clubs(accesToken string, pagination *xplorentities.XPlor
 }
 
 func (xe xplorExecutor) club(accesToken string, clubId string) (*xplorentities.XPlorClub, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorClub], 1)
 
@@ -49,7 +49,7 @@ func (xe xplorExecutor) club(accesToken string, clubId string) (*xplorentities.X
 
 		var request = xe.config.generateRequest(http.MethodGet, "/clubs/"+clubId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorClub](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorClub](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
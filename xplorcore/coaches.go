@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) coaches(accesToken string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPloreCoaches, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPloreCoaches], 1)
 
@@ -20,7 +20,7 @@ func (xe xplorExecutor) coaches(accesToken string, pagination *xplorentities.XPl
 
 		var request = xe.config.generateRequest(http.MethodGet, "/coaches", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPloreCoaches](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPloreCoaches](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -39,7 +39,7 @@ func (xe xplorExecutor) coaches(accesToken string, pagination *xplorentities.XPl
 
 }
 func (xe xplorExecutor) coach(accesToken string, familyId string) (*xplorentities.XPloreCoach, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPloreCoach], 1)
 
@@ -48,7 +48,7 @@ func (xe xplorExecutor) coach(accesToken string, familyId string) (*xplorentitie
 
 		var request = xe.config.generateRequest(http.MethodGet, "/coaches/"+familyId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPloreCoach](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPloreCoach](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
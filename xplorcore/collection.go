@@ -0,0 +1,195 @@
+package xplorcore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+func getCollection[T any](ctx context.Context, xe xplorExecutor, accesToken string, uri string, queryParams url.Values) (*xplorentities.Collection[T], *xplorentities.ErrorResponse) {
+	return getResource[xplorentities.Collection[T]](ctx, xe, accesToken, uri, queryParams)
+}
+
+// getResource decodes a GET on uri into T; ctx bounds the request together with the executor timeout
+func getResource[T any](ctx context.Context, xe xplorExecutor, accesToken string, uri string, queryParams url.Values) (*T, *xplorentities.ErrorResponse) {
+	var ctxWithTimeout, cancel = context.WithTimeout(ctx, xe.defaultTimeout)
+	defer cancel()
+	resultChan := make(chan util.RequestResult[*T], 1)
+
+	go func() {
+		formData := url.Values{}
+
+		var request = xe.config.generateRequest(http.MethodGet, uri, xe.generateHeaders(accesToken), queryParams, formData)
+		request = request.WithContext(ctxWithTimeout)
+		result := executeRequest[*T](ctxWithTimeout, xe, request)
+		resultChan <- result
+
+	}()
+	select {
+	case res := <-resultChan:
+		if res.Error == nil {
+			return res.Response, nil
+		}
+		return nil, res.Error
+	case <-ctxWithTimeout.Done():
+		return nil, &xplorentities.ErrorResponse{
+			Code:    http.StatusRequestTimeout,
+			Message: "Request timeout: operation cancelled after 10 seconds",
+		}
+	}
+}
+
+// executorFor returns an authenticated executor for requests on resource.
+// Resources that are not node scoped ignore nodeId, like NetworkNodes and Users do.
+func (xe *XplorProvider) executorFor(ctx context.Context, resource xplorentities.Resource, nodeId string) (*xplorExecutor, *xplorentities.ErrorResponse) {
+	if resource.NodeScoped() {
+		return xe.getExecutorFullyInitialized(ctx, nodeId)
+	}
+	executor := xe.getExecutor("")
+	if err := xe.authenticateIfNeeded(ctx, executor); err != nil {
+		xe.putExecutor(executor)
+		return nil, err
+	}
+	return executor, nil
+}
+
+// List fetches a page of the collection of T, e.g. List[xplorentities.XPlorClub](provider, nodeId, nil, pagination).
+// queryParams are sent as-is; build them with a search template query to have them validated.
+func List[T xplorentities.Entity](xe *XplorProvider, nodeId string, queryParams url.Values, pagination *xplorentities.XPlorPagination) (*xplorentities.Collection[T], *xplorentities.ErrorResponse) {
+	return ListContext[T](context.Background(), xe, nodeId, queryParams, pagination)
+}
+
+// ListContext is List with a caller context
+func ListContext[T xplorentities.Entity](ctx context.Context, xe *XplorProvider, nodeId string, queryParams url.Values, pagination *xplorentities.XPlorPagination) (*xplorentities.Collection[T], *xplorentities.ErrorResponse) {
+	var zero T
+	resource := zero.Resource()
+	executor, err := xe.executorFor(ctx, resource, nodeId)
+	if err != nil {
+		return nil, err
+	}
+	defer xe.putExecutor(executor)
+
+	params := xplorentities.BuildPaginationQueryParams(pagination)
+	for name, values := range queryParams {
+		params[name] = values
+	}
+	collection, err := getCollection[T](ctx, *executor, xe.token.Token.AccessToken, resource.Path(), params)
+	if err != nil {
+		return nil, &xplorentities.ErrorResponse{
+			Code:    err.Code,
+			Message: "Failed to get " + string(resource) + ": " + err.Message,
+		}
+	}
+
+	return collection, nil
+}
+
+// Resolve fetches the entity an IRI references, e.g. Resolve(provider, nodeId, xplorentities.IRIFrom[xplorentities.XPlorClub](class.Club))
+func Resolve[T xplorentities.Entity](xe *XplorProvider, nodeId string, iri xplorentities.IRI[T]) (*T, *xplorentities.ErrorResponse) {
+	return ResolveContext(context.Background(), xe, nodeId, iri)
+}
+
+// ResolveContext is Resolve with a caller context
+func ResolveContext[T xplorentities.Entity](ctx context.Context, xe *XplorProvider, nodeId string, iri xplorentities.IRI[T]) (*T, *xplorentities.ErrorResponse) {
+	uri, pathErr := iri.Path()
+	if pathErr != nil {
+		return nil, &xplorentities.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid IRI: " + pathErr.Error(),
+		}
+	}
+	return getEntity[T](ctx, xe, nodeId, uri)
+}
+
+// Get fetches the entity of type T with the given ID
+func Get[T xplorentities.Entity](xe *XplorProvider, nodeId string, id string) (*T, *xplorentities.ErrorResponse) {
+	return GetContext[T](context.Background(), xe, nodeId, id)
+}
+
+// GetContext is Get with a caller context
+func GetContext[T xplorentities.Entity](ctx context.Context, xe *XplorProvider, nodeId string, id string) (*T, *xplorentities.ErrorResponse) {
+	if strings.TrimSpace(id) == "" {
+		return nil, &xplorentities.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "ID is required",
+		}
+	}
+	var zero T
+	return getEntity[T](ctx, xe, nodeId, zero.Resource().Path()+"/"+url.PathEscape(id))
+}
+
+func getEntity[T xplorentities.Entity](ctx context.Context, xe *XplorProvider, nodeId string, uri string) (*T, *xplorentities.ErrorResponse) {
+	var zero T
+	executor, err := xe.executorFor(ctx, zero.Resource(), nodeId)
+	if err != nil {
+		return nil, err
+	}
+	defer xe.putExecutor(executor)
+
+	entity, err := getResource[T](ctx, *executor, xe.token.Token.AccessToken, uri, nil)
+	if err != nil {
+		return nil, &xplorentities.ErrorResponse{
+			Code:    err.Code,
+			Message: "Failed to get " + string(zero.Resource()) + ": " + err.Message,
+		}
+	}
+
+	return entity, nil
+}
+
+// countResponse keeps what Count needs from a single-item page
+type countResponse struct {
+	TotalItems *int                     `json:"hydra:totalItems"`
+	Members    []json.RawMessage        `json:"hydra:member"`
+	View       *xplorentities.HydraView `json:"hydra:view,omitempty"`
+}
+
+// Count returns the number of items of a collection matching filters by requesting a single-item page.
+// When the API omits hydra:totalItems the count is derived from the last page number.
+func (xe *XplorProvider) Count(ctx context.Context, nodeId string, resource xplorentities.Resource, filters url.Values) (int, *xplorentities.ErrorResponse) {
+	if err := checkSearchResource(resource); err != nil {
+		return 0, err
+	}
+	executor, err := xe.executorFor(ctx, resource, nodeId)
+	if err != nil {
+		return 0, err
+	}
+	defer xe.putExecutor(executor)
+
+	queryParams := url.Values{}
+	for name, values := range filters {
+		queryParams[name] = values
+	}
+	queryParams.Set("itemsPerPage", "1")
+	queryParams.Del("page")
+
+	page, err := getResource[countResponse](ctx, *executor, xe.token.Token.AccessToken, resource.Path(), queryParams)
+	if err != nil {
+		return 0, &xplorentities.ErrorResponse{
+			Code:    err.Code,
+			Message: "Failed to count " + string(resource) + ": " + err.Message,
+		}
+	}
+	if page.TotalItems != nil {
+		return *page.TotalItems, nil
+	}
+	if len(page.Members) == 0 {
+		return 0, nil
+	}
+	if page.View == nil {
+		return len(page.Members), nil
+	}
+	last, lastErr := page.View.LastPageNumber()
+	if lastErr != nil {
+		return 0, &xplorentities.ErrorResponse{
+			Code:    http.StatusBadGateway,
+			Message: "Failed to count " + string(resource) + ": " + lastErr.Error(),
+		}
+	}
+	return last, nil
+}
@@ -3,8 +3,13 @@ package xplorcore
 import (
 	"bytes"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
 )
 
 type neededHeaders struct {
@@ -19,7 +24,31 @@ type xplorConfig struct {
 	ClientID       string
 	ClientSecret   string
 	NeededHeaders  []neededHeaders
-	Debug          bool
+	// Debug logs every request as a cURL command and its full response at debug level,
+	// to Logger when set or to stderr otherwise; credentials and personal data are redacted
+	Debug bool
+	// Cache enables the read-through response cache when set (see NewLRUCache)
+	Cache Cache
+	// CacheTTLs sets the TTL per resource; nil uses DefaultCacheTTLs.
+	// Resources without a TTL are not stored but still benefit from request de-duplication.
+	CacheTTLs map[xplorentities.Resource]time.Duration
+	// NodeResolverTTL controls how long node-to-club resolutions stay fresh; zero never expires them
+	NodeResolverTTL time.Duration
+	// WarmNodes loads the whole network tree into the node resolver in the background at Init
+	WarmNodes bool
+	// Logger receives one structured event per API request when set; secrets and personal data are redacted
+	Logger *slog.Logger
+	// LogBodies adds the redacted request and response bodies to the log events
+	LogBodies bool
+	// LogBodyLimit caps each logged body in bytes; zero uses 2048
+	LogBodyLimit int
+	// MaxBodyBytes fails responses whose body exceeds this size; zero means no limit
+	MaxBodyBytes int64
+	// DecodeMode selects lenient decoding, which skips values of unexpected types with a warning,
+	// or strict decoding, which fails on fields missing from the entities; the default fails on type mismatches only
+	DecodeMode util.DecodeMode
+	// Telemetry enables spans and metrics for API calls when set (see the xplorotel module)
+	Telemetry Telemetry
 }
 
 func NewConfig(host string, apiVersion string, enterpriseName, clientID, clientSecret string, headers map[string]string, debug bool) *xplorConfig {
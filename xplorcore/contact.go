@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) contacts(accesToken string, params *xplorentities.XPlorContactsParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorContacts, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorContacts], 1)
 
@@ -23,7 +23,7 @@ func (xe xplorExecutor) contacts(accesToken string, params *xplorentities.XPlorC
 
 		var request = xe.config.generateRequest(http.MethodGet, "/contacts", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorContacts](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorContacts](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -42,7 +42,7 @@ func (xe xplorExecutor) contacts(accesToken string, params *xplorentities.XPlorC
 
 }
 func (xe xplorExecutor) contact(accesToken string, familyId string) (*xplorentities.XPlorContact, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorContact], 1)
 
@@ -51,7 +51,7 @@ func (xe xplorExecutor) contact(accesToken string, familyId string) (*xplorentit
 
 		var request = xe.config.generateRequest(http.MethodGet, "/contacts/"+familyId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorContact](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorContact](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) contactImages(accesToken string, params *xplorentities.XPlorContactImagesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorContactImages, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorContactImages], 1)
 
@@ -23,7 +23,7 @@ func (xe xplorExecutor) contactImages(accesToken string, params *xplorentities.X
 
 		var request = xe.config.generateRequest(http.MethodGet, "/files/contact_images", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorContactImages](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorContactImages](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -42,7 +42,7 @@ func (xe xplorExecutor) contactImages(accesToken string, params *xplorentities.X
 }
 
 func (xe xplorExecutor) contactImage(accesToken string, contactImageId string) (*xplorentities.XPlorContactImage, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorContactImage], 1)
 
@@ -51,7 +51,7 @@ func (xe xplorExecutor) contactImage(accesToken string, contactImageId string) (
 
 		var request = xe.config.generateRequest(http.MethodGet, "/files/contact_images/"+contactImageId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorContactImage](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorContactImage](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) contactTags(accesToken string, params *xplorentities.XPlorContactTagsParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorContactTags, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorContactTags], 1)
 
@@ -23,7 +23,7 @@ func (xe xplorExecutor) contactTags(accesToken string, params *xplorentities.XPl
 
 		var request = xe.config.generateRequest(http.MethodGet, "/contact_tags", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorContactTags](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorContactTags](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -42,7 +42,7 @@ func (xe xplorExecutor) contactTags(accesToken string, params *xplorentities.XPl
 
 }
 func (xe xplorExecutor) contactTag(accesToken string, contacTagId string) (*xplorentities.XPlorContactTag, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorContactTag], 1)
 
@@ -51,7 +51,7 @@ func (xe xplorExecutor) contactTag(accesToken string, contacTagId string) (*xplo
 
 		var request = xe.config.generateRequest(http.MethodGet, "/contact_tags/"+contacTagId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorContactTag](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorContactTag](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
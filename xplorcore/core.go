@@ -1,6 +1,8 @@
 package xplorcore
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
 	"strings"
 	"sync"
@@ -13,10 +15,12 @@ var xplorProviderInstace *XplorProvider = nil
 var syncOnce sync.Once
 
 type XplorProvider struct {
-	providers        *sync.Pool
-	token            *xplorentities.XPlorTokenWithTimestamp
-	authMutex        *sync.Mutex
-	nodeClubRelation map[string]string
+	providers    *sync.Pool
+	token        *xplorentities.XPlorTokenWithTimestamp
+	authMutex    *sync.Mutex
+	nodes        *NodeResolver
+	cache        *responseCache
+	interceptors *interceptors
 }
 type xplorExecutor struct {
 	config         *xplorConfig
@@ -24,19 +28,32 @@ type xplorExecutor struct {
 	defaultTimeout time.Duration
 	nodeId         *string
 	clubId         *string
+	cache          *responseCache
+	// refresh skips cached responses; the fresh response still replaces the cached one
+	refresh      bool
+	interceptors *interceptors
+	// ctx is the caller context of the request being made, nil for context.Background
+	ctx context.Context
 }
 
 func Init(cfg *xplorConfig) *XplorProvider {
 	syncOnce.Do(func() {
+		var cache = newResponseCache(cfg)
+		var hooks = &interceptors{}
 		xplorProviderInstace = &XplorProvider{
-			authMutex:        &sync.Mutex{},
-			nodeClubRelation: make(map[string]string),
+			authMutex:    &sync.Mutex{},
+			cache:        cache,
+			interceptors: hooks,
 			providers: &sync.Pool{
 				New: func() any {
-					return &xplorExecutor{config: cfg, client: http.DefaultClient, defaultTimeout: 30 * time.Second}
+					return &xplorExecutor{config: cfg, client: http.DefaultClient, defaultTimeout: 30 * time.Second, cache: cache, interceptors: hooks}
 				},
 			},
 		}
+		xplorProviderInstace.nodes = newNodeResolver(resolverSource{xplorProviderInstace}, cfg.NodeResolverTTL)
+		if cfg.WarmNodes {
+			go xplorProviderInstace.warmNodes()
+		}
 	})
 	return xplorProviderInstace
 }
@@ -50,6 +67,25 @@ func checkNodeId(nodeId string) *xplorentities.ErrorResponse {
 	return nil
 }
 
+// checkParams validates typed filters before any request is issued
+func checkParams[P xplorentities.QueryParams](params *P) *xplorentities.ErrorResponse {
+	if params == nil {
+		return nil
+	}
+	return invalidParams((*params).Validate())
+}
+
+// invalidParams converts a filter validation error into a 400 response
+func invalidParams(err error) *xplorentities.ErrorResponse {
+	if err == nil {
+		return nil
+	}
+	return &xplorentities.ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: "Invalid query parameters: " + strings.ReplaceAll(err.Error(), "\n", "; "),
+	}
+}
+
 func (pp XplorProvider) getExecutor(nodeId string) *xplorExecutor {
 	var executor = pp.providers.Get().(*xplorExecutor)
 	if strings.TrimSpace(nodeId) == "" {
@@ -61,8 +97,19 @@ func (pp XplorProvider) getExecutor(nodeId string) *xplorExecutor {
 }
 func (pp XplorProvider) putExecutor(executor *xplorExecutor) {
 	executor.nodeId = nil
+	executor.clubId = nil
+	executor.refresh = false
+	executor.ctx = nil
 	pp.providers.Put(executor)
 }
+
+// baseContext returns the caller context the executor requests derive from
+func (xe xplorExecutor) baseContext() context.Context {
+	if xe.ctx == nil {
+		return context.Background()
+	}
+	return xe.ctx
+}
 func (pp XplorProvider) Close() {
 	xplorProviderInstace = nil
 }
@@ -87,7 +134,10 @@ func (xp XplorProvider) needsAuthentication(token *xplorentities.XPlorTokenWithT
 
 	return !token.IsValid()
 }
-func (xe *XplorProvider) authenticateIfNeeded(executor *xplorExecutor) *xplorentities.ErrorResponse {
+
+// authenticateIfNeeded gets a token when the current one expired, and makes the executor requests derive from ctx
+func (xe *XplorProvider) authenticateIfNeeded(ctx context.Context, executor *xplorExecutor) *xplorentities.ErrorResponse {
+	executor.ctx = ctx
 
 	// Double-check locking pattern
 	xe.authMutex.Lock()
@@ -97,7 +147,14 @@ func (xe *XplorProvider) authenticateIfNeeded(executor *xplorExecutor) *xplorent
 	if xe.needsAuthentication(xe.token) {
 		var token *xplorentities.XPlorTokenResponse
 		var err *xplorentities.ErrorResponse
-		if token, err = executor.authenticate(); err != nil {
+		ctx, span := executor.config.startSpan(ctx, SpanAuthenticate)
+		token, err = executor.authenticate(ctx)
+		if executor.config.Telemetry != nil {
+			executor.config.Telemetry.RecordTokenRefresh(ctx, err == nil)
+		}
+		if err != nil {
+			span.SetError(err.Message)
+			span.End()
 			return &xplorentities.ErrorResponse{
 				Code:    err.Code,
 				Message: "Failed to authenticate: " + err.Message,
@@ -107,56 +164,41 @@ func (xe *XplorProvider) authenticateIfNeeded(executor *xplorExecutor) *xplorent
 			Token:      token,
 			ObtainedAt: time.Now(),
 		}
+		span.End()
+		if logger := executor.config.Logger; logger != nil {
+			logger.Info("xplor token refreshed", slog.Int("expires_in", token.ExpiresIn))
+		}
 	}
 
 	return nil
 
 }
-func (xe *XplorProvider) generateClubIdIfNeeded(executor *xplorExecutor, nodeId string) *xplorentities.ErrorResponse {
-	if clubId, ok := xe.nodeClubRelation[nodeId]; ok {
-		executor.clubId = &clubId
-		return nil
-	}
-	if xe.token != nil && executor.nodeId != nil && strings.TrimSpace(*executor.nodeId) != "" {
-		if _, ok := xe.nodeClubRelation[*executor.nodeId]; !ok {
-			node, err := executor.networkNode(xe.token.Token.AccessToken, *executor.nodeId)
-			if err != nil {
-				return err
-			}
-			var id, cErr = node.ClubIDValue()
-
-			if cErr != nil {
-				return &xplorentities.ErrorResponse{
-					Code:    404,
-					Message: "Failed to get club ID for node: " + cErr.Error(),
-				}
-			}
-			xe.nodeClubRelation[*executor.nodeId] = id
-			executor.clubId = &id
-
-		}
-	}
-	return nil
-}
-
-func (xe *XplorProvider) getExecutorFullyInitialized(nodeId string) (*xplorExecutor, *xplorentities.ErrorResponse) {
+func (xe *XplorProvider) getExecutorFullyInitialized(ctx context.Context, nodeId string) (*xplorExecutor, *xplorentities.ErrorResponse) {
 	if err := checkNodeId(nodeId); err != nil {
 		return nil, err
 	}
 	executor := xe.getExecutor(nodeId)
 
-	if err := xe.authenticateIfNeeded(executor); err != nil {
+	if err := xe.authenticateIfNeeded(ctx, executor); err != nil {
 		xe.putExecutor(executor)
 		return nil, err
 	}
-	if err := xe.generateClubIdIfNeeded(executor, nodeId); err != nil {
+	if err := xe.resolveClubId(ctx, executor, nodeId); err != nil {
 		xe.putExecutor(executor)
 		return nil, err
 	}
 	return executor, nil
 }
 func (xe *XplorProvider) Families(nodeId string, params *xplorentities.XPlorFamiliesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorFamilies, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.FamiliesContext(context.Background(), nodeId, params, pagination)
+}
+
+// FamiliesContext is Families with a caller context
+func (xe *XplorProvider) FamiliesContext(ctx context.Context, nodeId string, params *xplorentities.XPlorFamiliesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorFamilies, *xplorentities.ErrorResponse) {
+	if err := checkParams(params); err != nil {
+		return nil, err
+	}
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -174,13 +216,18 @@ func (xe *XplorProvider) Families(nodeId string, params *xplorentities.XPlorFami
 
 }
 func (xe *XplorProvider) Family(nodeId string, familyId string) (*xplorentities.XPlorFamily, *xplorentities.ErrorResponse) {
+	return xe.FamilyContext(context.Background(), nodeId, familyId)
+}
+
+// FamilyContext is Family with a caller context
+func (xe *XplorProvider) FamilyContext(ctx context.Context, nodeId string, familyId string) (*xplorentities.XPlorFamily, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(familyId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Family ID is required",
 		}
 	}
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -198,7 +245,12 @@ func (xe *XplorProvider) Family(nodeId string, familyId string) (*xplorentities.
 
 }
 func (xe *XplorProvider) Clubs(nodeId string) (*xplorentities.XPloreClubs, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.ClubsContext(context.Background(), nodeId)
+}
+
+// ClubsContext is Clubs with a caller context
+func (xe *XplorProvider) ClubsContext(ctx context.Context, nodeId string) (*xplorentities.XPloreClubs, *xplorentities.ErrorResponse) {
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -216,13 +268,18 @@ func (xe *XplorProvider) Clubs(nodeId string) (*xplorentities.XPloreClubs, *xplo
 
 }
 func (xe *XplorProvider) Club(nodeId string, clubId string) (*xplorentities.XPlorClub, *xplorentities.ErrorResponse) {
+	return xe.ClubContext(context.Background(), nodeId, clubId)
+}
+
+// ClubContext is Club with a caller context
+func (xe *XplorProvider) ClubContext(ctx context.Context, nodeId string, clubId string) (*xplorentities.XPlorClub, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(clubId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Club ID is required",
 		}
 	}
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -240,7 +297,12 @@ func (xe *XplorProvider) Club(nodeId string, clubId string) (*xplorentities.XPlo
 
 }
 func (xe *XplorProvider) Events(nodeId string, pagination *xplorentities.XPlorPagination, timeGap *xplorentities.XPlorTimeGap) (*xplorentities.XPlorEvents, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.EventsContext(context.Background(), nodeId, pagination, timeGap)
+}
+
+// EventsContext is Events with a caller context
+func (xe *XplorProvider) EventsContext(ctx context.Context, nodeId string, pagination *xplorentities.XPlorPagination, timeGap *xplorentities.XPlorTimeGap) (*xplorentities.XPlorEvents, *xplorentities.ErrorResponse) {
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -258,7 +320,15 @@ func (xe *XplorProvider) Events(nodeId string, pagination *xplorentities.XPlorPa
 
 }
 func (xe *XplorProvider) Activities(nodeId string, queryParams *xplorentities.XPlorActivitiesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorActivities, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.ActivitiesContext(context.Background(), nodeId, queryParams, pagination)
+}
+
+// ActivitiesContext is Activities with a caller context
+func (xe *XplorProvider) ActivitiesContext(ctx context.Context, nodeId string, queryParams *xplorentities.XPlorActivitiesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorActivities, *xplorentities.ErrorResponse) {
+	if err := checkParams(queryParams); err != nil {
+		return nil, err
+	}
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -275,13 +345,18 @@ func (xe *XplorProvider) Activities(nodeId string, queryParams *xplorentities.XP
 	return activities, nil
 }
 func (xe *XplorProvider) Activity(nodeId string, activityId string) (*xplorentities.XPlorActivity, *xplorentities.ErrorResponse) {
+	return xe.ActivityContext(context.Background(), nodeId, activityId)
+}
+
+// ActivityContext is Activity with a caller context
+func (xe *XplorProvider) ActivityContext(ctx context.Context, nodeId string, activityId string) (*xplorentities.XPlorActivity, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(activityId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Activity ID is required",
 		}
 	}
-	var executor, err = xe.getExecutorFullyInitialized(nodeId)
+	var executor, err = xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -299,7 +374,12 @@ func (xe *XplorProvider) Activity(nodeId string, activityId string) (*xplorentit
 
 }
 func (xd *XplorProvider) Studios(nodeId string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorStudios, *xplorentities.ErrorResponse) {
-	executor, err := xd.getExecutorFullyInitialized(nodeId)
+	return xd.StudiosContext(context.Background(), nodeId, pagination)
+}
+
+// StudiosContext is Studios with a caller context
+func (xd *XplorProvider) StudiosContext(ctx context.Context, nodeId string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorStudios, *xplorentities.ErrorResponse) {
+	executor, err := xd.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -317,13 +397,18 @@ func (xd *XplorProvider) Studios(nodeId string, pagination *xplorentities.XPlorP
 
 }
 func (xd *XplorProvider) Studio(nodeId string, studioId string) (*xplorentities.XPlorStudio, *xplorentities.ErrorResponse) {
+	return xd.StudioContext(context.Background(), nodeId, studioId)
+}
+
+// StudioContext is Studio with a caller context
+func (xd *XplorProvider) StudioContext(ctx context.Context, nodeId string, studioId string) (*xplorentities.XPlorStudio, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(studioId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Studio ID is required",
 		}
 	}
-	executor, err := xd.getExecutorFullyInitialized(nodeId)
+	executor, err := xd.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -341,7 +426,15 @@ func (xd *XplorProvider) Studio(nodeId string, studioId string) (*xplorentities.
 
 }
 func (xd *XplorProvider) Contacts(nodeId string, params *xplorentities.XPlorContactsParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorContacts, *xplorentities.ErrorResponse) {
-	executor, err := xd.getExecutorFullyInitialized(nodeId)
+	return xd.ContactsContext(context.Background(), nodeId, params, pagination)
+}
+
+// ContactsContext is Contacts with a caller context
+func (xd *XplorProvider) ContactsContext(ctx context.Context, nodeId string, params *xplorentities.XPlorContactsParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorContacts, *xplorentities.ErrorResponse) {
+	if err := checkParams(params); err != nil {
+		return nil, err
+	}
+	executor, err := xd.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -359,13 +452,18 @@ func (xd *XplorProvider) Contacts(nodeId string, params *xplorentities.XPlorCont
 
 }
 func (xd *XplorProvider) Contact(nodeId string, contactId string) (*xplorentities.XPlorContact, *xplorentities.ErrorResponse) {
+	return xd.ContactContext(context.Background(), nodeId, contactId)
+}
+
+// ContactContext is Contact with a caller context
+func (xd *XplorProvider) ContactContext(ctx context.Context, nodeId string, contactId string) (*xplorentities.XPlorContact, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(contactId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Contact ID is required",
 		}
 	}
-	executor, err := xd.getExecutorFullyInitialized(nodeId)
+	executor, err := xd.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -383,7 +481,15 @@ func (xd *XplorProvider) Contact(nodeId string, contactId string) (*xplorentitie
 
 }
 func (xd *XplorProvider) ContactImages(nodeId string, params *xplorentities.XPlorContactImagesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorContactImages, *xplorentities.ErrorResponse) {
-	executor, err := xd.getExecutorFullyInitialized(nodeId)
+	return xd.ContactImagesContext(context.Background(), nodeId, params, pagination)
+}
+
+// ContactImagesContext is ContactImages with a caller context
+func (xd *XplorProvider) ContactImagesContext(ctx context.Context, nodeId string, params *xplorentities.XPlorContactImagesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorContactImages, *xplorentities.ErrorResponse) {
+	if err := checkParams(params); err != nil {
+		return nil, err
+	}
+	executor, err := xd.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -400,6 +506,11 @@ func (xd *XplorProvider) ContactImages(nodeId string, params *xplorentities.XPlo
 	return contactImages, nil
 }
 func (xd *XplorProvider) ContactImage(nodeId string, contactImageId string) (*xplorentities.XPlorContactImage, *xplorentities.ErrorResponse) {
+	return xd.ContactImageContext(context.Background(), nodeId, contactImageId)
+}
+
+// ContactImageContext is ContactImage with a caller context
+func (xd *XplorProvider) ContactImageContext(ctx context.Context, nodeId string, contactImageId string) (*xplorentities.XPlorContactImage, *xplorentities.ErrorResponse) {
 	contactImageId = strings.TrimSpace(contactImageId)
 	if contactImageId == "" {
 		return nil, &xplorentities.ErrorResponse{
@@ -414,7 +525,7 @@ func (xd *XplorProvider) ContactImage(nodeId string, contactImageId string) (*xp
 			Message: "Invalid Contact Image ID: " + extractErr.Error(),
 		}
 	}
-	executor, err := xd.getExecutorFullyInitialized(nodeId)
+	executor, err := xd.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -431,7 +542,15 @@ func (xd *XplorProvider) ContactImage(nodeId string, contactImageId string) (*xp
 	return contactImage, nil
 }
 func (xe *XplorProvider) Subscriptions(nodeId string, params *xplorentities.XPlorSubscriptionsParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorSubscriptions, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.SubscriptionsContext(context.Background(), nodeId, params, pagination)
+}
+
+// SubscriptionsContext is Subscriptions with a caller context
+func (xe *XplorProvider) SubscriptionsContext(ctx context.Context, nodeId string, params *xplorentities.XPlorSubscriptionsParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorSubscriptions, *xplorentities.ErrorResponse) {
+	if err := checkParams(params); err != nil {
+		return nil, err
+	}
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -449,13 +568,18 @@ func (xe *XplorProvider) Subscriptions(nodeId string, params *xplorentities.XPlo
 
 }
 func (xe *XplorProvider) Subscription(nodeId string, subscriptionId string) (*xplorentities.XPlorSubscription, *xplorentities.ErrorResponse) {
+	return xe.SubscriptionContext(context.Background(), nodeId, subscriptionId)
+}
+
+// SubscriptionContext is Subscription with a caller context
+func (xe *XplorProvider) SubscriptionContext(ctx context.Context, nodeId string, subscriptionId string) (*xplorentities.XPlorSubscription, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(subscriptionId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Subscription ID is required",
 		}
 	}
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -473,7 +597,15 @@ func (xe *XplorProvider) Subscription(nodeId string, subscriptionId string) (*xp
 
 }
 func (xe *XplorProvider) Classes(nodeId string, params *xplorentities.XPlorClassesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorClasses, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.ClassesContext(context.Background(), nodeId, params, pagination)
+}
+
+// ClassesContext is Classes with a caller context
+func (xe *XplorProvider) ClassesContext(ctx context.Context, nodeId string, params *xplorentities.XPlorClassesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorClasses, *xplorentities.ErrorResponse) {
+	if err := checkParams(params); err != nil {
+		return nil, err
+	}
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -491,13 +623,18 @@ func (xe *XplorProvider) Classes(nodeId string, params *xplorentities.XPlorClass
 
 }
 func (xe *XplorProvider) Class(nodeId string, classId string) (*xplorentities.XPlorClass, *xplorentities.ErrorResponse) {
+	return xe.ClassContext(context.Background(), nodeId, classId)
+}
+
+// ClassContext is Class with a caller context
+func (xe *XplorProvider) ClassContext(ctx context.Context, nodeId string, classId string) (*xplorentities.XPlorClass, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(classId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Class ID is required",
 		}
 	}
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -515,10 +652,21 @@ func (xe *XplorProvider) Class(nodeId string, classId string) (*xplorentities.XP
 
 }
 func (xe *XplorProvider) NetworkNodes(pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorNetworkNodes, *xplorentities.ErrorResponse) {
+	return xe.NetworkNodesContext(context.Background(), pagination)
+}
+
+// NetworkNodesContext is NetworkNodes with a caller context
+func (xe *XplorProvider) NetworkNodesContext(ctx context.Context, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorNetworkNodes, *xplorentities.ErrorResponse) {
+	return xe.networkNodes(ctx, pagination, false)
+}
+
+// networkNodes lists the network nodes, bypassing the response cache when refresh is set
+func (xe *XplorProvider) networkNodes(ctx context.Context, pagination *xplorentities.XPlorPagination, refresh bool) (*xplorentities.XPlorNetworkNodes, *xplorentities.ErrorResponse) {
 	var executor = xe.getExecutor("")
 	defer xe.putExecutor(executor)
+	executor.refresh = refresh
 
-	if err := xe.authenticateIfNeeded(executor); err != nil {
+	if err := xe.authenticateIfNeeded(ctx, executor); err != nil {
 		return nil, err
 	}
 	networkNodes, err := executor.networkNodes(xe.token.Token.AccessToken, pagination)
@@ -532,6 +680,16 @@ func (xe *XplorProvider) NetworkNodes(pagination *xplorentities.XPlorPagination)
 	return networkNodes, nil
 }
 func (xe *XplorProvider) NetworkNode(nodeId string) (*xplorentities.XPlorNetworkNode, *xplorentities.ErrorResponse) {
+	return xe.NetworkNodeContext(context.Background(), nodeId)
+}
+
+// NetworkNodeContext is NetworkNode with a caller context
+func (xe *XplorProvider) NetworkNodeContext(ctx context.Context, nodeId string) (*xplorentities.XPlorNetworkNode, *xplorentities.ErrorResponse) {
+	return xe.networkNode(ctx, nodeId, false)
+}
+
+// networkNode gets a network node, bypassing the response cache when refresh is set
+func (xe *XplorProvider) networkNode(ctx context.Context, nodeId string, refresh bool) (*xplorentities.XPlorNetworkNode, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(nodeId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
@@ -540,8 +698,9 @@ func (xe *XplorProvider) NetworkNode(nodeId string) (*xplorentities.XPlorNetwork
 	}
 	var executor = xe.getExecutor("")
 	defer xe.putExecutor(executor)
+	executor.refresh = refresh
 
-	if err := xe.authenticateIfNeeded(executor); err != nil {
+	if err := xe.authenticateIfNeeded(ctx, executor); err != nil {
 		return nil, err
 	}
 	networkNode, err := executor.networkNode(xe.token.Token.AccessToken, nodeId)
@@ -556,7 +715,12 @@ func (xe *XplorProvider) NetworkNode(nodeId string) (*xplorentities.XPlorNetwork
 }
 
 func (xe *XplorProvider) Attendees(nodeId string, classId *string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorAttendees, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.AttendeesContext(context.Background(), nodeId, classId, pagination)
+}
+
+// AttendeesContext is Attendees with a caller context
+func (xe *XplorProvider) AttendeesContext(ctx context.Context, nodeId string, classId *string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorAttendees, *xplorentities.ErrorResponse) {
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -574,7 +738,12 @@ func (xe *XplorProvider) Attendees(nodeId string, classId *string, pagination *x
 
 }
 func (xe *XplorProvider) Coaches(nodeId string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPloreCoaches, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.CoachesContext(context.Background(), nodeId, pagination)
+}
+
+// CoachesContext is Coaches with a caller context
+func (xe *XplorProvider) CoachesContext(ctx context.Context, nodeId string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPloreCoaches, *xplorentities.ErrorResponse) {
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -592,13 +761,18 @@ func (xe *XplorProvider) Coaches(nodeId string, pagination *xplorentities.XPlorP
 
 }
 func (xe *XplorProvider) Coach(nodeId string, coachId string) (*xplorentities.XPloreCoach, *xplorentities.ErrorResponse) {
+	return xe.CoachContext(context.Background(), nodeId, coachId)
+}
+
+// CoachContext is Coach with a caller context
+func (xe *XplorProvider) CoachContext(ctx context.Context, nodeId string, coachId string) (*xplorentities.XPloreCoach, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(coachId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Coach ID is required",
 		}
 	}
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -616,7 +790,12 @@ func (xe *XplorProvider) Coach(nodeId string, coachId string) (*xplorentities.XP
 
 }
 func (xe *XplorProvider) Articles(nodeId string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorArticles, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.ArticlesContext(context.Background(), nodeId, pagination)
+}
+
+// ArticlesContext is Articles with a caller context
+func (xe *XplorProvider) ArticlesContext(ctx context.Context, nodeId string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorArticles, *xplorentities.ErrorResponse) {
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -634,13 +813,18 @@ func (xe *XplorProvider) Articles(nodeId string, pagination *xplorentities.XPlor
 
 }
 func (xe *XplorProvider) Article(nodeId string, articleId string) (*xplorentities.XPlorArticle, *xplorentities.ErrorResponse) {
+	return xe.ArticleContext(context.Background(), nodeId, articleId)
+}
+
+// ArticleContext is Article with a caller context
+func (xe *XplorProvider) ArticleContext(ctx context.Context, nodeId string, articleId string) (*xplorentities.XPlorArticle, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(articleId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Article ID is required",
 		}
 	}
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -658,7 +842,15 @@ func (xe *XplorProvider) Article(nodeId string, articleId string) (*xplorentitie
 
 }
 func (xe *XplorProvider) Recurrences(nodeId string, params *xplorentities.XPlorRecurrencesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorRecurrences, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.RecurrencesContext(context.Background(), nodeId, params, pagination)
+}
+
+// RecurrencesContext is Recurrences with a caller context
+func (xe *XplorProvider) RecurrencesContext(ctx context.Context, nodeId string, params *xplorentities.XPlorRecurrencesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorRecurrences, *xplorentities.ErrorResponse) {
+	if err := checkParams(params); err != nil {
+		return nil, err
+	}
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -676,13 +868,18 @@ func (xe *XplorProvider) Recurrences(nodeId string, params *xplorentities.XPlorR
 
 }
 func (xe *XplorProvider) Recurrence(nodeId string, recurrenceId string) (*xplorentities.XPlorRecurrence, *xplorentities.ErrorResponse) {
+	return xe.RecurrenceContext(context.Background(), nodeId, recurrenceId)
+}
+
+// RecurrenceContext is Recurrence with a caller context
+func (xe *XplorProvider) RecurrenceContext(ctx context.Context, nodeId string, recurrenceId string) (*xplorentities.XPlorRecurrence, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(recurrenceId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Recurrence ID is required",
 		}
 	}
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -701,13 +898,18 @@ func (xe *XplorProvider) Recurrence(nodeId string, recurrenceId string) (*xplore
 }
 
 func (xe *XplorProvider) ClassType(nodeId string, classTypeId string) (*xplorentities.XPlorClassType, *xplorentities.ErrorResponse) {
+	return xe.ClassTypeContext(context.Background(), nodeId, classTypeId)
+}
+
+// ClassTypeContext is ClassType with a caller context
+func (xe *XplorProvider) ClassTypeContext(ctx context.Context, nodeId string, classTypeId string) (*xplorentities.XPlorClassType, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(classTypeId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Class Type ID is required",
 		}
 	}
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -725,7 +927,12 @@ func (xe *XplorProvider) ClassType(nodeId string, classTypeId string) (*xplorent
 }
 
 func (xe *XplorProvider) CounterLines(nodeId string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorCounterLines, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.CounterLinesContext(context.Background(), nodeId, pagination)
+}
+
+// CounterLinesContext is CounterLines with a caller context
+func (xe *XplorProvider) CounterLinesContext(ctx context.Context, nodeId string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorCounterLines, *xplorentities.ErrorResponse) {
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -743,13 +950,18 @@ func (xe *XplorProvider) CounterLines(nodeId string, pagination *xplorentities.X
 
 }
 func (xe *XplorProvider) CounterLine(nodeId string, counterLineId string) (*xplorentities.XPlorCounterLine, *xplorentities.ErrorResponse) {
+	return xe.CounterLineContext(context.Background(), nodeId, counterLineId)
+}
+
+// CounterLineContext is CounterLine with a caller context
+func (xe *XplorProvider) CounterLineContext(ctx context.Context, nodeId string, counterLineId string) (*xplorentities.XPlorCounterLine, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(counterLineId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Counter Line ID is required",
 		}
 	}
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -766,7 +978,15 @@ func (xe *XplorProvider) CounterLine(nodeId string, counterLineId string) (*xplo
 
 }
 func (xe *XplorProvider) ContactTags(nodeId string, params *xplorentities.XPlorContactTagsParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorContactTags, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.ContactTagsContext(context.Background(), nodeId, params, pagination)
+}
+
+// ContactTagsContext is ContactTags with a caller context
+func (xe *XplorProvider) ContactTagsContext(ctx context.Context, nodeId string, params *xplorentities.XPlorContactTagsParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorContactTags, *xplorentities.ErrorResponse) {
+	if err := checkParams(params); err != nil {
+		return nil, err
+	}
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -784,13 +1004,18 @@ func (xe *XplorProvider) ContactTags(nodeId string, params *xplorentities.XPlorC
 
 }
 func (xe *XplorProvider) ContactTag(nodeId string, contactTagId string) (*xplorentities.XPlorContactTag, *xplorentities.ErrorResponse) {
+	return xe.ContactTagContext(context.Background(), nodeId, contactTagId)
+}
+
+// ContactTagContext is ContactTag with a caller context
+func (xe *XplorProvider) ContactTagContext(ctx context.Context, nodeId string, contactTagId string) (*xplorentities.XPlorContactTag, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(contactTagId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Contact Tag ID is required",
 		}
 	}
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -808,10 +1033,15 @@ func (xe *XplorProvider) ContactTag(nodeId string, contactTagId string) (*xplore
 
 }
 func (xe *XplorProvider) Users(pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorUsers, *xplorentities.ErrorResponse) {
+	return xe.UsersContext(context.Background(), pagination)
+}
+
+// UsersContext is Users with a caller context
+func (xe *XplorProvider) UsersContext(ctx context.Context, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorUsers, *xplorentities.ErrorResponse) {
 	var executor = xe.getExecutor("")
 	defer xe.putExecutor(executor)
 
-	if err := xe.authenticateIfNeeded(executor); err != nil {
+	if err := xe.authenticateIfNeeded(ctx, executor); err != nil {
 		return nil, err
 	}
 	users, err := executor.users(xe.token.Token.AccessToken, pagination)
@@ -826,6 +1056,11 @@ func (xe *XplorProvider) Users(pagination *xplorentities.XPlorPagination) (*xplo
 
 }
 func (xe *XplorProvider) User(userId string) (*xplorentities.XPlorUser, *xplorentities.ErrorResponse) {
+	return xe.UserContext(context.Background(), userId)
+}
+
+// UserContext is User with a caller context
+func (xe *XplorProvider) UserContext(ctx context.Context, userId string) (*xplorentities.XPlorUser, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(userId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
@@ -835,7 +1070,7 @@ func (xe *XplorProvider) User(userId string) (*xplorentities.XPlorUser, *xploren
 	var executor = xe.getExecutor("")
 	defer xe.putExecutor(executor)
 
-	if err := xe.authenticateIfNeeded(executor); err != nil {
+	if err := xe.authenticateIfNeeded(ctx, executor); err != nil {
 		return nil, err
 	}
 	user, err := executor.user(xe.token.Token.AccessToken, userId)
@@ -850,7 +1085,15 @@ func (xe *XplorProvider) User(userId string) (*xplorentities.XPlorUser, *xploren
 
 }
 func (xe *XplorProvider) Zones(nodeId string, params *xplorentities.XPlorZonesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorZones, *xplorentities.ErrorResponse) {
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	return xe.ZonesContext(context.Background(), nodeId, params, pagination)
+}
+
+// ZonesContext is Zones with a caller context
+func (xe *XplorProvider) ZonesContext(ctx context.Context, nodeId string, params *xplorentities.XPlorZonesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorZones, *xplorentities.ErrorResponse) {
+	if err := checkParams(params); err != nil {
+		return nil, err
+	}
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -868,13 +1111,18 @@ func (xe *XplorProvider) Zones(nodeId string, params *xplorentities.XPlorZonesPa
 
 }
 func (xe *XplorProvider) Zone(nodeId string, zoneId string) (*xplorentities.XPlorZone, *xplorentities.ErrorResponse) {
+	return xe.ZoneContext(context.Background(), nodeId, zoneId)
+}
+
+// ZoneContext is Zone with a caller context
+func (xe *XplorProvider) ZoneContext(ctx context.Context, nodeId string, zoneId string) (*xplorentities.XPlorZone, *xplorentities.ErrorResponse) {
 	if strings.TrimSpace(zoneId) == "" {
 		return nil, &xplorentities.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "Zone ID is required",
 		}
 	}
-	executor, err := xe.getExecutorFullyInitialized(nodeId)
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) counterLines(accesToken string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorCounterLines, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorCounterLines], 1)
 
@@ -20,7 +20,7 @@ func (xe xplorExecutor) counterLines(accesToken string, pagination *xplorentitie
 
 		var request = xe.config.generateRequest(http.MethodGet, "/counter_lines", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorCounterLines](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorCounterLines](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -39,7 +39,7 @@ func (xe xplorExecutor) counterLines(accesToken string, pagination *xplorentitie
 
 }
 func (xe xplorExecutor) counterLine(accesToken string, familyId string) (*xplorentities.XPlorCounterLine, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorCounterLine], 1)
 
@@ -48,7 +48,7 @@ func (xe xplorExecutor) counterLine(accesToken string, familyId string) (*xplore
 
 		var request = xe.config.generateRequest(http.MethodGet, "/counter_lines/"+familyId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorCounterLine](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorCounterLine](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) events(accesToken string, pagination *xplorentities.XPlorPagination, timeGap *xplorentities.XPlorTimeGap) (*xplorentities.XPlorEvents, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorEvents], 1)
 
@@ -20,7 +20,7 @@ func (xe xplorExecutor) events(accesToken string, pagination *xplorentities.XPlo
 
 		var request = xe.config.generateRequest(http.MethodGet, "/events", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorEvents](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorEvents](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
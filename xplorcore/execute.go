@@ -0,0 +1,169 @@
+package xplorcore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// warningsHolder is implemented by the collections, which keep the decode warnings of their page
+type warningsHolder interface {
+	SetWarnings(warnings []util.DecodeWarning)
+}
+
+// executeRequest runs a request through the executor pipeline and decodes the response into T
+func executeRequest[T any](ctx context.Context, xe xplorExecutor, request *http.Request) util.RequestResult[T] {
+	resource, _ := xplorentities.ResourceFromPath(request.URL.Path)
+	body, store, err := xe.fetch(request)
+	var result util.RequestResult[T]
+	if err != nil {
+		result.Error = err
+	} else {
+		result = util.DecodeResponseMode[T](body, xe.config.DecodeMode)
+		if result.Error == nil && store != nil {
+			store()
+		}
+		xe.logDecodeWarnings(request, resource, result.Warnings)
+		if holder, ok := any(result.Response).(warningsHolder); ok && len(result.Warnings) > 0 {
+			holder.SetWarnings(result.Warnings)
+		}
+	}
+	observed := Result{Request: request, Resource: resource, Error: result.Error, Warnings: result.Warnings}
+	if result.Error == nil {
+		observed.Value = result.Response
+	}
+	xe.interceptors.observe(ctx, observed)
+	return result
+}
+
+// fetch returns the raw response body, going through the response cache when enabled.
+// A body just received comes with a store func caching it, which the caller runs once the body decoded,
+// so a 2xx body that fails to decode is not served for the whole TTL. It is nil for cached bodies
+// and for responses to requests whose URL a middleware rewrote, since their key is the original URL.
+func (xe xplorExecutor) fetch(request *http.Request) ([]byte, func(), *xplorentities.ErrorResponse) {
+	resource, _ := xplorentities.ResourceFromPath(request.URL.Path)
+	if xe.cache == nil || request.Method != http.MethodGet {
+		body, _, err := xe.send(request, resource)
+		return body, nil, err
+	}
+
+	nodeId := ""
+	if xe.nodeId != nil {
+		nodeId = *xe.nodeId
+	}
+	key := cacheKey(resource, nodeId, request.URL)
+	sendForStore := func(request *http.Request) ([]byte, func(), *xplorentities.ErrorResponse) {
+		body, rewritten, err := xe.send(request, resource)
+		if err != nil {
+			return nil, nil, err
+		}
+		ttl := xe.cache.ttls[resource]
+		if rewritten || ttl <= 0 {
+			return body, nil, nil
+		}
+		return body, func() { xe.cache.store.Set(key, body, ttl) }, nil
+	}
+	if xe.refresh {
+		return sendForStore(request)
+	}
+	if body, ok := xe.cache.store.Get(key); ok {
+		xe.logCacheHit(request, resource)
+		xe.recordCacheLookup(request, resource, true)
+		return body, nil, nil
+	}
+	xe.recordCacheLookup(request, resource, false)
+
+	// The shared request outlives the caller that started it, so it gets its own timeout instead of that caller's
+	return xe.cache.flights.do(request.Context(), key, func() ([]byte, func(), *xplorentities.ErrorResponse) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(request.Context()), xe.defaultTimeout)
+		defer cancel()
+		return sendForStore(request.WithContext(ctx))
+	})
+}
+
+// send performs the HTTP exchange and returns the response body, reporting whether a middleware changed the URL
+func (xe xplorExecutor) send(request *http.Request, resource xplorentities.Resource) ([]byte, bool, *xplorentities.ErrorResponse) {
+	target := request.URL.String()
+	response, sent, err := xe.exchange(request, resource, false)
+	if err != nil {
+		return nil, false, err
+	}
+	return response.Body, sent.URL.String() != target, nil
+}
+
+// exchange performs the HTTP exchange through the middleware chain and logs it.
+// With stream set, a successful body is left unread in response.Stream unless a middleware answered with Body.
+// It also returns the request as the middlewares sent it; non-2xx responses always come with an error.
+func (xe xplorExecutor) exchange(request *http.Request, resource xplorentities.Resource, stream bool) (*util.RawResponse, *http.Request, *xplorentities.ErrorResponse) {
+	ctx, span := xe.config.startSpan(request.Context(), SpanRequest, xe.requestSpanAttrs(request, resource)...)
+	request = request.WithContext(ctx)
+
+	// The exchange is logged as the middlewares left it; short-circuited requests keep the original
+	sent := request
+	var requestBody []byte
+	terminal := func(request *http.Request) (*util.RawResponse, *xplorentities.ErrorResponse) {
+		sent = request
+		if xe.config.Logger != nil && xe.config.LogBodies && request.Body != nil {
+			requestBody, _ = io.ReadAll(request.Body)
+			request.Body.Close()
+			request.Body = io.NopCloser(bytes.NewReader(requestBody))
+		}
+		if stream {
+			return util.OpenRawResponse(xe.client, request, xe.config.debugLogger(), xe.config.MaxBodyBytes)
+		}
+		return util.FetchRawResponseLimit(xe.client, request, xe.config.debugLogger(), xe.config.MaxBodyBytes)
+	}
+
+	start := time.Now()
+	response, err := xe.interceptors.chain(terminal)(request)
+	latency := time.Since(start)
+	if response == nil && err == nil {
+		err = &xplorentities.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Middleware returned neither a response nor an error",
+		}
+	}
+	if err == nil && (response.StatusCode < 200 || response.StatusCode >= 300) {
+		if response.Stream != nil {
+			response.Stream.Close()
+		}
+		err = &xplorentities.ErrorResponse{
+			Code:    response.StatusCode,
+			Message: "Response: " + string(response.Body),
+		}
+	}
+	if err == nil && response.Stream != nil {
+		// A streamed exchange lasts until its body is read, so it is logged and traced on Close
+		response.Stream = &finishingBody{ReadCloser: response.Stream, finish: func() {
+			latency := time.Since(start)
+			xe.logExchange(sent, resource, requestBody, response, nil, latency)
+			xe.traceExchange(sent, span, resource, response, nil, latency)
+		}}
+		return response, sent, nil
+	}
+	xe.logExchange(sent, resource, requestBody, response, err, latency)
+	xe.traceExchange(sent, span, resource, response, err, latency)
+	if err != nil {
+		return nil, sent, err
+	}
+	return response, sent, nil
+}
+
+// finishingBody calls finish once, when the body is closed
+type finishingBody struct {
+	io.ReadCloser
+	once   sync.Once
+	finish func()
+}
+
+func (b *finishingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.finish)
+	return err
+}
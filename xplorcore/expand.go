@@ -0,0 +1,125 @@
+package xplorcore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// expandConcurrency bounds the referenced resources fetched in parallel by Expand
+const expandConcurrency = 4
+
+// Expand resolves the named relations of target and attaches them to its Embedded field,
+// e.g. provider.Expand(ctx, nodeId, classes, "coach", "studio", "activity").
+// target is anything accepted by xplorentities.LinkedItems. Each distinct reference is fetched once,
+// through the response cache when it is enabled; unset references are skipped.
+func (xe *XplorProvider) Expand(ctx context.Context, nodeId string, target any, relations ...string) *xplorentities.ErrorResponse {
+	items, ok := xplorentities.LinkedItems(target)
+	if !ok {
+		return &xplorentities.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Expand does not support the given target",
+		}
+	}
+	if len(items) == 0 || len(relations) == 0 {
+		return nil
+	}
+
+	// Group the decoders of every item by referenced path
+	wanted := make(map[string]bool, len(relations))
+	for _, name := range relations {
+		wanted[name] = true
+	}
+	attachments := make(map[string][]func([]byte) error)
+	known := make(map[string]bool)
+	for _, item := range items {
+		for _, relation := range item.Relations() {
+			known[relation.Name] = true
+			if !wanted[relation.Name] {
+				continue
+			}
+			if path := relation.Path(); path != "" {
+				attachments[path] = append(attachments[path], relation.Attach)
+			}
+		}
+	}
+	for _, name := range relations {
+		if !known[name] {
+			available := make([]string, 0, len(known))
+			for k := range known {
+				available = append(available, k)
+			}
+			sort.Strings(available)
+			return &xplorentities.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Unknown relation " + name + ", expected one of " + strings.Join(available, ", "),
+			}
+		}
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	executor, err := xe.getExecutorFullyInitialized(ctx, nodeId)
+	if err != nil {
+		return err
+	}
+	defer xe.putExecutor(executor)
+
+	paths := make([]string, 0, len(attachments))
+	for path := range attachments {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	bodies := make([]json.RawMessage, len(paths))
+	errs := make([]*xplorentities.ErrorResponse, len(paths))
+	semaphore := make(chan struct{}, expandConcurrency)
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = &xplorentities.ErrorResponse{Code: http.StatusRequestTimeout, Message: "Expansion cancelled: " + ctx.Err().Error()}
+				return
+			}
+			defer func() { <-semaphore }()
+
+			body, fetchErr := getResource[json.RawMessage](ctx, *executor, xe.token.Token.AccessToken, path, nil)
+			if fetchErr != nil {
+				errs[i] = &xplorentities.ErrorResponse{Code: fetchErr.Code, Message: "Failed to expand " + path + ": " + fetchErr.Message}
+				return
+			}
+			bodies[i] = *body
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return &xplorentities.ErrorResponse{Code: http.StatusRequestTimeout, Message: "Expansion cancelled: " + ctx.Err().Error()}
+	}
+	for i, path := range paths {
+		for _, attach := range attachments[path] {
+			if decodeErr := attach(bodies[i]); decodeErr != nil {
+				return &xplorentities.ErrorResponse{
+					Code:    http.StatusInternalServerError,
+					Message: "Failed to decode " + path + ": " + decodeErr.Error(),
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) families(accesToken string, params *xplorentities.XPlorFamiliesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorFamilies, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorFamilies], 1)
 
@@ -23,7 +23,7 @@ func (xe xplorExecutor) families(accesToken string, params *xplorentities.XPlorF
 
 		var request = xe.config.generateRequest(http.MethodGet, "/families", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorFamilies](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorFamilies](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -42,7 +42,7 @@ func (xe xplorExecutor) families(accesToken string, params *xplorentities.XPlorF
 
 }
 func (xe xplorExecutor) family(accesToken string, familyId string) (*xplorentities.XPlorFamily, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorFamily], 1)
 
@@ -51,7 +51,7 @@ func (xe xplorExecutor) family(accesToken string, familyId string) (*xplorentiti
 
 		var request = xe.config.generateRequest(http.MethodGet, "/families/"+familyId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorFamily](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorFamily](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
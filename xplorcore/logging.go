@@ -0,0 +1,101 @@
+package xplorcore
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+const defaultLogBodyLimit = 2048
+
+func (xc *xplorConfig) logBodyLimit() int {
+	if xc.LogBodyLimit > 0 {
+		return xc.LogBodyLimit
+	}
+	return defaultLogBodyLimit
+}
+
+// debugLogger returns where Debug output goes: Logger when set, util.DebugLogger otherwise; nil when Debug is off
+func (xc *xplorConfig) debugLogger() *slog.Logger {
+	if !xc.Debug {
+		return nil
+	}
+	if xc.Logger != nil {
+		return xc.Logger
+	}
+	return util.DebugLogger()
+}
+
+// requestAttrs describes a request without credentials or personal data
+func (xe xplorExecutor) requestAttrs(request *http.Request, resource xplorentities.Resource) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("method", request.Method),
+		slog.String("path", request.URL.Path),
+		slog.String("resource", string(resource)),
+	}
+	if request.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String("query", util.RedactValues(request.URL.Query()).Encode()))
+	}
+	if xe.nodeId != nil {
+		attrs = append(attrs, slog.String("node_id", *xe.nodeId))
+	}
+	if xe.clubId != nil {
+		attrs = append(attrs, slog.String("club_id", *xe.clubId))
+	}
+	return attrs
+}
+
+// logExchange emits one event per HTTP exchange: debug on success, warn on 4xx and error on 5xx or transport failures
+func (xe xplorExecutor) logExchange(request *http.Request, resource xplorentities.Resource, requestBody []byte, response *util.RawResponse, err *xplorentities.ErrorResponse, latency time.Duration) {
+	logger := xe.config.Logger
+	if logger == nil {
+		return
+	}
+	level := slog.LevelDebug
+	attrs := append(xe.requestAttrs(request, resource), slog.Duration("latency", latency))
+	switch {
+	case response == nil:
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", err.Message))
+	case response.StatusCode >= 500:
+		level = slog.LevelError
+	case err != nil:
+		level = slog.LevelWarn
+	}
+	if response != nil {
+		attrs = append(attrs, slog.Int("status", response.StatusCode))
+	}
+	if xe.config.LogBodies {
+		limit := xe.config.logBodyLimit()
+		if len(requestBody) > 0 {
+			attrs = append(attrs, slog.String("request_body", util.TruncateBody(util.RedactBody(requestBody), limit)))
+		}
+		if response != nil && len(response.Body) > 0 {
+			attrs = append(attrs, slog.String("response_body", util.TruncateBody(util.RedactBody(response.Body), limit)))
+		}
+	}
+	logger.LogAttrs(request.Context(), level, "xplor request", attrs...)
+}
+
+// logDecodeWarnings reports the values lenient or strict decoding flagged; values are never logged
+func (xe xplorExecutor) logDecodeWarnings(request *http.Request, resource xplorentities.Resource, warnings []util.DecodeWarning) {
+	if xe.config.Logger == nil || len(warnings) == 0 {
+		return
+	}
+	attrs := append(xe.requestAttrs(request, resource),
+		slog.Int("count", len(warnings)),
+		slog.String("warnings", util.FormatWarnings(warnings)),
+	)
+	xe.config.Logger.LogAttrs(request.Context(), slog.LevelWarn, "xplor decode warnings", attrs...)
+}
+
+// logCacheHit records a request answered from the response cache
+func (xe xplorExecutor) logCacheHit(request *http.Request, resource xplorentities.Resource) {
+	if xe.config.Logger == nil {
+		return
+	}
+	xe.config.Logger.LogAttrs(request.Context(), slog.LevelDebug, "xplor cache hit", xe.requestAttrs(request, resource)...)
+}
@@ -0,0 +1,97 @@
+package xplorcore
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// Handler performs one API exchange. The response is nil when none was received;
+// non-2xx responses are returned together with an error, like util.FetchRawResponse.
+type Handler func(request *http.Request) (*util.RawResponse, *xplorentities.ErrorResponse)
+
+// Middleware wraps the handler sending every API request, token requests included.
+// It may change the request (headers, query) before calling next, inspect or replace the response,
+// or return without calling next to answer with a canned response (see CannedResponse).
+// Responses served from the response cache do not go through the middleware chain, and responses
+// to requests whose URL a middleware changed are not cached. A non-2xx response returned without
+// an error is reported as one.
+type Middleware func(next Handler) Handler
+
+// Result is a decoded API response handed to result observers
+type Result struct {
+	Request  *http.Request
+	Resource xplorentities.Resource
+	// Value is the decoded response, e.g. *xplorentities.XPlorClasses; nil on error
+	Value any
+	Error *xplorentities.ErrorResponse
+	// Warnings lists the values skipped by lenient decoding or the unknown fields found by strict decoding
+	Warnings []util.DecodeWarning
+}
+
+// ResultObserver is called after every API response has been decoded.
+// It must not modify Value, which is returned to the caller as-is.
+type ResultObserver func(ctx context.Context, result Result)
+
+// interceptors holds the middleware chain and the result observers shared by the executors
+type interceptors struct {
+	mutex       sync.RWMutex
+	middlewares []Middleware
+	observers   []ResultObserver
+}
+
+// Use appends middlewares to the chain around every request.
+// The first middleware registered is the outermost one, seeing the request first and the response last.
+func (xe *XplorProvider) Use(middlewares ...Middleware) {
+	xe.interceptors.mutex.Lock()
+	defer xe.interceptors.mutex.Unlock()
+	xe.interceptors.middlewares = append(xe.interceptors.middlewares, middlewares...)
+}
+
+// Observe registers observers of the decoded responses, called in registration order
+func (xe *XplorProvider) Observe(observers ...ResultObserver) {
+	xe.interceptors.mutex.Lock()
+	defer xe.interceptors.mutex.Unlock()
+	xe.interceptors.observers = append(xe.interceptors.observers, observers...)
+}
+
+// chain wraps terminal with the registered middlewares
+func (i *interceptors) chain(terminal Handler) Handler {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	handler := terminal
+	for index := len(i.middlewares) - 1; index >= 0; index-- {
+		handler = i.middlewares[index](handler)
+	}
+	return handler
+}
+
+// observe reports a decoded response to the observers
+func (i *interceptors) observe(ctx context.Context, result Result) {
+	i.mutex.RLock()
+	observers := i.observers
+	i.mutex.RUnlock()
+	for _, observer := range observers {
+		observer(ctx, result)
+	}
+}
+
+// CannedResponse builds a handler result for a JSON body without sending anything,
+// reporting non-2xx statuses as errors the same way real responses are
+func CannedResponse(status int, body []byte) (*util.RawResponse, *xplorentities.ErrorResponse) {
+	response := &util.RawResponse{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/ld+json"}},
+		Body:       body,
+	}
+	if status < 200 || status >= 300 {
+		return response, &xplorentities.ErrorResponse{
+			Code:    status,
+			Message: "Response: " + string(body),
+		}
+	}
+	return response, nil
+}
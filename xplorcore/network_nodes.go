@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) networkNodes(accessToken string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorNetworkNodes, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorNetworkNodes], 1)
 	headers := map[string]string{
@@ -23,7 +23,7 @@ func (xe xplorExecutor) networkNodes(accessToken string, pagination *xplorentiti
 
 		var request = xe.config.generateRequest(http.MethodGet, "/network_nodes", headers, queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorNetworkNodes](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorNetworkNodes](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -42,7 +42,7 @@ func (xe xplorExecutor) networkNodes(accessToken string, pagination *xplorentiti
 
 }
 func (xe xplorExecutor) networkNode(accessToken string, networkId string) (*xplorentities.XPlorNetworkNode, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorNetworkNode], 1)
 
@@ -55,7 +55,7 @@ func (xe xplorExecutor) networkNode(accessToken string, networkId string) (*xplo
 
 		var request = xe.config.generateRequest(http.MethodGet, "/network_nodes/"+networkId, headers, nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorNetworkNode](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorNetworkNode](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
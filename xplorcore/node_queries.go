@@ -0,0 +1,232 @@
+package xplorcore
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// NodeQueryOptions tunes queries fanned out over the club nodes of a subtree
+type NodeQueryOptions struct {
+	Concurrency  int // Club nodes queried in parallel (default 4)
+	ItemsPerPage int // Page size used for each club node (default 100)
+}
+
+const (
+	defaultNodeQueryConcurrency = 4
+	defaultNodeQueryPageSize    = 100
+)
+
+func (o *NodeQueryOptions) concurrency() int {
+	if o == nil || o.Concurrency <= 0 {
+		return defaultNodeQueryConcurrency
+	}
+	return o.Concurrency
+}
+
+func (o *NodeQueryOptions) itemsPerPage() int {
+	if o == nil || o.ItemsPerPage <= 0 {
+		return defaultNodeQueryPageSize
+	}
+	return o.ItemsPerPage
+}
+
+// NodePageFetcher fetches one page of a collection for a club node.
+// It returns the page items and whether another page follows.
+type NodePageFetcher[T any] func(nodeId string, pagination *xplorentities.XPlorPagination) ([]T, bool, *xplorentities.ErrorResponse)
+
+// ClubNodesUnder returns the club nodes of the subtree rooted at nodeId, including the node itself when it is a club.
+// Children only known by IRI are resolved on the way. Descendants that cannot be resolved are skipped:
+// the clubs found elsewhere are returned along with an error naming them.
+func (nr *NodeResolver) ClubNodesUnder(nodeId string) ([]NetworkNodeInfo, *xplorentities.ErrorResponse) {
+	return nr.ClubNodesUnderContext(context.Background(), nodeId)
+}
+
+// ClubNodesUnderContext is ClubNodesUnder resolving nodes with a caller context; the walk stops once ctx is done
+func (nr *NodeResolver) ClubNodesUnderContext(ctx context.Context, nodeId string) ([]NetworkNodeInfo, *xplorentities.ErrorResponse) {
+	var clubs []NetworkNodeInfo
+	var failed []string
+	var failure *xplorentities.ErrorResponse
+	visited := make(map[string]bool)
+	queue := []string{nodeId}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		if ctx.Err() != nil {
+			return nil, &xplorentities.ErrorResponse{Code: http.StatusRequestTimeout, Message: "Node resolution cancelled: " + ctx.Err().Error()}
+		}
+
+		info, err := nr.resolve(ctx, current)
+		if err != nil && current == nodeId {
+			return nil, err
+		}
+		if err != nil {
+			failed = append(failed, current)
+			failure = err
+			continue
+		}
+		if info.NodeType == "club" || (info.ClubID != "" && len(info.ChildIDs) == 0) {
+			clubs = append(clubs, info)
+		}
+		queue = append(queue, info.ChildIDs...)
+	}
+	sortNodeInfos(clubs)
+	if failure != nil {
+		return clubs, &xplorentities.ErrorResponse{
+			Code:    failure.Code,
+			Message: "Failed to resolve nodes " + strings.Join(failed, ", ") + ": " + failure.Message,
+		}
+	}
+	return clubs, nil
+}
+
+// QueryClubNodes runs fetch on every club node under nodeId concurrently, following pagination,
+// and merges the results. Items sharing the same key (usually their IRI) are kept once;
+// a nil key function keeps every item. Subtrees that cannot be resolved are logged and left out.
+// ctx bounds the node resolution and stops the pagination; pass it to the calls made by fetch too.
+func QueryClubNodes[T any](ctx context.Context, xe *XplorProvider, nodeId string, options *NodeQueryOptions, fetch NodePageFetcher[T], key func(T) string) ([]T, *xplorentities.ErrorResponse) {
+	if err := checkNodeId(nodeId); err != nil {
+		return nil, err
+	}
+	clubs, err := xe.nodes.ClubNodesUnderContext(ctx, nodeId)
+	if err != nil && len(clubs) > 0 {
+		executor := xe.getExecutor("")
+		logger := executor.config.Logger
+		xe.putExecutor(executor)
+		if logger != nil {
+			logger.Warn("xplor club nodes skipped", slog.String("node_id", nodeId), slog.Int("status", err.Code), slog.String("error", err.Message))
+		}
+	} else if err != nil {
+		return nil, &xplorentities.ErrorResponse{
+			Code:    err.Code,
+			Message: "Failed to resolve club nodes: " + err.Message,
+		}
+	}
+	if len(clubs) == 0 {
+		return nil, &xplorentities.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "No club nodes found under node " + nodeId,
+		}
+	}
+
+	results := make([][]T, len(clubs))
+	errs := make([]*xplorentities.ErrorResponse, len(clubs))
+	semaphore := make(chan struct{}, options.concurrency())
+	var wg sync.WaitGroup
+	for i, club := range clubs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			for page := 1; ; page++ {
+				if ctx.Err() != nil {
+					errs[i] = &xplorentities.ErrorResponse{Code: http.StatusRequestTimeout, Message: "Node " + club.NodeID + ": query cancelled: " + ctx.Err().Error()}
+					return
+				}
+				items, hasNext, fetchErr := fetch(club.NodeID, &xplorentities.XPlorPagination{Page: page, ItemsPerPage: options.itemsPerPage()})
+				if fetchErr != nil {
+					errs[i] = &xplorentities.ErrorResponse{
+						Code:    fetchErr.Code,
+						Message: "Node " + club.NodeID + ": " + fetchErr.Message,
+					}
+					return
+				}
+				results[i] = append(results[i], items...)
+				if !hasNext || len(items) == 0 {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var merged []T
+	seen := make(map[string]bool)
+	for _, items := range results {
+		for _, item := range items {
+			if key != nil {
+				k := key(item)
+				if k != "" && seen[k] {
+					continue
+				}
+				seen[k] = true
+			}
+			merged = append(merged, item)
+		}
+	}
+	return merged, nil
+}
+
+// ContactsUnderNode returns the contacts of every club under nodeId, de-duplicated by IRI
+func (xe *XplorProvider) ContactsUnderNode(nodeId string, params *xplorentities.XPlorContactsParams, options *NodeQueryOptions) ([]xplorentities.XPlorContact, *xplorentities.ErrorResponse) {
+	return xe.ContactsUnderNodeContext(context.Background(), nodeId, params, options)
+}
+
+// ContactsUnderNodeContext is ContactsUnderNode with a caller context
+func (xe *XplorProvider) ContactsUnderNodeContext(ctx context.Context, nodeId string, params *xplorentities.XPlorContactsParams, options *NodeQueryOptions) ([]xplorentities.XPlorContact, *xplorentities.ErrorResponse) {
+	return QueryClubNodes(ctx, xe, nodeId, options,
+		func(clubNodeId string, pagination *xplorentities.XPlorPagination) ([]xplorentities.XPlorContact, bool, *xplorentities.ErrorResponse) {
+			result, err := xe.ContactsContext(ctx, clubNodeId, params, pagination)
+			if err != nil {
+				return nil, false, err
+			}
+			return result.Members, result.HasNextPage(), nil
+		},
+		func(contact xplorentities.XPlorContact) string { return util.Deref(contact.ID) },
+	)
+}
+
+// SubscriptionsUnderNode returns the subscriptions of every club under nodeId, de-duplicated by IRI
+func (xe *XplorProvider) SubscriptionsUnderNode(nodeId string, params *xplorentities.XPlorSubscriptionsParams, options *NodeQueryOptions) ([]xplorentities.XPlorSubscription, *xplorentities.ErrorResponse) {
+	return xe.SubscriptionsUnderNodeContext(context.Background(), nodeId, params, options)
+}
+
+// SubscriptionsUnderNodeContext is SubscriptionsUnderNode with a caller context
+func (xe *XplorProvider) SubscriptionsUnderNodeContext(ctx context.Context, nodeId string, params *xplorentities.XPlorSubscriptionsParams, options *NodeQueryOptions) ([]xplorentities.XPlorSubscription, *xplorentities.ErrorResponse) {
+	return QueryClubNodes(ctx, xe, nodeId, options,
+		func(clubNodeId string, pagination *xplorentities.XPlorPagination) ([]xplorentities.XPlorSubscription, bool, *xplorentities.ErrorResponse) {
+			result, err := xe.SubscriptionsContext(ctx, clubNodeId, params, pagination)
+			if err != nil {
+				return nil, false, err
+			}
+			return result.Members, result.HasNextPage(), nil
+		},
+		func(subscription xplorentities.XPlorSubscription) string { return util.Deref(subscription.Id) },
+	)
+}
+
+// ClassesUnderNode returns the classes of every club under nodeId, de-duplicated by IRI
+func (xe *XplorProvider) ClassesUnderNode(nodeId string, params *xplorentities.XPlorClassesParams, options *NodeQueryOptions) ([]xplorentities.XPlorClass, *xplorentities.ErrorResponse) {
+	return xe.ClassesUnderNodeContext(context.Background(), nodeId, params, options)
+}
+
+// ClassesUnderNodeContext is ClassesUnderNode with a caller context
+func (xe *XplorProvider) ClassesUnderNodeContext(ctx context.Context, nodeId string, params *xplorentities.XPlorClassesParams, options *NodeQueryOptions) ([]xplorentities.XPlorClass, *xplorentities.ErrorResponse) {
+	return QueryClubNodes(ctx, xe, nodeId, options,
+		func(clubNodeId string, pagination *xplorentities.XPlorPagination) ([]xplorentities.XPlorClass, bool, *xplorentities.ErrorResponse) {
+			result, err := xe.ClassesContext(ctx, clubNodeId, params, pagination)
+			if err != nil {
+				return nil, false, err
+			}
+			return result.Members, result.HasNextPage(), nil
+		},
+		func(class xplorentities.XPlorClass) string { return util.Deref(class.ID) },
+	)
+}
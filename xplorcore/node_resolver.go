@@ -0,0 +1,471 @@
+package xplorcore
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// NetworkNodeInfo is the resolved view of a network node kept by the NodeResolver
+type NetworkNodeInfo struct {
+	NodeID     string    `json:"nodeId"`
+	Name       string    `json:"name,omitempty"`
+	NodeType   string    `json:"type,omitempty"`
+	ClubID     string    `json:"clubId,omitempty"`
+	ParentID   string    `json:"parentId,omitempty"`
+	ChildIDs   []string  `json:"childIds,omitempty"`
+	ResolvedAt time.Time `json:"resolvedAt"`
+}
+
+// NetworkNodeTree is a node of the resolved network hierarchy
+type NetworkNodeTree struct {
+	NetworkNodeInfo
+	Children []*NetworkNodeTree `json:"children,omitempty"`
+}
+
+// nodeSource loads network nodes from the API
+type nodeSource interface {
+	NetworkNodes(ctx context.Context, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorNetworkNodes, *xplorentities.ErrorResponse)
+	NetworkNode(ctx context.Context, nodeId string) (*xplorentities.XPlorNetworkNode, *xplorentities.ErrorResponse)
+}
+
+// resolverSource reads network nodes for the resolver, always from the API:
+// the response cache would hand stale nodes back to a refresh
+type resolverSource struct {
+	provider *XplorProvider
+}
+
+func (s resolverSource) NetworkNodes(ctx context.Context, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorNetworkNodes, *xplorentities.ErrorResponse) {
+	return s.provider.networkNodes(ctx, pagination, true)
+}
+
+func (s resolverSource) NetworkNode(ctx context.Context, nodeId string) (*xplorentities.XPlorNetworkNode, *xplorentities.ErrorResponse) {
+	return s.provider.networkNode(ctx, nodeId, true)
+}
+
+// NodeResolver keeps a concurrency-safe mapping between network nodes and clubs.
+// Entries older than the TTL are re-resolved on demand; a zero TTL never expires them.
+type NodeResolver struct {
+	mutex       sync.RWMutex
+	source      nodeSource
+	ttl         time.Duration
+	nodes       map[string]*NetworkNodeInfo
+	clubNodes   map[string]string
+	refreshedAt time.Time
+}
+
+// nodeSnapshot is the on-disk format of a NodeResolver
+type nodeSnapshot struct {
+	RefreshedAt time.Time         `json:"refreshedAt"`
+	Nodes       []NetworkNodeInfo `json:"nodes"`
+}
+
+const networkNodesPageSize = 100
+
+func newNodeResolver(source nodeSource, ttl time.Duration) *NodeResolver {
+	return &NodeResolver{
+		source:    source,
+		ttl:       ttl,
+		nodes:     make(map[string]*NetworkNodeInfo),
+		clubNodes: make(map[string]string),
+	}
+}
+
+// SetTTL changes how long resolved entries are considered fresh
+func (nr *NodeResolver) SetTTL(ttl time.Duration) {
+	nr.mutex.Lock()
+	defer nr.mutex.Unlock()
+	nr.ttl = ttl
+}
+
+// Refresh reloads the whole network tree from the API, replacing the current entries
+func (nr *NodeResolver) Refresh() *xplorentities.ErrorResponse {
+	var roots []xplorentities.XPlorNetworkNode
+	for page := 1; ; page++ {
+		result, err := nr.source.NetworkNodes(context.Background(), &xplorentities.XPlorPagination{Page: page, ItemsPerPage: networkNodesPageSize})
+		if err != nil {
+			return err
+		}
+		roots = append(roots, result.Members...)
+		if len(result.Members) == 0 {
+			break
+		}
+		if !result.HasNextPage() {
+			break
+		}
+	}
+
+	now := time.Now()
+	nodes := make(map[string]*NetworkNodeInfo)
+	for _, root := range roots {
+		collectNode(nodes, root, "", now)
+	}
+	clubNodes := make(map[string]string)
+	for _, info := range nodes {
+		if info.ClubID != "" {
+			clubNodes[info.ClubID] = info.NodeID
+		}
+	}
+
+	nr.mutex.Lock()
+	defer nr.mutex.Unlock()
+	nr.nodes = nodes
+	nr.clubNodes = clubNodes
+	nr.refreshedAt = now
+	return nil
+}
+
+// Warm loads the network tree unless entries are already present and fresh
+func (nr *NodeResolver) Warm() *xplorentities.ErrorResponse {
+	nr.mutex.RLock()
+	fresh := len(nr.nodes) > 0 && nr.isFresh(nr.refreshedAt)
+	nr.mutex.RUnlock()
+	if fresh {
+		return nil
+	}
+	return nr.Refresh()
+}
+
+// collectNode registers a node and walks its children, keeping the richest data seen for each node
+func collectNode(nodes map[string]*NetworkNodeInfo, node xplorentities.XPlorNetworkNode, parentID string, now time.Time) string {
+	nodeID := networkNodeKey(node)
+	if nodeID == "" {
+		return ""
+	}
+	info, ok := nodes[nodeID]
+	if !ok {
+		info = &NetworkNodeInfo{NodeID: nodeID}
+		nodes[nodeID] = info
+	}
+	info.ResolvedAt = now
+	if node.Name != "" {
+		info.Name = node.Name
+	}
+	if node.NodeType != "" {
+		info.NodeType = node.NodeType
+	}
+	if clubID, err := node.ClubIDValue(); err == nil {
+		info.ClubID = clubID
+	}
+	if parentID != "" {
+		info.ParentID = parentID
+	}
+	for _, child := range node.ChildNodes() {
+		if childID := collectNode(nodes, child, nodeID, now); childID != "" && !slices.Contains(info.ChildIDs, childID) {
+			info.ChildIDs = append(info.ChildIDs, childID)
+		}
+	}
+	return nodeID
+}
+
+func networkNodeKey(node xplorentities.XPlorNetworkNode) string {
+	if id, err := node.NetworkNodeID(); err == nil && id != "" && id != "." && id != "/" {
+		return id
+	}
+	if node.ID > 0 {
+		return strconv.Itoa(node.ID)
+	}
+	return ""
+}
+
+// Resolve returns the node, fetching it from the API when unknown or stale
+func (nr *NodeResolver) Resolve(nodeId string) (NetworkNodeInfo, *xplorentities.ErrorResponse) {
+	return nr.resolve(context.Background(), nodeId)
+}
+
+// resolve is Resolve fetching unknown nodes with ctx
+func (nr *NodeResolver) resolve(ctx context.Context, nodeId string) (NetworkNodeInfo, *xplorentities.ErrorResponse) {
+	nodeId = strings.TrimSpace(nodeId)
+	nr.mutex.RLock()
+	info, ok := nr.nodes[nodeId]
+	// Children listed as bare IRIs have no type yet and still need to be fetched
+	if ok && info.NodeType != "" && nr.isFresh(info.ResolvedAt) {
+		result := *info
+		nr.mutex.RUnlock()
+		return result, nil
+	}
+	nr.mutex.RUnlock()
+
+	node, err := nr.source.NetworkNode(ctx, nodeId)
+	if err != nil {
+		return NetworkNodeInfo{}, err
+	}
+	return nr.store(nodeId, *node), nil
+}
+
+// store records a single node fetched from the API
+func (nr *NodeResolver) store(nodeId string, node xplorentities.XPlorNetworkNode) NetworkNodeInfo {
+	nr.mutex.Lock()
+	defer nr.mutex.Unlock()
+
+	now := time.Now()
+	fetched := make(map[string]*NetworkNodeInfo)
+	collectNode(fetched, node, "", now)
+	for id, info := range fetched {
+		if existing, ok := nr.nodes[id]; ok {
+			if info.ParentID == "" {
+				info.ParentID = existing.ParentID
+			}
+			if info.Name == "" {
+				info.Name = existing.Name
+			}
+			if info.NodeType == "" {
+				info.NodeType = existing.NodeType
+			}
+			if info.ClubID == "" {
+				info.ClubID = existing.ClubID
+			}
+			if len(info.ChildIDs) == 0 {
+				info.ChildIDs = existing.ChildIDs
+			}
+			if existing.ClubID != "" && existing.ClubID != info.ClubID {
+				delete(nr.clubNodes, existing.ClubID)
+			}
+		}
+		nr.nodes[id] = info
+		if info.ClubID != "" {
+			nr.clubNodes[info.ClubID] = id
+		}
+	}
+
+	key := networkNodeKey(node)
+	if key == "" {
+		key = nodeId
+	}
+	if info, ok := nr.nodes[key]; ok {
+		return *info
+	}
+	return NetworkNodeInfo{NodeID: nodeId, ResolvedAt: now}
+}
+
+func (nr *NodeResolver) isFresh(resolvedAt time.Time) bool {
+	return nr.ttl <= 0 || time.Since(resolvedAt) < nr.ttl
+}
+
+// ClubForNode returns the club ID attached to a node, if known
+func (nr *NodeResolver) ClubForNode(nodeId string) (string, bool) {
+	nr.mutex.RLock()
+	defer nr.mutex.RUnlock()
+	info, ok := nr.nodes[nodeId]
+	if !ok || info.ClubID == "" {
+		return "", false
+	}
+	return info.ClubID, true
+}
+
+// NodeForClub returns the network node ID of a club, if known
+func (nr *NodeResolver) NodeForClub(clubId string) (string, bool) {
+	nr.mutex.RLock()
+	defer nr.mutex.RUnlock()
+	nodeId, ok := nr.clubNodes[clubId]
+	return nodeId, ok
+}
+
+// Node returns a copy of a known node
+func (nr *NodeResolver) Node(nodeId string) (NetworkNodeInfo, bool) {
+	nr.mutex.RLock()
+	defer nr.mutex.RUnlock()
+	info, ok := nr.nodes[nodeId]
+	if !ok {
+		return NetworkNodeInfo{}, false
+	}
+	return *info, true
+}
+
+// Nodes returns a copy of every known node sorted by node ID
+func (nr *NodeResolver) Nodes() []NetworkNodeInfo {
+	nr.mutex.RLock()
+	defer nr.mutex.RUnlock()
+	nodes := make([]NetworkNodeInfo, 0, len(nr.nodes))
+	for _, info := range nr.nodes {
+		nodes = append(nodes, *info)
+	}
+	sortNodeInfos(nodes)
+	return nodes
+}
+
+// Tree returns the known hierarchy starting from nodes without a parent
+func (nr *NodeResolver) Tree() []*NetworkNodeTree {
+	nr.mutex.RLock()
+	defer nr.mutex.RUnlock()
+
+	var roots []*NetworkNodeTree
+	for _, info := range nr.nodes {
+		if _, hasParent := nr.nodes[info.ParentID]; info.ParentID == "" || !hasParent {
+			roots = append(roots, nr.buildTree(info, map[string]bool{}))
+		}
+	}
+	slices.SortFunc(roots, func(a, b *NetworkNodeTree) int { return compareNodeIDs(a.NodeID, b.NodeID) })
+	return roots
+}
+
+func (nr *NodeResolver) buildTree(info *NetworkNodeInfo, visited map[string]bool) *NetworkNodeTree {
+	visited[info.NodeID] = true
+	tree := &NetworkNodeTree{NetworkNodeInfo: *info}
+	for _, childID := range info.ChildIDs {
+		if child, ok := nr.nodes[childID]; ok && !visited[childID] {
+			tree.Children = append(tree.Children, nr.buildTree(child, visited))
+		}
+	}
+	return tree
+}
+
+// RefreshedAt returns when the whole tree was last loaded
+func (nr *NodeResolver) RefreshedAt() time.Time {
+	nr.mutex.RLock()
+	defer nr.mutex.RUnlock()
+	return nr.refreshedAt
+}
+
+// Forget drops a single node so it is fetched again on next use
+func (nr *NodeResolver) Forget(nodeId string) {
+	nr.mutex.Lock()
+	defer nr.mutex.Unlock()
+	if info, ok := nr.nodes[nodeId]; ok {
+		if info.ClubID != "" {
+			delete(nr.clubNodes, info.ClubID)
+		}
+		delete(nr.nodes, nodeId)
+	}
+}
+
+// Save writes the resolver state to a JSON file
+func (nr *NodeResolver) Save(path string) error {
+	nr.mutex.RLock()
+	snapshot := nodeSnapshot{RefreshedAt: nr.refreshedAt, Nodes: make([]NetworkNodeInfo, 0, len(nr.nodes))}
+	for _, info := range nr.nodes {
+		snapshot.Nodes = append(snapshot.Nodes, *info)
+	}
+	nr.mutex.RUnlock()
+	sortNodeInfos(snapshot.Nodes)
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so readers never see a partial snapshot
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load replaces the resolver state with a snapshot written by Save.
+// Entries keep their original resolution time, so the TTL still applies.
+func (nr *NodeResolver) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var snapshot nodeSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+
+	nodes := make(map[string]*NetworkNodeInfo, len(snapshot.Nodes))
+	clubNodes := make(map[string]string)
+	for i := range snapshot.Nodes {
+		info := snapshot.Nodes[i]
+		nodes[info.NodeID] = &info
+		if info.ClubID != "" {
+			clubNodes[info.ClubID] = info.NodeID
+		}
+	}
+
+	nr.mutex.Lock()
+	defer nr.mutex.Unlock()
+	nr.nodes = nodes
+	nr.clubNodes = clubNodes
+	nr.refreshedAt = snapshot.RefreshedAt
+	return nil
+}
+
+func sortNodeInfos(nodes []NetworkNodeInfo) {
+	slices.SortFunc(nodes, func(a, b NetworkNodeInfo) int { return compareNodeIDs(a.NodeID, b.NodeID) })
+}
+
+// compareNodeIDs orders numeric IDs numerically and falls back to string order
+func compareNodeIDs(a, b string) int {
+	ai, aErr := strconv.Atoi(a)
+	bi, bErr := strconv.Atoi(b)
+	if aErr == nil && bErr == nil {
+		return ai - bi
+	}
+	return strings.Compare(a, b)
+}
+
+// Nodes returns the node-to-club resolver used by the provider
+func (xe *XplorProvider) Nodes() *NodeResolver {
+	return xe.nodes
+}
+
+// warmNodes loads the network tree at Init, logging failures; nodes are then resolved one by one on demand
+func (xe *XplorProvider) warmNodes() {
+	executor := xe.getExecutor("")
+	logger := executor.config.Logger
+	xe.putExecutor(executor)
+	if err := xe.nodes.Warm(); err != nil && logger != nil {
+		logger.Warn("xplor network nodes warm-up failed", slog.Int("status", err.Code), slog.String("error", err.Message))
+	}
+}
+
+// RefreshNodes reloads the whole network tree used to resolve clubs, and drops the cached network node responses
+func (xe *XplorProvider) RefreshNodes() *xplorentities.ErrorResponse {
+	if err := xe.nodes.Refresh(); err != nil {
+		return &xplorentities.ErrorResponse{
+			Code:    err.Code,
+			Message: "Failed to refresh nodes: " + err.Message,
+		}
+	}
+	// Other pages and single nodes may still be cached from before the refresh
+	xe.InvalidateResource(xplorentities.ResourceNetworkNodes)
+	return nil
+}
+
+// resolveClubId sets the club header of the executor from the node resolver.
+// Group and franchise nodes have no club and are sent without it.
+func (xe *XplorProvider) resolveClubId(ctx context.Context, executor *xplorExecutor, nodeId string) *xplorentities.ErrorResponse {
+	executor.clubId = nil
+	ctx, span := executor.config.startSpan(ctx, SpanResolveClub, Attribute{AttrNodeID, nodeId})
+	defer span.End()
+	info, err := xe.nodes.resolve(ctx, nodeId)
+	if err != nil {
+		span.SetError(err.Message)
+		return err
+	}
+	if info.ClubID != "" {
+		span.SetAttributes(Attribute{AttrClubID, info.ClubID})
+	}
+	if info.ClubID == "" {
+		if info.NodeType == "club" {
+			return &xplorentities.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Failed to get club ID for node: club ID field is nil",
+			}
+		}
+		return nil
+	}
+	clubId := info.ClubID
+	executor.clubId = &clubId
+	return nil
+}
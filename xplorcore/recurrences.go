@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) recurrences(accesToken string, params *xplorentities.XPlorRecurrencesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorRecurrences, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorRecurrences], 1)
 
@@ -23,7 +23,7 @@ func (xe xplorExecutor) recurrences(accesToken string, params *xplorentities.XPl
 
 		var request = xe.config.generateRequest(http.MethodGet, "/recurrences", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorRecurrences](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorRecurrences](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -42,7 +42,7 @@ func (xe xplorExecutor) recurrences(accesToken string, params *xplorentities.XPl
 
 }
 func (xe xplorExecutor) recurrence(accesToken string, familyId string) (*xplorentities.XPlorRecurrence, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorRecurrence], 1)
 
@@ -51,7 +51,7 @@ func (xe xplorExecutor) recurrence(accesToken string, familyId string) (*xploren
 
 		var request = xe.config.generateRequest(http.MethodGet, "/recurrences/"+familyId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorRecurrence](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorRecurrence](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
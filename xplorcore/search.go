@@ -0,0 +1,94 @@
+package xplorcore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// checkSearchResource rejects resources that are not searchable collections
+func checkSearchResource(resource xplorentities.Resource) *xplorentities.ErrorResponse {
+	for _, r := range xplorentities.Resources {
+		if r == resource {
+			return nil
+		}
+	}
+	return &xplorentities.ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: "Unknown resource: " + string(resource),
+	}
+}
+
+// SearchTemplate returns the hydra:search template of a collection, listing the filters it accepts.
+// A single-item page is requested; the template is cached with the resource when the response cache is enabled.
+func (xe *XplorProvider) SearchTemplate(nodeId string, resource xplorentities.Resource) (*xplorentities.HydraSearch, *xplorentities.ErrorResponse) {
+	return xe.SearchTemplateContext(context.Background(), nodeId, resource)
+}
+
+// SearchTemplateContext is SearchTemplate with a caller context
+func (xe *XplorProvider) SearchTemplateContext(ctx context.Context, nodeId string, resource xplorentities.Resource) (*xplorentities.HydraSearch, *xplorentities.ErrorResponse) {
+	if err := checkSearchResource(resource); err != nil {
+		return nil, err
+	}
+	executor, err := xe.executorFor(ctx, resource, nodeId)
+	if err != nil {
+		return nil, err
+	}
+	defer xe.putExecutor(executor)
+
+	collection, err := getCollection[json.RawMessage](ctx, *executor, xe.token.Token.AccessToken, resource.Path(), url.Values{"itemsPerPage": {"1"}})
+	if err != nil {
+		return nil, &xplorentities.ErrorResponse{
+			Code:    err.Code,
+			Message: "Failed to get search template: " + err.Message,
+		}
+	}
+	if collection.Search == nil {
+		return nil, &xplorentities.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Resource " + string(resource) + " does not expose a search template",
+		}
+	}
+
+	return collection.Search, nil
+}
+
+// Search runs a query built from a search template against its collection.
+// Members are returned undecoded so that any resource can be listed without a dedicated type.
+func (xe *XplorProvider) Search(nodeId string, resource xplorentities.Resource, query *xplorentities.SearchQuery, pagination *xplorentities.XPlorPagination) (*xplorentities.SearchCollection, *xplorentities.ErrorResponse) {
+	return xe.SearchContext(context.Background(), nodeId, resource, query, pagination)
+}
+
+// SearchContext is Search with a caller context
+func (xe *XplorProvider) SearchContext(ctx context.Context, nodeId string, resource xplorentities.Resource, query *xplorentities.SearchQuery, pagination *xplorentities.XPlorPagination) (*xplorentities.SearchCollection, *xplorentities.ErrorResponse) {
+	if err := checkSearchResource(resource); err != nil {
+		return nil, err
+	}
+	queryParams := xplorentities.BuildPaginationQueryParams(pagination)
+	if query != nil {
+		if err := invalidParams(query.Validate()); err != nil {
+			return nil, err
+		}
+		for name, values := range query.Values() {
+			queryParams[name] = values
+		}
+	}
+	executor, err := xe.executorFor(ctx, resource, nodeId)
+	if err != nil {
+		return nil, err
+	}
+	defer xe.putExecutor(executor)
+
+	collection, err := getCollection[json.RawMessage](ctx, *executor, xe.token.Token.AccessToken, resource.Path(), queryParams)
+	if err != nil {
+		return nil, &xplorentities.ErrorResponse{
+			Code:    err.Code,
+			Message: "Failed to search " + string(resource) + ": " + err.Message,
+		}
+	}
+
+	return collection, nil
+}
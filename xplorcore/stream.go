@@ -0,0 +1,144 @@
+package xplorcore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// StreamResult describes a page decoded by Stream
+type StreamResult struct {
+	Decoded    int  // Members passed to yield
+	TotalItems *int // hydra:totalItems, nil when absent
+	View       *xplorentities.HydraView
+	// Errors lists the members that could not be decoded; they are skipped
+	Errors []util.MemberError
+	// Warnings lists the values skipped by lenient decoding
+	Warnings []util.DecodeWarning
+}
+
+// PageInfo returns the page metadata of the streamed page
+func (r *StreamResult) PageInfo() xplorentities.PageInfo {
+	return xplorentities.NewPageInfo(r.TotalItems, r.Decoded+len(r.Errors), r.View)
+}
+
+// Stream fetches a page of the collection of T and decodes its members one at a time,
+// so memory use does not grow with the page size. yield receives each member; returning false stops reading.
+// Members that fail to decode are reported in the result instead of failing the page; on other errors
+// the result still reports what was decoded before the failure.
+// Streamed pages bypass the response cache. The default timeout only bounds waiting for the response, ctx bounds the whole page.
+func Stream[T xplorentities.Entity](ctx context.Context, xe *XplorProvider, nodeId string, queryParams url.Values, pagination *xplorentities.XPlorPagination, yield func(item T) bool) (*StreamResult, *xplorentities.ErrorResponse) {
+	var zero T
+	resource := zero.Resource()
+	executor, err := xe.executorFor(ctx, resource, nodeId)
+	if err != nil {
+		return nil, err
+	}
+	defer xe.putExecutor(executor)
+
+	params := xplorentities.BuildPaginationQueryParams(pagination)
+	for name, values := range queryParams {
+		params[name] = values
+	}
+	result, err := streamCollection(ctx, *executor, xe.token.Token.AccessToken, resource, params, yield)
+	if err != nil {
+		return result, &xplorentities.ErrorResponse{
+			Code:    err.Code,
+			Message: "Failed to stream " + string(resource) + ": " + err.Message,
+		}
+	}
+	return result, nil
+}
+
+func streamCollection[T any](ctx context.Context, xe xplorExecutor, accessToken string, resource xplorentities.Resource, queryParams url.Values, yield func(item T) bool) (*StreamResult, *xplorentities.ErrorResponse) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	responseTimeout := time.AfterFunc(xe.defaultTimeout, cancel)
+
+	request := xe.config.generateRequest(http.MethodGet, resource.Path(), xe.generateHeaders(accessToken), queryParams, url.Values{})
+	request = request.WithContext(ctx)
+	response, _, err := xe.exchange(request, resource, true)
+	if !responseTimeout.Stop() {
+		if response != nil && response.Stream != nil {
+			response.Stream.Close()
+		}
+		err = &xplorentities.ErrorResponse{
+			Code:    http.StatusRequestTimeout,
+			Message: "Request timeout: no response after " + xe.defaultTimeout.String(),
+		}
+	}
+	if err != nil {
+		xe.interceptors.observe(ctx, Result{Request: request, Resource: resource, Error: err})
+		return nil, err
+	}
+
+	body := response.Stream
+	if body == nil {
+		body = io.NopCloser(bytes.NewReader(response.Body))
+	}
+	defer body.Close()
+
+	result := &StreamResult{}
+	fields, decodeErr := util.StreamMembers(body, "hydra:member", func(index int, member json.RawMessage) bool {
+		var item T
+		warnings, err := util.Unmarshal(member, &item, xe.config.DecodeMode)
+		if err != nil {
+			result.Errors = append(result.Errors, util.MemberError{Index: index, ID: memberID(member), Err: err})
+			return true
+		}
+		for _, warning := range warnings {
+			warning.Path = "hydra:member[" + strconv.Itoa(index) + "]." + warning.Path
+			result.Warnings = append(result.Warnings, warning)
+		}
+		result.Decoded++
+		return yield(item)
+	})
+	if raw, ok := fields["hydra:totalItems"]; ok {
+		var total int
+		if json.Unmarshal(raw, &total) == nil {
+			result.TotalItems = &total
+		}
+	}
+	if raw, ok := fields["hydra:view"]; ok {
+		var view xplorentities.HydraView
+		if json.Unmarshal(raw, &view) == nil {
+			result.View = &view
+		}
+	}
+
+	if decodeErr != nil {
+		err = &xplorentities.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to decode response: " + decodeErr.Error(),
+		}
+		if ctx.Err() != nil && !errors.Is(decodeErr, util.ErrBodyTooLarge) {
+			err = &xplorentities.ErrorResponse{
+				Code:    http.StatusRequestTimeout,
+				Message: "Stream cancelled: " + ctx.Err().Error(),
+			}
+		}
+	}
+	xe.logDecodeWarnings(request, resource, result.Warnings)
+	xe.interceptors.observe(ctx, Result{Request: request, Resource: resource, Value: result, Error: err, Warnings: result.Warnings})
+	return result, err
+}
+
+// memberID reads the @id of a member that failed to decode, if any
+func memberID(member json.RawMessage) string {
+	var identified struct {
+		ID string `json:"@id"`
+	}
+	if json.Unmarshal(member, &identified) != nil {
+		return ""
+	}
+	return identified.ID
+}
@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) studios(accesToken string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorStudios, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorStudios], 1)
 
@@ -20,7 +20,7 @@ func (xe xplorExecutor) studios(accesToken string, pagination *xplorentities.XPl
 
 		var request = xe.config.generateRequest(http.MethodGet, "/studios", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorStudios](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorStudios](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -39,7 +39,7 @@ func (xe xplorExecutor) studios(accesToken string, pagination *xplorentities.XPl
 
 }
 func (xe xplorExecutor) studio(accesToken string, familyId string) (*xplorentities.XPlorStudio, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorStudio], 1)
 
@@ -48,7 +48,7 @@ func (xe xplorExecutor) studio(accesToken string, familyId string) (*xplorentiti
 
 		var request = xe.config.generateRequest(http.MethodGet, "/studios/"+familyId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorStudio](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorStudio](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
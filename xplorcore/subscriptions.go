@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) subscriptions(accesToken string, params *xplorentities.XPlorSubscriptionsParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorSubscriptions, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorSubscriptions], 1)
 
@@ -23,7 +23,7 @@ func (xe xplorExecutor) subscriptions(accesToken string, params *xplorentities.X
 
 		var request = xe.config.generateRequest(http.MethodGet, "/subscriptions", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorSubscriptions](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorSubscriptions](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -42,7 +42,7 @@ func (xe xplorExecutor) subscriptions(accesToken string, params *xplorentities.X
 
 }
 func (xe xplorExecutor) subscription(accesToken string, subscriptionId string) (*xplorentities.XPlorSubscription, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorSubscription], 1)
 
@@ -51,7 +51,7 @@ func (xe xplorExecutor) subscription(accesToken string, subscriptionId string) (
 
 		var request = xe.config.generateRequest(http.MethodGet, "/subscriptions/"+subscriptionId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorSubscription](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorSubscription](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -0,0 +1,117 @@
+package xplorcore
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// Attribute is a key/value pair attached to spans; values are strings, ints or bools
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Attribute keys set by the SDK
+const (
+	AttrResource   = "xplor.resource"
+	AttrNodeID     = "xplor.node_id"
+	AttrClubID     = "xplor.club_id"
+	AttrPage       = "xplor.page"
+	AttrCacheHit   = "xplor.cache_hit"
+	AttrMethod     = "http.request.method"
+	AttrStatusCode = "http.response.status_code"
+	AttrPath       = "url.path"
+)
+
+// Span names emitted by the SDK
+const (
+	SpanRequest      = "xplor.request"
+	SpanAuthenticate = "xplor.authenticate"
+	SpanResolveClub  = "xplor.resolve_club"
+)
+
+// Span is an operation being traced
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// SetError marks the span as failed
+	SetError(message string)
+	End()
+}
+
+// Telemetry receives traces and metrics of the SDK. Set it on the config to enable instrumentation;
+// the xplorotel module provides an OpenTelemetry implementation.
+type Telemetry interface {
+	// StartSpan starts a span as a child of any span found in ctx
+	StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+	// RecordRequest records one HTTP exchange; status is 0 when no response was received
+	RecordRequest(ctx context.Context, resource xplorentities.Resource, method string, status int, latency time.Duration)
+	// RecordTokenRefresh counts access token requests
+	RecordTokenRefresh(ctx context.Context, success bool)
+	// RecordCacheLookup counts response cache hits and misses
+	RecordCacheLookup(ctx context.Context, resource xplorentities.Resource, hit bool)
+}
+
+// startSpan starts a span when telemetry is configured; the returned span is never nil
+func (xc *xplorConfig) startSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	if xc.Telemetry == nil {
+		return ctx, noopSpan{}
+	}
+	return xc.Telemetry.StartSpan(ctx, name, attrs...)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) SetError(string)            {}
+func (noopSpan) End()                       {}
+
+// requestSpanAttrs describes a request for its span
+func (xe xplorExecutor) requestSpanAttrs(request *http.Request, resource xplorentities.Resource) []Attribute {
+	attrs := []Attribute{
+		{AttrResource, string(resource)},
+		{AttrMethod, request.Method},
+		{AttrPath, request.URL.Path},
+	}
+	if xe.nodeId != nil {
+		attrs = append(attrs, Attribute{AttrNodeID, *xe.nodeId})
+	}
+	if xe.clubId != nil {
+		attrs = append(attrs, Attribute{AttrClubID, *xe.clubId})
+	}
+	if page, err := strconv.Atoi(request.URL.Query().Get("page")); err == nil {
+		attrs = append(attrs, Attribute{AttrPage, page})
+	}
+	return attrs
+}
+
+// traceExchange ends the span of an HTTP exchange and records its metrics
+func (xe xplorExecutor) traceExchange(request *http.Request, span Span, resource xplorentities.Resource, response *util.RawResponse, err *xplorentities.ErrorResponse, latency time.Duration) {
+	status := 0
+	if response != nil {
+		status = response.StatusCode
+		span.SetAttributes(Attribute{AttrStatusCode, status})
+	}
+	switch {
+	case response == nil && err != nil:
+		span.SetError(err.Message)
+	case err != nil:
+		// The error message embeds the response body, which may hold personal data
+		span.SetError("HTTP " + strconv.Itoa(status) + " " + http.StatusText(status))
+	}
+	span.End()
+	if xe.config.Telemetry != nil {
+		xe.config.Telemetry.RecordRequest(request.Context(), resource, request.Method, status, latency)
+	}
+}
+
+// recordCacheLookup reports a response cache lookup
+func (xe xplorExecutor) recordCacheLookup(request *http.Request, resource xplorentities.Resource, hit bool) {
+	if xe.config.Telemetry != nil {
+		xe.config.Telemetry.RecordCacheLookup(request.Context(), resource, hit)
+	}
+}
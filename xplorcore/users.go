@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) users(accesToken string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorUsers, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorUsers], 1)
 
@@ -20,7 +20,7 @@ func (xe xplorExecutor) users(accesToken string, pagination *xplorentities.XPlor
 
 		var request = xe.config.generateRequest(http.MethodGet, "/users", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorUsers](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorUsers](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -39,7 +39,7 @@ func (xe xplorExecutor) users(accesToken string, pagination *xplorentities.XPlor
 
 }
 func (xe xplorExecutor) user(accesToken string, userId string) (*xplorentities.XPlorUser, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorUser], 1)
 
@@ -48,7 +48,7 @@ func (xe xplorExecutor) user(accesToken string, userId string) (*xplorentities.X
 
 		var request = xe.config.generateRequest(http.MethodGet, "/users/"+userId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorUser](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorUser](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -10,7 +10,7 @@ import (
 )
 
 func (xe xplorExecutor) zones(accesToken string, params *xplorentities.XPlorZonesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorZones, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorZones], 1)
 
@@ -23,7 +23,7 @@ func (xe xplorExecutor) zones(accesToken string, params *xplorentities.XPlorZone
 
 		var request = xe.config.generateRequest(http.MethodGet, "/zones", xe.generateHeaders(accesToken), queryParams, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorZones](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorZones](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -42,7 +42,7 @@ func (xe xplorExecutor) zones(accesToken string, params *xplorentities.XPlorZone
 
 }
 func (xe xplorExecutor) zone(accesToken string, zoneId string) (*xplorentities.XPlorZone, *xplorentities.ErrorResponse) {
-	var ctxWithTimeout, cancel = context.WithTimeout(context.Background(), xe.defaultTimeout)
+	var ctxWithTimeout, cancel = context.WithTimeout(xe.baseContext(), xe.defaultTimeout)
 	defer cancel()
 	resultChan := make(chan util.RequestResult[*xplorentities.XPlorZone], 1)
 
@@ -51,7 +51,7 @@ func (xe xplorExecutor) zone(accesToken string, zoneId string) (*xplorentities.X
 
 		var request = xe.config.generateRequest(http.MethodGet, "/zones/"+zoneId, xe.generateHeaders(accesToken), nil, formData)
 		request = request.WithContext(ctxWithTimeout)
-		result := util.ExecuteRequest[*xplorentities.XPlorZone](ctxWithTimeout, xe.client, request, xe.config.Debug)
+		result := executeRequest[*xplorentities.XPlorZone](ctxWithTimeout, xe, request)
 		resultChan <- result
 
 	}()
@@ -10,11 +10,7 @@ import (
 
 // ----------- Hydra Collection de Activities -----------
 type XPlorActivities struct {
-	Context    string          `json:"@context"`
-	ID         string          `json:"@id"`
-	Type       string          `json:"@type"`
-	Activities []XPlorActivity `json:"hydra:member"`
-	Pagination HydraView       `json:"hydra:view"`
+	Collection[XPlorActivity]
 }
 
 // ----------- XPlorActivity -----------
@@ -123,6 +119,11 @@ type XPlorActivitiesParams struct {
 	Archived *bool
 }
 
+// Validate checks that ClubID is a bare ID, since ToValues builds the club IRI from it
+func (p XPlorActivitiesParams) Validate() error {
+	return validateBareID("clubId", p.ClubID)
+}
+
 // ToValues converts the params to url.Values for query parameters
 func (p XPlorActivitiesParams) ToValues(orgName string, values *url.Values) {
 	// Single value filters
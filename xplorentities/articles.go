@@ -11,11 +11,7 @@ import (
 
 // Estructuras principales
 type XPlorArticles struct {
-	Context     string         `json:"@context"`
-	ID          string         `json:"@id"`
-	Type        string         `json:"@type"`
-	HydraMember []XPlorArticle `json:"hydra:member"`
-	Pagination  *HydraView     `json:"hydra:view"`
+	Collection[XPlorArticle]
 }
 
 type XPlorArticle struct {
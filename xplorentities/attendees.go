@@ -9,11 +9,7 @@ import (
 )
 
 type XPlorAttendees struct {
-	Context    string          `json:"@context"`
-	ID         string          `json:"@id"`
-	Type       string          `json:"@type"`
-	Attendees  []XPlorAttendee `json:"hydra:member"`
-	Pagination *HydraView      `json:"hydra:view,omitempty"`
+	Collection[XPlorAttendee]
 }
 
 type XPlorAttendee struct {
@@ -56,6 +52,8 @@ type XPlorAttendee struct {
 	ClassEventStart *string `json:"classEventStartedAt"`
 	ClassLayout     *string `json:"classLayout"`
 	CancelDelayOver bool    `json:"cancelDelayOver"`
+
+	Embedded AttendeeEmbedded `json:"-"` // Filled by Expand
 }
 
 // Subestructura ClassEvent
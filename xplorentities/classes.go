@@ -1,6 +1,7 @@
 package xplorentities
 
 import (
+	"errors"
 	"net/url"
 	"time"
 
@@ -12,11 +13,7 @@ type LocalTime = util.LocalTime
 
 // XPlorClasses representa la colección de eventos de clase
 type XPlorClasses struct {
-	Context    string       `json:"@context"`
-	ID         string       `json:"@id"`
-	Type       string       `json:"@type"`
-	Classes    []XPlorClass `json:"hydra:member"`
-	Pagination HydraView    `json:"hydra:view"`
+	Collection[XPlorClass]
 }
 
 // XPlorClass representa un evento de clase individual
@@ -58,6 +55,8 @@ type XPlorClass struct {
 	AttendeeRemaining                  int             `json:"attendeeRemaining"`
 	QueueRemaining                     int             `json:"queueRemaining"`
 	DefaultOnlineLimit                 any             `json:"defaultOnlineLimit"`
+
+	Embedded ClassEmbedded `json:"-"` // Filled by Expand
 }
 
 // Métodos para obtener IDs
@@ -94,26 +93,41 @@ func (c XPlorClass) RecurrenceID() (string, error) {
 
 // Métodos de utilidad para fechas
 
-// GetStartedAt returns the start time as a naive timestamp.
+// GetStartedAt returns the start time as decoded (naive unless the API sent an offset).
 func (c XPlorClass) GetStartedAt() time.Time {
 	return c.StartedAt.Time
 }
 
-// GetEndedAt returns the end time as a naive timestamp.
+// GetEndedAt returns the end time as decoded (naive unless the API sent an offset).
 func (c XPlorClass) GetEndedAt() time.Time {
 	return c.EndedAt.Time
 }
 
-// GetCreatedAt returns the creation time as a naive timestamp.
+// GetCreatedAt returns the creation time as decoded (naive unless the API sent an offset).
 func (c XPlorClass) GetCreatedAt() time.Time {
 	return c.CreatedAt.Time
 }
 
-// GetUpdatedAt returns the last update time as a naive timestamp.
+// GetUpdatedAt returns the last update time as decoded (naive unless the API sent an offset).
 func (c XPlorClass) GetUpdatedAt() time.Time {
 	return c.UpdatedAt.Time
 }
 
+// StartInstant returns the exact start instant of the class in the club time zone, handling DST transitions
+func (c XPlorClass) StartInstant(loc *time.Location) time.Time {
+	return c.StartedAt.InLocation(loc)
+}
+
+// EndInstant returns the exact end instant of the class in the club time zone, handling DST transitions
+func (c XPlorClass) EndInstant(loc *time.Location) time.Time {
+	return c.EndedAt.InLocation(loc)
+}
+
+// Duration returns the real duration of the class in loc, which differs from the wall-clock span across a DST change
+func (c XPlorClass) Duration(loc *time.Location) time.Duration {
+	return c.EndInstant(loc).Sub(c.StartInstant(loc))
+}
+
 // Métodos para verificar disponibilidad
 
 // HasAvailableSpots checks if the class has available attendee spots
@@ -177,9 +191,59 @@ type XPlorClassesParams struct {
 	StartedAtAfter          *time.Time
 	StartedAtStrictlyAfter  *time.Time
 	OrderStartedAt          *string // asc or desc
-	Available               *string
+	Available               *string // true or false
 	Time                    *string
-	Archived                *string
+	Archived                *string // true or false
+	StartedAt               *DateFilter
+	UpdatedAt               *DateFilter
+	Order                   []Order
+}
+
+// ClassOrderFields lists the fields classes can be ordered by
+var ClassOrderFields = []string{"startedAt"}
+
+// AttendeeStates lists the values accepted by the attendees.state filter of classes; append to it if the API adds one
+var AttendeeStates = []string{"booked", "queued", "validated", "canceled"}
+
+func (p XPlorClassesParams) legacyStartedAt() DateFilter {
+	return DateFilter{
+		Before:         p.StartedAtBefore,
+		StrictlyBefore: p.StartedAtStrictlyBefore,
+		After:          p.StartedAtAfter,
+		StrictlyAfter:  p.StartedAtStrictlyAfter,
+	}
+}
+
+func (p XPlorClassesParams) orders() []Order {
+	orders := p.Order
+	if p.OrderStartedAt != nil {
+		orders = append([]Order{{Field: "startedAt", Direction: OrderDirection(*p.OrderStartedAt)}}, orders...)
+	}
+	return orders
+}
+
+// Validate checks the date ranges, order, attendee state and boolean filters
+func (p XPlorClassesParams) Validate() error {
+	var errs []error
+	var attendeeState string
+	if p.AttendeeState != nil {
+		attendeeState = *p.AttendeeState
+	}
+	if startedAt, err := mergeDateFilter("startedAt", p.StartedAt, p.legacyStartedAt()); err != nil {
+		errs = append(errs, err)
+	} else if err := startedAt.Validate("startedAt"); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs,
+		p.UpdatedAt.Validate("updatedAt"),
+		validateOrders(p.orders(), ClassOrderFields),
+		validateBoolString("available", p.Available),
+		validateBoolString("archived", p.Archived),
+		validateEnums("attendees.state", attendeeState, p.AttendeeStates, AttendeeStates),
+		validateBareID("club", p.Club),
+		validateBareID("recurrence", p.Recurrence),
+	)
+	return errors.Join(errs...)
 }
 
 // ToValues converts the params to url.Values for query parameters
@@ -209,9 +273,6 @@ func (p XPlorClassesParams) ToValues(orgName string, values *url.Values) {
 	if p.ActivityGroup != nil {
 		values.Set("activity.activityGroups", *p.ActivityGroup)
 	}
-	if p.OrderStartedAt != nil {
-		values.Set("order[startedAt]", *p.OrderStartedAt)
-	}
 	if p.Available != nil {
 		values.Set("available", *p.Available)
 	}
@@ -249,16 +310,9 @@ func (p XPlorClassesParams) ToValues(orgName string, values *url.Values) {
 	}
 
 	// Date filters
-	if p.StartedAtBefore != nil {
-		values.Set("startedAt[before]", p.StartedAtBefore.Format("2006-01-02 15:04:05"))
-	}
-	if p.StartedAtStrictlyBefore != nil {
-		values.Set("startedAt[strictly_before]", p.StartedAtStrictlyBefore.Format("2006-01-02 15:04:05"))
-	}
-	if p.StartedAtAfter != nil {
-		values.Set("startedAt[after]", p.StartedAtAfter.Format("2006-01-02 15:04:05"))
-	}
-	if p.StartedAtStrictlyAfter != nil {
-		values.Set("startedAt[strictly_after]", p.StartedAtStrictlyAfter.Format("2006-01-02 15:04:05"))
-	}
+	applyDateFilters("startedAt", APIDateTimeLayout, p.StartedAt, p.legacyStartedAt(), values)
+	p.UpdatedAt.apply("updatedAt", APIDateTimeLayout, values)
+
+	// Order filters
+	applyOrders(p.orders(), values)
 }
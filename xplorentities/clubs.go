@@ -3,14 +3,13 @@ package xplorentities
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
 )
 
 type XPloreClubs struct {
-	Context    any         `json:"@context"` // Cambiado a any para permitir string o object
-	ID         string      `json:"@id"`
-	Type       string      `json:"@type"`
-	Clubs      []XPlorClub `json:"hydra:member"`
-	Pagination HydraView   `json:"hydra:view"`
+	Collection[XPlorClub]
 }
 
 type XPlorClub struct {
@@ -157,3 +156,16 @@ func (c XPlorClub) FullAddress() string {
 func (c XPlorClub) IsActive() bool {
 	return c.DeletedAt == nil
 }
+
+// Location returns the club time zone, derived from the address country and falling back to the locale region
+func (c XPlorClub) Location() (*time.Location, error) {
+	if c.AddressCountryIso != "" {
+		if loc, err := util.LocationForCountry(c.AddressCountryIso); err == nil {
+			return loc, nil
+		}
+	}
+	if c.Locale != nil && *c.Locale != "" {
+		return util.LocationForLocale(*c.Locale)
+	}
+	return nil, fmt.Errorf("club %s has no country or locale to derive a time zone", c.Code)
+}
@@ -7,11 +7,7 @@ import (
 )
 
 type XPloreCoaches struct {
-	Context    *string       `json:"@context"`
-	ID         *string       `json:"@id"`
-	Type       *string       `json:"@type"`
-	Coaches    []XPloreCoach `json:"hydra:member"`
-	Pagination *HydraView    `json:"hydra:view"`
+	Collection[XPloreCoach]
 }
 
 type XPloreCoach struct {
@@ -0,0 +1,111 @@
+package xplorentities
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Collection is a page of a Hydra collection with members of type T
+type Collection[T any] struct {
+	Context    string       `json:"@context"`
+	ID         string       `json:"@id"`
+	Type       string       `json:"@type"`
+	Members    []T          `json:"hydra:member"`
+	TotalItems *int         `json:"hydra:totalItems"` // nil when the page has none
+	View       *HydraView   `json:"hydra:view,omitempty"`
+	Search     *HydraSearch `json:"hydra:search,omitempty"`
+	// Warnings lists the values skipped by lenient decoding (see DecodeMode in the provider config)
+	Warnings []DecodeWarning `json:"-"`
+}
+
+// SetWarnings records the decode warnings of the page; the provider calls it after decoding
+func (c *Collection[T]) SetWarnings(warnings []DecodeWarning) {
+	c.Warnings = warnings
+}
+
+// Len returns the number of members in the page
+func (c Collection[T]) Len() int {
+	return len(c.Members)
+}
+
+// HasNextPage reports whether the view announces a following page
+func (c Collection[T]) HasNextPage() bool {
+	if c.View == nil {
+		return false
+	}
+	_, err := c.View.NextPageNumber()
+	return err == nil
+}
+
+// Entity is implemented by the entity types addressable by IRI
+type Entity interface {
+	Resource() Resource
+}
+
+// IRI is a reference to an entity of type T, such as "/enjoy/clubs/1249"
+type IRI[T Entity] string
+
+// NewIRI builds the IRI of the entity with the given ID under the enterprise prefix
+func NewIRI[T Entity](enterpriseName, id string) IRI[T] {
+	var zero T
+	return IRI[T]("/" + enterpriseName + zero.Resource().Path() + "/" + id)
+}
+
+// ShortID returns the trailing ID of an IRI such as /enjoy/clubs/1249, or iri itself when it is not one.
+// Collection IRIs (/enjoy/clubs) have no ID and are kept.
+func ShortID(iri string) string {
+	if !strings.HasPrefix(iri, "/") || strings.ContainsAny(iri, " \t\n") {
+		return iri
+	}
+	segments := strings.Split(strings.Trim(strings.Split(iri, "?")[0], "/"), "/")
+	if len(segments) < 3 || slices.Contains(segments, "") {
+		return iri
+	}
+	return segments[len(segments)-1]
+}
+
+// IRIFrom converts an optional IRI field into a typed reference; nil yields the zero IRI
+func IRIFrom[T Entity](field *string) IRI[T] {
+	if field == nil {
+		return ""
+	}
+	return IRI[T](*field)
+}
+
+// Resource returns the collection the referenced entity belongs to
+func (i IRI[T]) Resource() Resource {
+	var zero T
+	return zero.Resource()
+}
+
+// IsZero reports whether the reference is empty
+func (i IRI[T]) IsZero() bool {
+	return strings.TrimSpace(string(i)) == ""
+}
+
+// String returns the IRI as sent by the API
+func (i IRI[T]) String() string {
+	return string(i)
+}
+
+// ID extracts the entity ID from the IRI.
+// It fails when the IRI is empty or addresses another resource.
+func (i IRI[T]) ID() (string, error) {
+	if i.IsZero() {
+		return "", errors.New(string(i.Resource()) + " IRI is empty")
+	}
+	if resource, ok := ResourceFromPath(string(i)); !ok || resource != i.Resource() {
+		return "", errors.New("IRI " + string(i) + " does not reference " + string(i.Resource()))
+	}
+	return ExtractIDFromString(string(i), string(i.Resource())+" IRI is empty")
+}
+
+// Path returns the entity path relative to the enterprise prefix (e.g. "/clubs/1249")
+func (i IRI[T]) Path() (string, error) {
+	id, err := i.ID()
+	if err != nil {
+		return "", err
+	}
+	return i.Resource().Path() + "/" + id, nil
+}
@@ -9,12 +9,7 @@ import (
 
 // XPlorContactImages represents the Hydra collection for contact image files.
 type XPlorContactImages struct {
-	Context       string              `json:"@context"`
-	ID            string              `json:"@id"`
-	Type          string              `json:"@type"`
-	ContactImages []XPlorContactImage `json:"hydra:member"`
-	Pagination    *HydraView          `json:"hydra:view,omitempty"`
-	Search        *HydraSearch        `json:"hydra:search,omitempty"`
+	Collection[XPlorContactImage]
 }
 
 // XPlorContactImage represents a contact image file resource.
@@ -54,6 +49,11 @@ type XPlorContactImagesParams struct {
 	ContactIDs      []string
 }
 
+// Validate implements QueryParams; contact image filters take any value
+func (p XPlorContactImagesParams) Validate() error {
+	return nil
+}
+
 // ToValues converts the params to url.Values for query parameters.
 func (p XPlorContactImagesParams) ToValues(values *url.Values) {
 	contactImageID := strings.TrimSpace(p.ContactImageID)
@@ -23,15 +23,13 @@ type XPlorContactTag struct {
 	CreatedAt          *util.LocalTime `json:"createdAt"`
 	DeletedAt          *util.LocalTime `json:"deletedAt,omitempty"`
 	DeletedBy          any             `json:"deletedBy,omitempty"`
+
+	Embedded ContactTagEmbedded `json:"-"` // Filled by Expand
 }
 
 // XPlorContactTags representa una colección de tags de contacto
 type XPlorContactTags struct {
-	Context     string            `json:"@context"`
-	ID          string            `json:"@id"`
-	Type        string            `json:"@type"`
-	ContactTags []XPlorContactTag `json:"hydra:member"`
-	Pagination  *HydraView        `json:"hydra:view,omitempty"`
+	Collection[XPlorContactTag]
 }
 
 // Métodos para XPlorContactTag
@@ -108,12 +106,12 @@ func (c *XPlorContactTags) ContextID() (string, error) {
 
 // AllContactTagIDs returns all contact tag IDs from the collection
 func (c *XPlorContactTags) AllContactTagIDs() ([]string, error) {
-	if len(c.ContactTags) == 0 {
+	if len(c.Members) == 0 {
 		return nil, errors.New("no contact tags available")
 	}
 
-	ids := make([]string, len(c.ContactTags))
-	for i, tag := range c.ContactTags {
+	ids := make([]string, len(c.Members))
+	for i, tag := range c.Members {
 		id, err := tag.ContactTagID()
 		if err != nil {
 			return nil, err
@@ -125,12 +123,12 @@ func (c *XPlorContactTags) AllContactTagIDs() ([]string, error) {
 
 // Método para obtener todos los contact IDs únicos
 func (c *XPlorContactTags) AllContactIDs() ([]string, error) {
-	if len(c.ContactTags) == 0 {
+	if len(c.Members) == 0 {
 		return nil, errors.New("no contact tags available")
 	}
 
 	contactIDs := make(map[string]bool)
-	for _, tag := range c.ContactTags {
+	for _, tag := range c.Members {
 		contactID, err := tag.ContactID()
 		if err == nil {
 			contactIDs[contactID] = true
@@ -151,12 +149,12 @@ func (c *XPlorContactTags) AllContactIDs() ([]string, error) {
 
 // Método para obtener todos los subscription IDs únicos
 func (c *XPlorContactTags) AllSubscriptionIDs() ([]string, error) {
-	if len(c.ContactTags) == 0 {
+	if len(c.Members) == 0 {
 		return nil, errors.New("no contact tags available")
 	}
 
 	subscriptionIDs := make(map[string]bool)
-	for _, tag := range c.ContactTags {
+	for _, tag := range c.Members {
 		subscriptionID, err := tag.SubscriptionID()
 		if err == nil {
 			subscriptionIDs[subscriptionID] = true
@@ -180,7 +178,7 @@ func (c *XPlorContactTags) AllSubscriptionIDs() ([]string, error) {
 // ActiveTags returns all active tags that have not been deleted
 func (c *XPlorContactTags) ActiveTags() []XPlorContactTag {
 	activeTags := make([]XPlorContactTag, 0)
-	for _, tag := range c.ContactTags {
+	for _, tag := range c.Members {
 		if tag.IsActive() && !tag.IsDeleted() {
 			activeTags = append(activeTags, tag)
 		}
@@ -191,7 +189,7 @@ func (c *XPlorContactTags) ActiveTags() []XPlorContactTag {
 // ExpiredTags returns all expired tags that have not been deleted
 func (c *XPlorContactTags) ExpiredTags() []XPlorContactTag {
 	expiredTags := make([]XPlorContactTag, 0)
-	for _, tag := range c.ContactTags {
+	for _, tag := range c.Members {
 		if tag.IsExpired() && !tag.IsDeleted() {
 			expiredTags = append(expiredTags, tag)
 		}
@@ -202,7 +200,7 @@ func (c *XPlorContactTags) ExpiredTags() []XPlorContactTag {
 // PermanentTags returns all permanent tags (no expiration date) that have not been deleted
 func (c *XPlorContactTags) PermanentTags() []XPlorContactTag {
 	permanentTags := make([]XPlorContactTag, 0)
-	for _, tag := range c.ContactTags {
+	for _, tag := range c.Members {
 		if tag.IsPermanent() && !tag.IsDeleted() {
 			permanentTags = append(permanentTags, tag)
 		}
@@ -213,7 +211,7 @@ func (c *XPlorContactTags) PermanentTags() []XPlorContactTag {
 // TagsByName returns all tags with the specified name that have not been deleted
 func (c *XPlorContactTags) TagsByName(name string) []XPlorContactTag {
 	tags := make([]XPlorContactTag, 0)
-	for _, tag := range c.ContactTags {
+	for _, tag := range c.Members {
 		if tag.Name == name && !tag.IsDeleted() {
 			tags = append(tags, tag)
 		}
@@ -224,7 +222,7 @@ func (c *XPlorContactTags) TagsByName(name string) []XPlorContactTag {
 // TagsByContact returns all tags for the specified contact ID that have not been deleted
 func (c *XPlorContactTags) TagsByContact(contactID string) []XPlorContactTag {
 	tags := make([]XPlorContactTag, 0)
-	for _, tag := range c.ContactTags {
+	for _, tag := range c.Members {
 		id, err := tag.ContactID()
 		if err == nil && id == contactID && !tag.IsDeleted() {
 			tags = append(tags, tag)
@@ -236,7 +234,7 @@ func (c *XPlorContactTags) TagsByContact(contactID string) []XPlorContactTag {
 // TagsBySubscription returns all tags for the specified subscription ID that have not been deleted
 func (c *XPlorContactTags) TagsBySubscription(subscriptionID string) []XPlorContactTag {
 	tags := make([]XPlorContactTag, 0)
-	for _, tag := range c.ContactTags {
+	for _, tag := range c.Members {
 		id, err := tag.SubscriptionID()
 		if err == nil && id == subscriptionID && !tag.IsDeleted() {
 			tags = append(tags, tag)
@@ -248,7 +246,7 @@ func (c *XPlorContactTags) TagsBySubscription(subscriptionID string) []XPlorCont
 // UniqueTagNamesForContact returns unique tag names for the specified contact that have not been deleted
 func (c *XPlorContactTags) UniqueTagNamesForContact(contactID string) []string {
 	tagNames := make(map[string]bool)
-	for _, tag := range c.ContactTags {
+	for _, tag := range c.Members {
 		id, err := tag.ContactID()
 		if err == nil && id == contactID && !tag.IsDeleted() {
 			tagNames[tag.Name] = true
@@ -292,6 +290,11 @@ type XPlorContactTagsParams struct {
 	Active         *bool
 }
 
+// Validate implements QueryParams; contact tag filters take any value
+func (p XPlorContactTagsParams) Validate() error {
+	return nil
+}
+
 // ToValues converts the params to url.Values for query parameters
 func (p XPlorContactTagsParams) ToValues(values *url.Values) {
 	// Contact ID filters
@@ -11,12 +11,7 @@ import (
 
 // Colección
 type XPlorContacts struct {
-	Context    string         `json:"@context"`
-	ID         string         `json:"@id"`
-	Type       string         `json:"@type"`
-	Contacts   []XPlorContact `json:"hydra:member"`
-	Pagination *HydraView     `json:"hydra:view,omitempty"`
-	Search     *HydraSearch   `json:"hydra:search,omitempty"`
+	Collection[XPlorContact]
 }
 
 // Entidad Contact
@@ -157,6 +152,18 @@ type XPlorContactsParams struct {
 	Number     string
 	FamilyName string
 	GivenName  string
+	UpdatedAt  *DateFilter
+}
+
+// ContactStates lists the values accepted by the state filter of contacts; append to it if the API adds one
+var ContactStates = []string{"prospect", "customer", "former_customer"}
+
+// Validate checks the state filters and the updatedAt range
+func (p XPlorContactsParams) Validate() error {
+	return errors.Join(
+		validateEnums("state", p.State, p.States, ContactStates),
+		p.UpdatedAt.Validate("updatedAt"),
+	)
 }
 
 // ToValues converts the params to url.Values for query parameters
@@ -217,4 +224,7 @@ func (p XPlorContactsParams) ToValues(values *url.Values) {
 	if p.GivenName != "" {
 		values.Set("givenName", p.GivenName)
 	}
+
+	// Date filters
+	p.UpdatedAt.apply("updatedAt", APIDateTimeLayout, values)
 }
@@ -36,11 +36,7 @@ type CounterLineServiceProperty struct {
 
 // XPlorCounterLines representa una colección de líneas de contador
 type XPlorCounterLines struct {
-	Context      string             `json:"@context"`
-	ID           string             `json:"@id"`
-	Type         string             `json:"@type"`
-	CounterLines []XPlorCounterLine `json:"hydra:member"`
-	Pagination   *HydraView         `json:"hydra:view,omitempty"`
+	Collection[XPlorCounterLine]
 }
 
 // Métodos para XPlorCounterLine
@@ -126,12 +122,12 @@ func (c *XPlorCounterLines) ContextID() (string, error) {
 
 // AllContactIDs returns all contact IDs from the counter lines collection
 func (c *XPlorCounterLines) AllContactIDs() ([]string, error) {
-	if len(c.CounterLines) == 0 {
+	if len(c.Members) == 0 {
 		return nil, errors.New("no counter lines available")
 	}
 
 	contactIDs := make([]string, 0)
-	for _, cl := range c.CounterLines {
+	for _, cl := range c.Members {
 		contactID, err := cl.ContactIDValue()
 		if err == nil { // Solo agregar si no hay error
 			contactIDs = append(contactIDs, contactID)
@@ -148,7 +144,7 @@ func (c *XPlorCounterLines) AllContactIDs() ([]string, error) {
 // Método para obtener todas las líneas activas
 func (c *XPlorCounterLines) ActiveCounterLines() []XPlorCounterLine {
 	activeLines := make([]XPlorCounterLine, 0)
-	for _, cl := range c.CounterLines {
+	for _, cl := range c.Members {
 		if cl.IsActive() && !cl.IsDeleted() {
 			activeLines = append(activeLines, cl)
 		}
@@ -159,7 +155,7 @@ func (c *XPlorCounterLines) ActiveCounterLines() []XPlorCounterLine {
 // Método para obtener todas las líneas expiradas
 func (c *XPlorCounterLines) ExpiredCounterLines() []XPlorCounterLine {
 	expiredLines := make([]XPlorCounterLine, 0)
-	for _, cl := range c.CounterLines {
+	for _, cl := range c.Members {
 		if cl.IsExpired() && !cl.IsDeleted() {
 			expiredLines = append(expiredLines, cl)
 		}
@@ -170,7 +166,7 @@ func (c *XPlorCounterLines) ExpiredCounterLines() []XPlorCounterLine {
 // Método para obtener todas las líneas no iniciadas
 func (c *XPlorCounterLines) NotStartedCounterLines() []XPlorCounterLine {
 	notStartedLines := make([]XPlorCounterLine, 0)
-	for _, cl := range c.CounterLines {
+	for _, cl := range c.Members {
 		if cl.IsNotStarted() && !cl.IsDeleted() {
 			notStartedLines = append(notStartedLines, cl)
 		}
@@ -181,7 +177,7 @@ func (c *XPlorCounterLines) NotStartedCounterLines() []XPlorCounterLine {
 // Método para obtener todas las líneas eliminadas
 func (c *XPlorCounterLines) DeletedCounterLines() []XPlorCounterLine {
 	deletedLines := make([]XPlorCounterLine, 0)
-	for _, cl := range c.CounterLines {
+	for _, cl := range c.Members {
 		if cl.IsDeleted() {
 			deletedLines = append(deletedLines, cl)
 		}
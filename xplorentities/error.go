@@ -4,3 +4,6 @@ import "github.com/angelbarreiros/XPlorGo/util"
 
 // ErrorResponse is an alias for util.ErrorResponse
 type ErrorResponse = util.ErrorResponse
+
+// DecodeWarning is an alias for util.DecodeWarning
+type DecodeWarning = util.DecodeWarning
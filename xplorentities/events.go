@@ -4,17 +4,14 @@ import (
 	"errors"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/angelbarreiros/XPlorGo/util"
 )
 
 // Colección Hydra
 type XPlorEvents struct {
-	Context    string       `json:"@context"`
-	ID         string       `json:"@id"`
-	Type       string       `json:"@type"`
-	Events     []XPlorEvent `json:"hydra:member"`
-	Pagination HydraView    `json:"hydra:view"`
+	Collection[XPlorEvent]
 }
 
 // Un evento de clase individual
@@ -63,9 +60,12 @@ type HydraMapping struct {
 
 // Vista Hydra (para paginación)
 type HydraView struct {
-	HydraFirst string `json:"hydra:first"`
-	HydraLast  string `json:"hydra:last"`
-	HydraNext  string `json:"hydra:next"`
+	ID            string `json:"@id"`
+	Type          string `json:"@type"`
+	HydraFirst    string `json:"hydra:first"`
+	HydraLast     string `json:"hydra:last"`
+	HydraPrevious string `json:"hydra:previous"`
+	HydraNext     string `json:"hydra:next"`
 }
 
 // FirstPageNumber extracts the page number from the first URL
@@ -73,43 +73,23 @@ func (hv HydraView) FirstPageNumber() (int, error) {
 	if hv.HydraFirst == "" {
 		return 0, errors.New("hydra:first URL is empty")
 	}
-	pageStr, err := extractPageNumber(hv.HydraFirst)
-	if err != nil {
-		return 0, err
-	}
-	pageInt, err := strconv.Atoi(pageStr)
-	if err != nil {
-		return 0, errors.New("page parameter is not a valid integer")
-	}
-	return pageInt, nil
+	return pageNumber(hv.HydraFirst)
 }
 
-// LastPageNumber extracts the page number from the last URL
+// LastPageNumber extracts the page number from the last URL.
+// Without hydra:last it returns the current page when there is no next page, and fails
+// when the last page cannot be known (partial pagination).
 func (hv HydraView) LastPageNumber() (int, error) {
-	if hv.HydraLast == "" {
-		// If there's no last page, try to use next page number
-		if hv.HydraNext != "" {
-			pageStr, err := extractPageNumber(hv.HydraNext)
-			if err != nil {
-				return 1, nil // Return 1 if we can't extract page number
-			}
-			pageInt, err := strconv.Atoi(pageStr)
-			if err != nil {
-				return 1, nil // Return 1 if page parameter is not a valid integer
-			}
-			return pageInt, nil
-		}
-		return 1, nil // Return 1 if there's no last page and no next page
-	}
-	pageStr, err := extractPageNumber(hv.HydraLast)
-	if err != nil {
-		return 1, nil // Return 1 if we can't extract page number
+	if hv.HydraLast != "" {
+		return pageNumber(hv.HydraLast)
 	}
-	pageInt, err := strconv.Atoi(pageStr)
-	if err != nil {
-		return 1, nil // Return 1 if page parameter is not a valid integer
+	if hv.HydraNext != "" {
+		return 0, errors.New("hydra:last URL is empty and a next page exists")
 	}
-	return pageInt, nil
+	if hv.ID == "" {
+		return 1, nil // Single page collection
+	}
+	return hv.CurrentPageNumber()
 }
 
 // NextPageNumber extracts the page number from the next URL
@@ -117,12 +97,59 @@ func (hv HydraView) NextPageNumber() (int, error) {
 	if hv.HydraNext == "" {
 		return 0, errors.New("hydra:next URL is empty")
 	}
-	pageStr, err := extractPageNumber(hv.HydraNext)
+	return pageNumber(hv.HydraNext)
+}
+
+// PreviousPageNumber extracts the page number from the previous URL
+func (hv HydraView) PreviousPageNumber() (int, error) {
+	if hv.HydraPrevious == "" {
+		return 0, errors.New("hydra:previous URL is empty")
+	}
+	return pageNumber(hv.HydraPrevious)
+}
+
+// CurrentPageNumber extracts the page number from the view @id; a view without page parameter is the first page
+func (hv HydraView) CurrentPageNumber() (int, error) {
+	if hv.ID == "" {
+		return 0, errors.New("hydra:view @id is empty")
+	}
+	page, err := pageNumber(hv.ID)
+	if errors.Is(err, errPageNotFound) {
+		return 1, nil
+	}
+	return page, err
+}
+
+// ItemsPerPage extracts the itemsPerPage parameter from the view @id
+func (hv HydraView) ItemsPerPage() (int, error) {
+	if hv.ID == "" {
+		return 0, errors.New("hydra:view @id is empty")
+	}
+	parsedURL, err := url.Parse(hv.ID)
+	if err != nil {
+		return 0, errors.New("invalid URL format")
+	}
+	param := parsedURL.Query().Get("itemsPerPage")
+	if param == "" {
+		return 0, errors.New("itemsPerPage parameter not found in URL")
+	}
+	itemsPerPage, err := strconv.Atoi(param)
+	if err != nil || itemsPerPage <= 0 {
+		return 0, errors.New("itemsPerPage parameter is not a valid integer")
+	}
+	return itemsPerPage, nil
+}
+
+var errPageNotFound = errors.New("page parameter not found in URL")
+
+// pageNumber extracts the page parameter of a URL as an integer
+func pageNumber(urlStr string) (int, error) {
+	pageStr, err := extractPageNumber(urlStr)
 	if err != nil {
 		return 0, err
 	}
 	pageInt, err := strconv.Atoi(pageStr)
-	if err != nil {
+	if err != nil || pageInt <= 0 {
 		return 0, errors.New("page parameter is not a valid integer")
 	}
 	return pageInt, nil
@@ -137,7 +164,7 @@ func extractPageNumber(urlStr string) (string, error) {
 
 	pageParam := parsedURL.Query().Get("page")
 	if pageParam == "" {
-		return "", errors.New("page parameter not found in URL")
+		return "", errPageNotFound
 	}
 
 	return pageParam, nil
@@ -230,3 +257,19 @@ func (e XPlorEvent) RecurrenceID() (string, error) {
 func (e XPlorEvent) EventID() (string, error) {
 	return ExtractID(e.ID, "event ID field is nil")
 }
+
+// StartInstant returns the exact start instant of the event in loc, handling DST transitions
+func (e XPlorEvent) StartInstant(loc *time.Location) time.Time {
+	if e.StartedAt == nil {
+		return time.Time{}
+	}
+	return e.StartedAt.InLocation(loc)
+}
+
+// EndInstant returns the exact end instant of the event in loc, handling DST transitions
+func (e XPlorEvent) EndInstant(loc *time.Location) time.Time {
+	if e.EndedAt == nil {
+		return time.Time{}
+	}
+	return e.EndedAt.InLocation(loc)
+}
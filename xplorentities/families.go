@@ -10,11 +10,7 @@ import (
 )
 
 type XPlorFamilies struct {
-	Context    string        `json:"@context"`
-	ID         string        `json:"@id"`
-	Type       string        `json:"@type"`
-	Families   []XPlorFamily `json:"hydra:member"`
-	Pagination *HydraView    `json:"hydra:view,omitempty"`
+	Collection[XPlorFamily]
 }
 
 type HydraIriTemplateMapping struct {
@@ -83,6 +79,11 @@ type XPlorFamiliesParams struct {
 	ContactIds []string
 }
 
+// Validate implements QueryParams; family filters take any value
+func (p XPlorFamiliesParams) Validate() error {
+	return nil
+}
+
 // ToValues converts the params to url.Values for query parameters
 func (p XPlorFamiliesParams) ToValues(values *url.Values) {
 	contactId := strings.TrimSpace(p.ContactId)
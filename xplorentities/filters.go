@@ -0,0 +1,299 @@
+package xplorentities
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// APIDateTimeLayout is the "Y-m-d H:i:s" format accepted by the API date filters
+const APIDateTimeLayout = "2006-01-02 15:04:05"
+
+// QueryParams is implemented by search parameters that can check their filters before a request is sent
+type QueryParams interface {
+	Validate() error
+}
+
+// FilterError reports a filter value rejected before sending the request
+type FilterError struct {
+	Filter string // Query parameter name, e.g. "order[startedAt]"
+	Value  string
+	Reason string
+}
+
+func (e *FilterError) Error() string {
+	if e.Value == "" {
+		return "invalid filter " + e.Filter + ": " + e.Reason
+	}
+	return fmt.Sprintf("invalid filter %s=%q: %s", e.Filter, e.Value, e.Reason)
+}
+
+// DateFilter is an API Platform date filter on one property.
+// Bounds left nil are not sent.
+type DateFilter struct {
+	Before         *time.Time // property[before], inclusive
+	StrictlyBefore *time.Time // property[strictly_before]
+	After          *time.Time // property[after], inclusive
+	StrictlyAfter  *time.Time // property[strictly_after]
+}
+
+// DateBetween matches values in the half-open range [from, to)
+func DateBetween(from, to time.Time) *DateFilter {
+	return &DateFilter{After: &from, StrictlyBefore: &to}
+}
+
+// DateFrom matches values at or after t
+func DateFrom(t time.Time) *DateFilter {
+	return &DateFilter{After: &t}
+}
+
+// DateUntil matches values at or before t
+func DateUntil(t time.Time) *DateFilter {
+	return &DateFilter{Before: &t}
+}
+
+// IsZero reports whether the filter has no bound
+func (f *DateFilter) IsZero() bool {
+	return f == nil || (f.Before == nil && f.StrictlyBefore == nil && f.After == nil && f.StrictlyAfter == nil)
+}
+
+// Validate checks that the lower bounds do not exclude the upper bounds
+func (f *DateFilter) Validate(property string) error {
+	if f.IsZero() {
+		return nil
+	}
+	var lower, upper *time.Time
+	lowerStrict, upperStrict := false, false
+	if f.After != nil {
+		lower = f.After
+	}
+	if f.StrictlyAfter != nil && (lower == nil || !f.StrictlyAfter.Before(*lower)) {
+		lower, lowerStrict = f.StrictlyAfter, true
+	}
+	if f.Before != nil {
+		upper = f.Before
+	}
+	if f.StrictlyBefore != nil && (upper == nil || !f.StrictlyBefore.After(*upper)) {
+		upper, upperStrict = f.StrictlyBefore, true
+	}
+	if lower == nil || upper == nil {
+		return nil
+	}
+	if lower.After(*upper) || (lower.Equal(*upper) && (lowerStrict || upperStrict)) {
+		return &FilterError{
+			Filter: property,
+			Reason: fmt.Sprintf("empty date range from %s to %s", lower.Format(APIDateTimeLayout), upper.Format(APIDateTimeLayout)),
+		}
+	}
+	return nil
+}
+
+// apply writes the bounds as property[before], property[strictly_before], property[after] and property[strictly_after]
+func (f *DateFilter) apply(property, layout string, values *url.Values) {
+	if f == nil {
+		return
+	}
+	if f.Before != nil {
+		values.Set(property+"[before]", f.Before.Format(layout))
+	}
+	if f.StrictlyBefore != nil {
+		values.Set(property+"[strictly_before]", f.StrictlyBefore.Format(layout))
+	}
+	if f.After != nil {
+		values.Set(property+"[after]", f.After.Format(layout))
+	}
+	if f.StrictlyAfter != nil {
+		values.Set(property+"[strictly_after]", f.StrictlyAfter.Format(layout))
+	}
+}
+
+// mergeDateFilter combines a typed filter with the legacy per-bound fields of a params type.
+// Setting the same bound through both is rejected.
+func mergeDateFilter(property string, typed *DateFilter, legacy DateFilter) (*DateFilter, error) {
+	if typed.IsZero() {
+		if legacy.IsZero() {
+			return nil, nil
+		}
+		return &legacy, nil
+	}
+	merged := *typed
+	bounds := []struct {
+		name   string
+		target **time.Time
+		value  *time.Time
+	}{
+		{"before", &merged.Before, legacy.Before},
+		{"strictly_before", &merged.StrictlyBefore, legacy.StrictlyBefore},
+		{"after", &merged.After, legacy.After},
+		{"strictly_after", &merged.StrictlyAfter, legacy.StrictlyAfter},
+	}
+	for _, bound := range bounds {
+		if bound.value == nil {
+			continue
+		}
+		if *bound.target != nil {
+			return nil, &FilterError{Filter: property + "[" + bound.name + "]", Reason: "set both by the date filter and the legacy field"}
+		}
+		*bound.target = bound.value
+	}
+	return &merged, nil
+}
+
+// applyDateFilters writes a legacy filter and then the typed one, so the typed bounds win on conflict.
+// Validate rejects such conflicts before a provider call reaches this point.
+func applyDateFilters(property, layout string, typed *DateFilter, legacy DateFilter, values *url.Values) {
+	legacy.apply(property, layout, values)
+	typed.apply(property, layout, values)
+}
+
+// parseFilterDate parses a legacy "Y-m-d H:i:s" or "Y-m-d" filter value
+func parseFilterDate(filter, value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{APIDateTimeLayout, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, &FilterError{Filter: filter, Value: value, Reason: "expected Y-m-d H:i:s"}
+}
+
+// OrderDirection is the direction of an order filter
+type OrderDirection string
+
+const (
+	OrderAsc  OrderDirection = "asc"
+	OrderDesc OrderDirection = "desc"
+)
+
+// Order sorts a collection on one field, sent as order[Field]=Direction
+type Order struct {
+	Field     string
+	Direction OrderDirection
+}
+
+// OrderBy returns an ascending order on field
+func OrderBy(field string) Order {
+	return Order{Field: field, Direction: OrderAsc}
+}
+
+// OrderByDesc returns a descending order on field
+func OrderByDesc(field string) Order {
+	return Order{Field: field, Direction: OrderDesc}
+}
+
+// validate checks the field against the orderable fields of the resource and the direction against asc/desc
+func (o Order) validate(allowed []string) error {
+	filter := "order[" + o.Field + "]"
+	if !slices.Contains(allowed, o.Field) {
+		return &FilterError{Filter: filter, Reason: "unknown order field, expected one of " + strings.Join(allowed, ", ")}
+	}
+	return validateEnum(filter, string(o.Direction), string(OrderAsc), string(OrderDesc))
+}
+
+// applyOrders writes order[field]=direction for each complete order; validateOrders allows one at most
+func applyOrders(orders []Order, values *url.Values) {
+	for _, o := range orders {
+		if o.Field == "" || o.Direction == "" {
+			continue
+		}
+		values.Set("order["+o.Field+"]", strings.ToLower(string(o.Direction)))
+	}
+}
+
+// validateOrders checks every order and rejects a field ordered twice.
+// It also rejects several orders: url.Values encodes keys alphabetically, so their priority would be lost.
+func validateOrders(orders []Order, allowed []string) error {
+	var errs []error
+	if len(orders) > 1 {
+		errs = append(errs, &FilterError{Filter: "order", Reason: "only one order is supported, the query cannot keep the priority of several"})
+	}
+	seen := make(map[string]bool)
+	for _, o := range orders {
+		if seen[o.Field] {
+			errs = append(errs, &FilterError{Filter: "order[" + o.Field + "]", Reason: "ordered more than once"})
+			continue
+		}
+		seen[o.Field] = true
+		if err := o.validate(allowed); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ExistsFilter filters on the presence of nullable properties, sent as exists[property]=true|false
+type ExistsFilter map[string]bool
+
+func (f ExistsFilter) apply(values *url.Values) {
+	properties := make([]string, 0, len(f))
+	for property := range f {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	for _, property := range properties {
+		if f[property] {
+			values.Set("exists["+property+"]", "true")
+		} else {
+			values.Set("exists["+property+"]", "false")
+		}
+	}
+}
+
+func (f ExistsFilter) validate(allowed []string) error {
+	var errs []error
+	for property := range f {
+		if !slices.Contains(allowed, property) {
+			errs = append(errs, &FilterError{Filter: "exists[" + property + "]", Reason: "unknown property, expected one of " + strings.Join(allowed, ", ")})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateEnum checks that value is one of allowed; comparison ignores case
+func validateEnum(filter, value string, allowed ...string) error {
+	for _, candidate := range allowed {
+		if strings.EqualFold(value, candidate) {
+			return nil
+		}
+	}
+	return &FilterError{Filter: filter, Value: value, Reason: "expected one of " + strings.Join(allowed, ", ")}
+}
+
+// validateEnums checks every non-blank value of a single and a multi-valued filter against allowed
+func validateEnums(filter, value string, values []string, allowed []string) error {
+	var errs []error
+	for i, v := range append([]string{value}, values...) {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		name := filter
+		if i > 0 {
+			name += "[]"
+		}
+		errs = append(errs, validateEnum(name, strings.TrimSpace(v), allowed...))
+	}
+	return errors.Join(errs...)
+}
+
+// validateBareID checks an ID the params turn into an IRI themselves, such as "1249" for /org/clubs/1249
+func validateBareID(filter string, id *string) error {
+	if id == nil || !strings.Contains(*id, "/") {
+		return nil
+	}
+	return &FilterError{Filter: filter, Value: *id, Reason: "expected a bare ID, not an IRI"}
+}
+
+// validateBoolString checks a legacy string filter that the API reads as a boolean
+func validateBoolString(filter string, value *string) error {
+	if value == nil {
+		return nil
+	}
+	return validateEnum(filter, *value, "true", "false", "1", "0")
+}
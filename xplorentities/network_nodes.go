@@ -1,26 +1,33 @@
 package xplorentities
 
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
 // Colección de NetworkNodes
 type XPlorNetworkNodes struct {
-	Context      string             `json:"@context"`
-	ID           string             `json:"@id"`
-	Type         string             `json:"@type"`
-	NetworkNodes []XPlorNetworkNode `json:"hydra:member"`
-	Pagination   HydraView          `json:"hydra:view"`
+	Collection[XPlorNetworkNode]
 }
 
 // Entidad NetworkNode
 type XPlorNetworkNode struct {
-	NotworkNodeID *string `json:"@id"`
-	Type          string  `json:"@type"`
-	ID            int     `json:"id"`
-	Name          string  `json:"name"`
-	Alias         *string `json:"alias"`
-	NodeType      string  `json:"type"`
-	ClubID        *string `json:"clubId"`
-	Children      any     `json:"children"`
+	NotworkNodeID *string             `json:"@id"`
+	Type          string              `json:"@type"`
+	ID            int                 `json:"id"`
+	Name          string              `json:"name"`
+	Alias         *string             `json:"alias"`
+	NodeType      string              `json:"type"`
+	ClubID        *string             `json:"clubId"`
+	Children      NetworkNodeChildren `json:"children"`
 }
 
+// NetworkNodeChildren holds the children of a network node.
+// The API returns either embedded nodes or bare IRIs; IRIs are decoded into nodes carrying only their @id.
+type NetworkNodeChildren []XPlorNetworkNode
+
 // ---------------- Métodos helpers ----------------
 
 // NetworkNodeID extracts the network node ID from the @id field
@@ -47,3 +54,156 @@ func (n XPlorNetworkNode) IsGroup() bool {
 func (n XPlorNetworkNode) IsFranchise() bool {
 	return n.NodeType == "franchise"
 }
+
+// UnmarshalJSON decodes children given as a list or an object map of embedded nodes or bare IRIs.
+// Null, empty and scalar values decode as no children, and children that fail to decode are skipped,
+// so one malformed child does not fail the whole node tree.
+func (nc *NetworkNodeChildren) UnmarshalJSON(b []byte) error {
+	var items []json.RawMessage
+	switch trimmed := bytes.TrimSpace(b); {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return err
+		}
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &object); err != nil {
+			return err
+		}
+		keys := slices.Sorted(maps.Keys(object))
+		for _, key := range keys {
+			items = append(items, object[key])
+		}
+	}
+	if len(items) == 0 {
+		*nc = nil
+		return nil
+	}
+	children := make(NetworkNodeChildren, 0, len(items))
+	for _, item := range items {
+		var iri string
+		if err := json.Unmarshal(item, &iri); err == nil {
+			if iri != "" {
+				children = append(children, XPlorNetworkNode{NotworkNodeID: &iri})
+			}
+			continue
+		}
+		var child XPlorNetworkNode
+		if err := json.Unmarshal(item, &child); err != nil || child.NotworkNodeID == nil {
+			continue
+		}
+		children = append(children, child)
+	}
+	*nc = children
+	return nil
+}
+
+// ChildNodes returns the direct children of the node
+func (n XPlorNetworkNode) ChildNodes() []XPlorNetworkNode {
+	return n.Children
+}
+
+// IsReference checks if the node only carries its IRI (children listed without being embedded)
+func (n XPlorNetworkNode) IsReference() bool {
+	return n.NodeType == "" && n.Name == "" && n.ID == 0
+}
+
+// Walk visits the node and its descendants depth-first.
+// Returning false from fn skips the children of the visited node.
+func (n XPlorNetworkNode) Walk(fn func(node XPlorNetworkNode, depth int) bool) {
+	n.walk(fn, 0)
+}
+
+func (n XPlorNetworkNode) walk(fn func(node XPlorNetworkNode, depth int) bool, depth int) {
+	if !fn(n, depth) {
+		return
+	}
+	for _, child := range n.Children {
+		child.walk(fn, depth+1)
+	}
+}
+
+// Descendants returns every node below this one, depth-first
+func (n XPlorNetworkNode) Descendants() []XPlorNetworkNode {
+	var descendants []XPlorNetworkNode
+	for _, child := range n.Children {
+		child.Walk(func(node XPlorNetworkNode, _ int) bool {
+			descendants = append(descendants, node)
+			return true
+		})
+	}
+	return descendants
+}
+
+// ClubNodes returns the club nodes in the subtree, including the node itself when it is a club
+func (n XPlorNetworkNode) ClubNodes() []XPlorNetworkNode {
+	var clubs []XPlorNetworkNode
+	n.Walk(func(node XPlorNetworkNode, _ int) bool {
+		if node.IsClub() {
+			clubs = append(clubs, node)
+		}
+		return true
+	})
+	return clubs
+}
+
+// ClubIDs returns the club IDs found in the subtree
+func (n XPlorNetworkNode) ClubIDs() []string {
+	var ids []string
+	for _, club := range n.ClubNodes() {
+		if id, err := club.ClubIDValue(); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Find returns the node with the given ID in the subtree
+func (n XPlorNetworkNode) Find(nodeId string) (*XPlorNetworkNode, bool) {
+	var found *XPlorNetworkNode
+	n.Walk(func(node XPlorNetworkNode, _ int) bool {
+		if found != nil {
+			return false
+		}
+		if id, err := node.NetworkNodeID(); err == nil && id == nodeId {
+			found = &node
+			return false
+		}
+		return true
+	})
+	return found, found != nil
+}
+
+// Walk visits every node of the collection and its descendants depth-first
+func (ns XPlorNetworkNodes) Walk(fn func(node XPlorNetworkNode, depth int) bool) {
+	for _, node := range ns.Members {
+		node.Walk(fn)
+	}
+}
+
+// Find returns the node with the given ID anywhere in the collection
+func (ns XPlorNetworkNodes) Find(nodeId string) (*XPlorNetworkNode, bool) {
+	for _, node := range ns.Members {
+		if found, ok := node.Find(nodeId); ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+// ClubNodes returns every club node in the collection, without duplicates
+func (ns XPlorNetworkNodes) ClubNodes() []XPlorNetworkNode {
+	seen := make(map[string]bool)
+	var clubs []XPlorNetworkNode
+	for _, node := range ns.Members {
+		for _, club := range node.ClubNodes() {
+			id, _ := club.NetworkNodeID()
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			clubs = append(clubs, club)
+		}
+	}
+	return clubs
+}
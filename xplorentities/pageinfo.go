@@ -0,0 +1,60 @@
+package xplorentities
+
+// PageInfo summarizes the position of a collection page
+type PageInfo struct {
+	TotalItems   int  // hydra:totalItems, 0 when unknown
+	HasTotal     bool // Whether TotalItems is known
+	ItemsPerPage int  // Page size, 0 when unknown
+	CurrentPage  int
+	PreviousPage int // 0 on the first page
+	NextPage     int // 0 on the last page
+	LastPage     int // 0 when unknown
+}
+
+// NewPageInfo derives the page metadata of a collection page holding received members.
+// totalItems is nil when the page has no hydra:totalItems; LastPage then comes from the view alone.
+func NewPageInfo(totalItems *int, received int, view *HydraView) PageInfo {
+	info := PageInfo{CurrentPage: 1}
+	if totalItems != nil {
+		info.TotalItems, info.HasTotal = *totalItems, true
+	}
+	if view != nil {
+		if page, err := view.CurrentPageNumber(); err == nil {
+			info.CurrentPage = page
+		}
+		if page, err := view.PreviousPageNumber(); err == nil {
+			info.PreviousPage = page
+		}
+		if page, err := view.NextPageNumber(); err == nil {
+			info.NextPage = page
+		}
+		if page, err := view.LastPageNumber(); err == nil {
+			info.LastPage = page
+		}
+		if itemsPerPage, err := view.ItemsPerPage(); err == nil {
+			info.ItemsPerPage = itemsPerPage
+		}
+	}
+	if info.ItemsPerPage == 0 && info.NextPage > 0 {
+		info.ItemsPerPage = received // Only full pages are followed by another one
+	}
+	if info.LastPage == 0 && info.HasTotal && info.ItemsPerPage > 0 {
+		info.LastPage = max(1, (info.TotalItems+info.ItemsPerPage-1)/info.ItemsPerPage)
+	}
+	return info
+}
+
+// HasNext reports whether another page follows
+func (p PageInfo) HasNext() bool {
+	return p.NextPage > 0
+}
+
+// HasPrevious reports whether a page precedes
+func (p PageInfo) HasPrevious() bool {
+	return p.PreviousPage > 0
+}
+
+// PageInfo returns the page metadata of the collection
+func (c Collection[T]) PageInfo() PageInfo {
+	return NewPageInfo(c.TotalItems, len(c.Members), c.View)
+}
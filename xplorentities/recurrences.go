@@ -12,11 +12,7 @@ import (
 
 // Estructuras principales para Recurrence Collection
 type XPlorRecurrences struct {
-	Context     string            `json:"@context"`
-	ID          string            `json:"@id"`
-	Type        string            `json:"@type"`
-	Recurrences []XPlorRecurrence `json:"hydra:member"`
-	Pagination  HydraView         `json:"hydra:view"`
+	Collection[XPlorRecurrence]
 }
 
 type XPlorRecurrence struct {
@@ -281,12 +277,12 @@ func (rc *XPlorRecurrence) IsActive() bool {
 
 // Obtain all unique club IDs from the recurrences in the collection
 func (rc *XPlorRecurrences) AllRecurrenceIDs() ([]string, error) {
-	if len(rc.Recurrences) == 0 {
+	if len(rc.Members) == 0 {
 		return nil, errors.New("no recurrences available")
 	}
 
-	ids := make([]string, len(rc.Recurrences))
-	for i, recurrence := range rc.Recurrences {
+	ids := make([]string, len(rc.Members))
+	for i, recurrence := range rc.Members {
 		id, err := recurrence.RecurrenceID()
 		if err != nil {
 			return nil, err
@@ -298,12 +294,12 @@ func (rc *XPlorRecurrences) AllRecurrenceIDs() ([]string, error) {
 
 // Obteins all unique club IDs from the recurrences in the collection
 func (rc *XPlorRecurrences) AllActivityIDs() ([]string, error) {
-	if len(rc.Recurrences) == 0 {
+	if len(rc.Members) == 0 {
 		return nil, errors.New("no recurrences available")
 	}
 
 	activityIDs := make([]string, 0)
-	for _, recurrence := range rc.Recurrences {
+	for _, recurrence := range rc.Members {
 		activityID, err := recurrence.ClassEventType.ActivityID()
 		if err == nil { // Solo agregar si no hay error
 			activityIDs = append(activityIDs, activityID)
@@ -319,12 +315,12 @@ func (rc *XPlorRecurrences) AllActivityIDs() ([]string, error) {
 
 // Obtain all unique club IDs from the recurrences collection
 func (rc *XPlorRecurrences) AllStudioIDs() ([]string, error) {
-	if len(rc.Recurrences) == 0 {
+	if len(rc.Members) == 0 {
 		return nil, errors.New("no recurrences available")
 	}
 
 	studioIDs := make([]string, 0)
-	for _, recurrence := range rc.Recurrences {
+	for _, recurrence := range rc.Members {
 		studioID, err := recurrence.ClassEventType.StudioID()
 		if err == nil { // Solo agregar si no hay error
 			studioIDs = append(studioIDs, studioID)
@@ -340,12 +336,12 @@ func (rc *XPlorRecurrences) AllStudioIDs() ([]string, error) {
 
 // Obtains all unique club IDs from the recurrences collection
 func (rc *XPlorRecurrences) AllClubIDs() ([]string, error) {
-	if len(rc.Recurrences) == 0 {
+	if len(rc.Members) == 0 {
 		return nil, errors.New("no recurrences available")
 	}
 
 	clubIDs := make([]string, 0)
-	for _, recurrence := range rc.Recurrences {
+	for _, recurrence := range rc.Members {
 		clubID, err := recurrence.ClassEventType.ClubID()
 		if err == nil { // Solo agregar si no hay error
 			clubIDs = append(clubIDs, clubID)
@@ -380,7 +376,42 @@ type XPlorRecurrencesParams struct {
 	EndedAtAfter            *time.Time
 	EndedAtStrictlyAfter    *time.Time
 	Week                    *string
-	IncludeFutureClassEvent *string
+	IncludeFutureClassEvent *string // true or false
+	StartedAt               *DateFilter
+	EndedAt                 *DateFilter
+}
+
+// recurrenceDateLayout is the date format sent by the recurrence date filters
+const recurrenceDateLayout = "2006-01-02T15:04:05"
+
+// dateFilters returns the typed and legacy filters of each date property
+func (p XPlorRecurrencesParams) dateFilters() []struct {
+	property string
+	typed    *DateFilter
+	legacy   DateFilter
+} {
+	return []struct {
+		property string
+		typed    *DateFilter
+		legacy   DateFilter
+	}{
+		{"startedAt", p.StartedAt, DateFilter{p.StartedAtBefore, p.StartedAtStrictlyBefore, p.StartedAtAfter, p.StartedAtStrictlyAfter}},
+		{"endedAt", p.EndedAt, DateFilter{p.EndedAtBefore, p.EndedAtStrictlyBefore, p.EndedAtAfter, p.EndedAtStrictlyAfter}},
+	}
+}
+
+// Validate checks the date ranges and boolean filters
+func (p XPlorRecurrencesParams) Validate() error {
+	var errs []error
+	for _, date := range p.dateFilters() {
+		filter, err := mergeDateFilter(date.property, date.typed, date.legacy)
+		if err == nil {
+			err = filter.Validate(date.property)
+		}
+		errs = append(errs, err)
+	}
+	errs = append(errs, validateBoolString("includeFutureClassEvent", p.IncludeFutureClassEvent))
+	return errors.Join(errs...)
 }
 
 // ToValues converts the params to url.Values for query parameters
@@ -425,32 +456,9 @@ func (p XPlorRecurrencesParams) ToValues(orgName string, values *url.Values) {
 		values.Add("classEventType.studio[]", "/"+orgName+"/studios/"+studioId)
 	}
 
-	// StartedAt date filters
-	if p.StartedAtBefore != nil {
-		values.Set("startedAt[before]", p.StartedAtBefore.Format("2006-01-02T15:04:05"))
-	}
-	if p.StartedAtStrictlyBefore != nil {
-		values.Set("startedAt[strictly_before]", p.StartedAtStrictlyBefore.Format("2006-01-02T15:04:05"))
-	}
-	if p.StartedAtAfter != nil {
-		values.Set("startedAt[after]", p.StartedAtAfter.Format("2006-01-02T15:04:05"))
-	}
-	if p.StartedAtStrictlyAfter != nil {
-		values.Set("startedAt[strictly_after]", p.StartedAtStrictlyAfter.Format("2006-01-02T15:04:05"))
-	}
-
-	// EndedAt date filters
-	if p.EndedAtBefore != nil {
-		values.Set("endedAt[before]", p.EndedAtBefore.Format("2006-01-02T15:04:05"))
-	}
-	if p.EndedAtStrictlyBefore != nil {
-		values.Set("endedAt[strictly_before]", p.EndedAtStrictlyBefore.Format("2006-01-02T15:04:05"))
-	}
-	if p.EndedAtAfter != nil {
-		values.Set("endedAt[after]", p.EndedAtAfter.Format("2006-01-02T15:04:05"))
-	}
-	if p.EndedAtStrictlyAfter != nil {
-		values.Set("endedAt[strictly_after]", p.EndedAtStrictlyAfter.Format("2006-01-02T15:04:05"))
+	// Date filters
+	for _, date := range p.dateFilters() {
+		applyDateFilters(date.property, recurrenceDateLayout, date.typed, date.legacy, values)
 	}
 
 	// Week filter
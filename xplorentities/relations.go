@@ -0,0 +1,161 @@
+package xplorentities
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Relation links an entity to another resource through one of its IRI fields
+type Relation struct {
+	Name     string                  // Relation name used by Expand, e.g. "coach"
+	Resource Resource                // Resource the reference points to
+	Ref      string                  // IRI or bare ID; empty when the field is unset
+	Attach   func(body []byte) error // Decodes the fetched resource into the embedded field
+}
+
+// Path returns the path of the referenced entity relative to the enterprise prefix, or "" when unset
+func (r Relation) Path() string {
+	ref := strings.TrimSpace(r.Ref)
+	if ref == "" {
+		return ""
+	}
+	id, err := ExtractIDFromString(ref, "")
+	if err != nil || id == "" || id == "/" || id == "." {
+		return ""
+	}
+	return r.Resource.Path() + "/" + id
+}
+
+// Linked is implemented by entities whose references can be expanded into embedded resources
+type Linked interface {
+	Relations() []Relation
+}
+
+// attachTo returns a decoder storing the resource in target
+func attachTo[T any](target **T) func([]byte) error {
+	return func(body []byte) error {
+		var value T
+		if err := json.Unmarshal(body, &value); err != nil {
+			return err
+		}
+		*target = &value
+		return nil
+	}
+}
+
+func ref(field *string) string {
+	if field == nil {
+		return ""
+	}
+	return *field
+}
+
+// ClassEmbedded holds the resources resolved from the references of a class
+type ClassEmbedded struct {
+	Club       *XPlorClub
+	Studio     *XPlorStudio
+	Activity   *XPlorActivity
+	Coach      *XPloreCoach
+	Recurrence *XPlorRecurrence
+}
+
+// Relations lists the expandable references of a class: club, studio, activity, coach and recurrence
+func (c *XPlorClass) Relations() []Relation {
+	return []Relation{
+		{Name: "club", Resource: ResourceClubs, Ref: ref(c.Club), Attach: attachTo(&c.Embedded.Club)},
+		{Name: "studio", Resource: ResourceStudios, Ref: ref(c.Studio), Attach: attachTo(&c.Embedded.Studio)},
+		{Name: "activity", Resource: ResourceActivities, Ref: ref(c.Activity), Attach: attachTo(&c.Embedded.Activity)},
+		{Name: "coach", Resource: ResourceCoaches, Ref: ref(c.Coach), Attach: attachTo(&c.Embedded.Coach)},
+		{Name: "recurrence", Resource: ResourceRecurrences, Ref: ref(c.Recurrence), Attach: attachTo(&c.Embedded.Recurrence)},
+	}
+}
+
+// AttendeeEmbedded holds the resources resolved from the references of an attendee
+type AttendeeEmbedded struct {
+	Contact *XPlorContact
+}
+
+// Relations lists the expandable references of an attendee: contact
+func (a *XPlorAttendee) Relations() []Relation {
+	return []Relation{
+		{Name: "contact", Resource: ResourceContacts, Ref: ref(a.ContactId), Attach: attachTo(&a.Embedded.Contact)},
+	}
+}
+
+// SubscriptionEmbedded holds the resources resolved from the references of a subscription
+type SubscriptionEmbedded struct {
+	Article *XPlorArticle
+	Club    *XPlorClub
+	Contact *XPlorContact
+}
+
+// Relations lists the expandable references of a subscription: article, club and contact
+func (s *XPlorSubscription) Relations() []Relation {
+	return []Relation{
+		{Name: "article", Resource: ResourceArticles, Ref: s.ArticleId, Attach: attachTo(&s.Embedded.Article)},
+		{Name: "club", Resource: ResourceClubs, Ref: s.ClubId, Attach: attachTo(&s.Embedded.Club)},
+		{Name: "contact", Resource: ResourceContacts, Ref: ref(s.Contact.Id), Attach: attachTo(&s.Embedded.Contact)},
+	}
+}
+
+// ContactTagEmbedded holds the resources resolved from the references of a contact tag
+type ContactTagEmbedded struct {
+	Contact      *XPlorContact
+	Subscription *XPlorSubscription
+}
+
+// Relations lists the expandable references of a contact tag: contact and subscription
+func (ct *XPlorContactTag) Relations() []Relation {
+	return []Relation{
+		{Name: "contact", Resource: ResourceContacts, Ref: ref(ct.Contact), Attach: attachTo(&ct.Embedded.Contact)},
+		{Name: "subscription", Resource: ResourceSubscriptions, Ref: ref(ct.Subscription), Attach: attachTo(&ct.Embedded.Subscription)},
+	}
+}
+
+// LinkedItems returns the expandable entities held by target.
+// target may be a Linked entity, a slice of Linked, a slice of expandable entities or one of their collections.
+func LinkedItems(target any) ([]Linked, bool) {
+	switch t := target.(type) {
+	case Linked:
+		return []Linked{t}, true
+	case []Linked:
+		return t, true
+	case []XPlorClass:
+		return linkedSlice(t), true
+	case *XPlorClasses:
+		return linkedSlice(t.Members), true
+	case *Collection[XPlorClass]:
+		return linkedSlice(t.Members), true
+	case []XPlorAttendee:
+		return linkedSlice(t), true
+	case *XPlorAttendees:
+		return linkedSlice(t.Members), true
+	case *Collection[XPlorAttendee]:
+		return linkedSlice(t.Members), true
+	case []XPlorSubscription:
+		return linkedSlice(t), true
+	case *XPlorSubscriptions:
+		return linkedSlice(t.Members), true
+	case *Collection[XPlorSubscription]:
+		return linkedSlice(t.Members), true
+	case []XPlorContactTag:
+		return linkedSlice(t), true
+	case *XPlorContactTags:
+		return linkedSlice(t.Members), true
+	case *Collection[XPlorContactTag]:
+		return linkedSlice(t.Members), true
+	}
+	return nil, false
+}
+
+// linkedSlice addresses the elements of items in place so expansions are attached to the caller's slice
+func linkedSlice[T any, P interface {
+	*T
+	Linked
+}](items []T) []Linked {
+	linked := make([]Linked, len(items))
+	for i := range items {
+		linked[i] = P(&items[i])
+	}
+	return linked
+}
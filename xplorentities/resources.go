@@ -0,0 +1,110 @@
+package xplorentities
+
+import (
+	"slices"
+	"strings"
+)
+
+// Resource identifies an API collection by its path segment (e.g. "clubs", "class_events")
+type Resource string
+
+const (
+	ResourceActivities    Resource = "activities"
+	ResourceArticles      Resource = "articles"
+	ResourceAttendees     Resource = "attendees"
+	ResourceClasses       Resource = "class_events"
+	ResourceClassTypes    Resource = "class_event_types"
+	ResourceClubs         Resource = "clubs"
+	ResourceCoaches       Resource = "coaches"
+	ResourceContacts      Resource = "contacts"
+	ResourceContactImages Resource = "files/contact_images"
+	ResourceContactTags   Resource = "contact_tags"
+	ResourceCounterLines  Resource = "counter_lines"
+	ResourceEvents        Resource = "events"
+	ResourceFamilies      Resource = "families"
+	ResourceNetworkNodes  Resource = "network_nodes"
+	ResourceRecurrences   Resource = "recurrences"
+	ResourceStudios       Resource = "studios"
+	ResourceSubscriptions Resource = "subscriptions"
+	ResourceUsers         Resource = "users"
+	ResourceZones         Resource = "zones"
+	ResourceToken         Resource = "oauth/v2/token"
+)
+
+// Resources lists every resource known to the SDK, excluding the token endpoint
+var Resources = []Resource{
+	ResourceActivities,
+	ResourceArticles,
+	ResourceAttendees,
+	ResourceClasses,
+	ResourceClassTypes,
+	ResourceClubs,
+	ResourceCoaches,
+	ResourceContacts,
+	ResourceContactImages,
+	ResourceContactTags,
+	ResourceCounterLines,
+	ResourceEvents,
+	ResourceFamilies,
+	ResourceNetworkNodes,
+	ResourceRecurrences,
+	ResourceStudios,
+	ResourceSubscriptions,
+	ResourceUsers,
+	ResourceZones,
+}
+
+// pathResources is what ResourceFromPath matches against, built once
+var pathResources = append(slices.Clone(Resources), ResourceToken)
+
+// Path returns the collection path relative to the enterprise prefix (e.g. "/clubs")
+func (r Resource) Path() string {
+	return "/" + string(r)
+}
+
+// NodeScoped reports whether requests on the resource are made for a node, with its club header.
+// Network nodes and users belong to the enterprise and are requested without one.
+func (r Resource) NodeScoped() bool {
+	return r != ResourceNetworkNodes && r != ResourceUsers && r != ResourceToken
+}
+
+// ResourceFromPath returns the resource addressed by a request path.
+// The path may include the API version and enterprise prefix
+// (e.g. "/resa2-mfr/enjoy/clubs/1249" -> ResourceClubs).
+// It returns false when no known resource matches.
+func ResourceFromPath(requestPath string) (Resource, bool) {
+	trimmed := "/" + strings.Trim(strings.Split(requestPath, "?")[0], "/") + "/"
+	var best Resource
+	bestIndex := -1
+	for _, r := range pathResources {
+		// Pick the right-most match so that prefixes never shadow the resource itself
+		index := strings.LastIndex(trimmed, "/"+string(r)+"/")
+		if index > bestIndex || (index == bestIndex && index >= 0 && len(r) > len(best)) {
+			best, bestIndex = r, index
+		}
+	}
+	return best, bestIndex >= 0
+}
+
+// Resource implementations tie each entity type to its collection, so IRI[T] and the
+// generic provider calls can address it
+
+func (XPlorActivity) Resource() Resource     { return ResourceActivities }
+func (XPlorArticle) Resource() Resource      { return ResourceArticles }
+func (XPlorAttendee) Resource() Resource     { return ResourceAttendees }
+func (XPlorClass) Resource() Resource        { return ResourceClasses }
+func (XPlorClassType) Resource() Resource    { return ResourceClassTypes }
+func (XPlorClub) Resource() Resource         { return ResourceClubs }
+func (XPloreCoach) Resource() Resource       { return ResourceCoaches }
+func (XPlorContact) Resource() Resource      { return ResourceContacts }
+func (XPlorContactImage) Resource() Resource { return ResourceContactImages }
+func (XPlorContactTag) Resource() Resource   { return ResourceContactTags }
+func (XPlorCounterLine) Resource() Resource  { return ResourceCounterLines }
+func (XPlorEvent) Resource() Resource        { return ResourceEvents }
+func (XPlorFamily) Resource() Resource       { return ResourceFamilies }
+func (XPlorNetworkNode) Resource() Resource  { return ResourceNetworkNodes }
+func (XPlorRecurrence) Resource() Resource   { return ResourceRecurrences }
+func (XPlorStudio) Resource() Resource       { return ResourceStudios }
+func (XPlorSubscription) Resource() Resource { return ResourceSubscriptions }
+func (XPlorUser) Resource() Resource         { return ResourceUsers }
+func (XPlorZone) Resource() Resource         { return ResourceZones }
@@ -0,0 +1,233 @@
+package xplorentities
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SearchVariableKind describes the kind of value a search variable expects
+type SearchVariableKind string
+
+const (
+	SearchValue  SearchVariableKind = "value"  // Single exact or partial value
+	SearchList   SearchVariableKind = "list"   // Repeatable value, e.g. "clubId[]"
+	SearchDate   SearchVariableKind = "date"   // Date bound, e.g. "startedAt[before]"
+	SearchOrder  SearchVariableKind = "order"  // Sort direction, e.g. "order[startedAt]"
+	SearchExists SearchVariableKind = "exists" // Presence check, e.g. "exists[terminatedAt]"
+)
+
+// SearchVariable is a filter accepted by a collection, as announced in its hydra:search mapping
+type SearchVariable struct {
+	Name     string             // Query parameter name
+	Property string             // Entity property the filter applies to
+	Required bool               // Whether the API rejects queries without it
+	Kind     SearchVariableKind // Expected value kind, derived from the variable name
+}
+
+// searchVariableKind derives the kind of a variable from the API Platform naming conventions
+func searchVariableKind(name string) SearchVariableKind {
+	switch {
+	case strings.HasPrefix(name, "order["):
+		return SearchOrder
+	case strings.HasPrefix(name, "exists["):
+		return SearchExists
+	case strings.HasSuffix(name, "[before]"), strings.HasSuffix(name, "[strictly_before]"),
+		strings.HasSuffix(name, "[after]"), strings.HasSuffix(name, "[strictly_after]"):
+		return SearchDate
+	case strings.HasSuffix(name, "[]"):
+		return SearchList
+	}
+	return SearchValue
+}
+
+// Variables lists the filters of the search template, sorted by name.
+// Variables only present in hydra:template are included without a property.
+func (s HydraSearch) Variables() []SearchVariable {
+	byName := make(map[string]SearchVariable)
+	for _, m := range s.HydraMapping {
+		if m.Variable == "" {
+			continue
+		}
+		byName[m.Variable] = SearchVariable{Name: m.Variable, Property: m.Property, Required: m.Required, Kind: searchVariableKind(m.Variable)}
+	}
+	for _, name := range s.templateVariables() {
+		if _, ok := byName[name]; !ok {
+			byName[name] = SearchVariable{Name: name, Kind: searchVariableKind(name)}
+		}
+	}
+
+	variables := make([]SearchVariable, 0, len(byName))
+	for _, v := range byName {
+		variables = append(variables, v)
+	}
+	sort.Slice(variables, func(i, j int) bool { return variables[i].Name < variables[j].Name })
+	return variables
+}
+
+// Variable returns the filter named name
+func (s HydraSearch) Variable(name string) (SearchVariable, bool) {
+	for _, v := range s.Variables() {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return SearchVariable{}, false
+}
+
+// templateVariables parses the variable names of an RFC 6570 template such as "/enjoy/contacts{?id,id[],clubId}"
+func (s HydraSearch) templateVariables() []string {
+	start := strings.Index(s.HydraTemplate, "{?")
+	end := strings.LastIndex(s.HydraTemplate, "}")
+	if start < 0 || end < start {
+		return nil
+	}
+	var names []string
+	for _, name := range strings.Split(s.HydraTemplate[start+2:end], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// NewQuery starts a query validated against the search template
+func (s *HydraSearch) NewQuery() *SearchQuery {
+	return &SearchQuery{search: s, values: url.Values{}}
+}
+
+// SearchQuery holds filter values keyed by search variable name
+type SearchQuery struct {
+	search *HydraSearch
+	values url.Values
+}
+
+// Set replaces the values of a variable.
+// Several values are only accepted by list variables; passing several values to a
+// variable "x" selects "x[]" when the template declares it.
+func (q *SearchQuery) Set(name string, values ...string) error {
+	variable, err := q.resolve(name, len(values))
+	if err != nil {
+		return err
+	}
+	for _, value := range values {
+		if err := checkSearchValue(variable, value); err != nil {
+			return err
+		}
+	}
+	// A variable is sent under one name, "x" or "x[]", never both
+	q.Del(strings.TrimSuffix(name, "[]"))
+	if len(values) > 0 {
+		q.values[variable.Name] = append([]string(nil), values...)
+	}
+	return nil
+}
+
+// Add appends a value to a list variable, or sets a single-valued one that has no value yet.
+// A second value of a variable "x" moves every value to "x[]" when the template declares it.
+func (q *SearchQuery) Add(name, value string) error {
+	base := strings.TrimSuffix(name, "[]")
+	current := slices.Concat(q.Get(base), q.Get(base+"[]"))
+	variable, err := q.resolve(name, len(current)+1)
+	if err != nil {
+		return err
+	}
+	if err := checkSearchValue(variable, value); err != nil {
+		return err
+	}
+	q.Del(base)
+	q.values[variable.Name] = append(current, value)
+	return nil
+}
+
+// SetTime sets a date variable from a time value
+func (q *SearchQuery) SetTime(name string, t time.Time) error {
+	return q.Set(name, t.Format(APIDateTimeLayout))
+}
+
+// Del removes a variable from the query
+func (q *SearchQuery) Del(name string) {
+	q.values.Del(name)
+	q.values.Del(name + "[]")
+}
+
+// Get returns the values of a variable
+func (q *SearchQuery) Get(name string) []string {
+	return q.values[name]
+}
+
+// Validate checks that every required variable has a value
+func (q *SearchQuery) Validate() error {
+	if q.search == nil {
+		return errors.New("search query has no template, start it with HydraSearch.NewQuery")
+	}
+	var errs []error
+	for _, v := range q.search.Variables() {
+		if v.Required && len(q.values[v.Name]) == 0 {
+			errs = append(errs, &FilterError{Filter: v.Name, Reason: "required by the search template"})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Values returns a copy of the query parameters
+func (q *SearchQuery) Values() url.Values {
+	values := make(url.Values, len(q.values))
+	for name, v := range q.values {
+		values[name] = append([]string(nil), v...)
+	}
+	return values
+}
+
+// MarshalJSON encodes the query as a map of variable names to values, so a form state can be stored
+func (q *SearchQuery) MarshalJSON() ([]byte, error) {
+	return json.Marshal(q.values)
+}
+
+// resolve finds the variable that receives count values under name
+func (q *SearchQuery) resolve(name string, count int) (SearchVariable, error) {
+	if q.search == nil {
+		return SearchVariable{}, &FilterError{Filter: name, Reason: "query has no search template, start it with HydraSearch.NewQuery"}
+	}
+	variable, ok := q.search.Variable(name)
+	if count > 1 && (!ok || variable.Kind != SearchList) {
+		if list, listOk := q.search.Variable(name + "[]"); listOk {
+			return list, nil
+		}
+	}
+	if !ok {
+		return SearchVariable{}, &FilterError{Filter: name, Reason: "not a variable of the search template"}
+	}
+	if count > 1 && variable.Kind != SearchList {
+		return SearchVariable{}, &FilterError{Filter: name, Reason: "accepts a single value"}
+	}
+	return variable, nil
+}
+
+// checkSearchValue validates a value against the kind of its variable
+func checkSearchValue(variable SearchVariable, value string) error {
+	switch variable.Kind {
+	case SearchOrder:
+		return validateEnum(variable.Name, value, string(OrderAsc), string(OrderDesc))
+	case SearchExists:
+		return validateEnum(variable.Name, value, "true", "false", "1", "0")
+	case SearchDate:
+		for _, layout := range []string{APIDateTimeLayout, "2006-01-02T15:04:05", time.RFC3339, "2006-01-02"} {
+			if _, err := time.Parse(layout, value); err == nil {
+				return nil
+			}
+		}
+		return &FilterError{Filter: variable.Name, Value: value, Reason: "expected a date such as Y-m-d H:i:s"}
+	}
+	if strings.TrimSpace(value) == "" {
+		return &FilterError{Filter: variable.Name, Reason: "empty value"}
+	}
+	return nil
+}
+
+// SearchCollection is a collection page decoded without knowledge of its member type
+type SearchCollection = Collection[json.RawMessage]
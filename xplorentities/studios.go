@@ -8,11 +8,7 @@ import (
 
 // ---------- Colección ----------
 type XPlorStudios struct {
-	Context    string        `json:"@context"`
-	ID         string        `json:"@id"`
-	Type       string        `json:"@type"`
-	Studios    []XPlorStudio `json:"hydra:member"`
-	Pagination HydraView     `json:"hydra:view"`
+	Collection[XPlorStudio]
 }
 
 // ---------- Entidad Studio ----------
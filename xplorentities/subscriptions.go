@@ -1,6 +1,8 @@
 package xplorentities
 
 import (
+	"cmp"
+	"errors"
 	"net/url"
 	"strconv"
 	"strings"
@@ -10,11 +12,7 @@ import (
 
 // XPlorSubscriptions representa la colección de suscripciones
 type XPlorSubscriptions struct {
-	Context       string              `json:"@context"`
-	ID            string              `json:"@id"`
-	Type          string              `json:"@type"`
-	Subscriptions []XPlorSubscription `json:"hydra:member"`
-	Pagination    HydraView           `json:"hydra:view"`
+	Collection[XPlorSubscription]
 }
 
 // XPlorSubscription representa una suscripción individual
@@ -63,6 +61,8 @@ type XPlorSubscription struct {
 	RegularDebitDay         int                  `json:"regularDebitDay"`
 	Family                  string               `json:"family"`
 	Properties              map[string]any       `json:"properties"`
+
+	Embedded SubscriptionEmbedded `json:"-"` // Filled by Expand
 }
 
 // Contact representa la información de contacto
@@ -252,10 +252,101 @@ type XPlorSubscriptionsParams struct {
 	Current                *bool
 	IsTerminated           *bool
 	IsActive               *bool
-	OrderBy                string // "validFrom", "createdAt", "validThrough"
+	OrderBy                string // "validFrom", "createdAt", "validThrough"; without OrderDirection sends asc (it used to send no order)
 	OrderDirection         string // "asc", "desc"
 	Query                  string // Search on contact number, firstname or lastname
 	ExistsTerminatedAt     *bool
+
+	// Typed filters, combined with the legacy fields above
+	ValidFrom               *DateFilter
+	ValidThrough            *DateFilter
+	EngagedThrough          *DateFilter
+	CreatedAt               *DateFilter
+	TerminatedAt            *DateFilter
+	UpdatedAt               *DateFilter
+	InclusiveEndDate        *DateFilter
+	InclusiveEngagedThrough *DateFilter
+	Order                   []Order
+	Exists                  ExistsFilter
+}
+
+// SubscriptionOrderFields lists the fields subscriptions can be ordered by
+var SubscriptionOrderFields = []string{"validFrom", "createdAt", "validThrough"}
+
+// WarrantyStates lists the values accepted by the warrantyState filter of subscriptions; append to it if the API adds one
+var WarrantyStates = []string{"pending", "valid", "invalid", "canceled"}
+
+// SubscriptionExistsFields lists the properties accepted by the exists filter of subscriptions
+var SubscriptionExistsFields = []string{"terminatedAt"}
+
+// subscriptionDateFilter pairs a typed date filter with its legacy before/after strings
+type subscriptionDateFilter struct {
+	property      string
+	typed         *DateFilter
+	before, after string
+}
+
+func (p XPlorSubscriptionsParams) dateFilters() []subscriptionDateFilter {
+	return []subscriptionDateFilter{
+		{"validFrom", p.ValidFrom, p.ValidFromBefore, p.ValidFromAfter},
+		{"validThrough", p.ValidThrough, p.ValidThroughBefore, p.ValidThroughAfter},
+		{"engagedThrough", p.EngagedThrough, p.EngagedThroughBefore, p.EngagedThroughAfter},
+		{"createdAt", p.CreatedAt, p.CreatedAtBefore, p.CreatedAtAfter},
+		{"terminatedAt", p.TerminatedAt, p.TerminatedAtBefore, p.TerminatedAtAfter},
+		{"updatedAt", p.UpdatedAt, p.UpdatedAtBefore, p.UpdatedAtAfter},
+		{"inclusiveEndDate", p.InclusiveEndDate, p.InclusiveEndDateBefore, p.InclusiveEndDateAfter},
+		{"inclusiveEngagedThrough", p.InclusiveEngagedThrough, p.InclusiveEngagedBefore, p.InclusiveEngagedAfter},
+	}
+}
+
+func (p XPlorSubscriptionsParams) orders() []Order {
+	orders := p.Order
+	if strings.TrimSpace(p.OrderBy) != "" || strings.TrimSpace(p.OrderDirection) != "" {
+		// OrderBy alone sorts ascending, like OrderBy(field)
+		direction := OrderDirection(cmp.Or(strings.TrimSpace(p.OrderDirection), string(OrderAsc)))
+		orders = append([]Order{{Field: strings.TrimSpace(p.OrderBy), Direction: direction}}, orders...)
+	}
+	return orders
+}
+
+func (p XPlorSubscriptionsParams) exists() ExistsFilter {
+	if p.ExistsTerminatedAt == nil {
+		return p.Exists
+	}
+	exists := ExistsFilter{"terminatedAt": *p.ExistsTerminatedAt}
+	for property, value := range p.Exists {
+		exists[property] = value
+	}
+	return exists
+}
+
+// Validate checks the date, order, exists and warranty state filters
+func (p XPlorSubscriptionsParams) Validate() error {
+	var errs []error
+	for _, date := range p.dateFilters() {
+		before, beforeErr := parseFilterDate(date.property+"[before]", date.before)
+		after, afterErr := parseFilterDate(date.property+"[after]", date.after)
+		if beforeErr != nil || afterErr != nil {
+			errs = append(errs, beforeErr, afterErr)
+			continue
+		}
+		filter, err := mergeDateFilter(date.property, date.typed, DateFilter{Before: before, After: after})
+		if err == nil {
+			err = filter.Validate(date.property)
+		}
+		errs = append(errs, err)
+	}
+	if p.ExistsTerminatedAt != nil {
+		if _, ok := p.Exists["terminatedAt"]; ok {
+			errs = append(errs, &FilterError{Filter: "exists[terminatedAt]", Reason: "set both by Exists and ExistsTerminatedAt"})
+		}
+	}
+	errs = append(errs,
+		validateOrders(p.orders(), SubscriptionOrderFields),
+		p.Exists.validate(SubscriptionExistsFields),
+		validateEnums("warrantyState", p.WarrantyState, p.WarrantyStates, WarrantyStates),
+	)
+	return errors.Join(errs...)
 }
 
 // ToValues devuelve los parámetros como url.Values
@@ -325,53 +416,14 @@ func (p XPlorSubscriptionsParams) ToValues(values *url.Values) {
 			values.Add("clubId[]", id)
 		}
 	}
-	if strings.TrimSpace(p.ValidFromBefore) != "" {
-		values.Set("validFrom[before]", p.ValidFromBefore)
-	}
-	if strings.TrimSpace(p.ValidFromAfter) != "" {
-		values.Set("validFrom[after]", p.ValidFromAfter)
-	}
-	if strings.TrimSpace(p.ValidThroughBefore) != "" {
-		values.Set("validThrough[before]", p.ValidThroughBefore)
-	}
-	if strings.TrimSpace(p.ValidThroughAfter) != "" {
-		values.Set("validThrough[after]", p.ValidThroughAfter)
-	}
-	if strings.TrimSpace(p.EngagedThroughBefore) != "" {
-		values.Set("engagedThrough[before]", p.EngagedThroughBefore)
-	}
-	if strings.TrimSpace(p.EngagedThroughAfter) != "" {
-		values.Set("engagedThrough[after]", p.EngagedThroughAfter)
-	}
-	if strings.TrimSpace(p.CreatedAtBefore) != "" {
-		values.Set("createdAt[before]", p.CreatedAtBefore)
-	}
-	if strings.TrimSpace(p.CreatedAtAfter) != "" {
-		values.Set("createdAt[after]", p.CreatedAtAfter)
-	}
-	if strings.TrimSpace(p.TerminatedAtBefore) != "" {
-		values.Set("terminatedAt[before]", p.TerminatedAtBefore)
-	}
-	if strings.TrimSpace(p.TerminatedAtAfter) != "" {
-		values.Set("terminatedAt[after]", p.TerminatedAtAfter)
-	}
-	if strings.TrimSpace(p.UpdatedAtBefore) != "" {
-		values.Set("updatedAt[before]", p.UpdatedAtBefore)
-	}
-	if strings.TrimSpace(p.UpdatedAtAfter) != "" {
-		values.Set("updatedAt[after]", p.UpdatedAtAfter)
-	}
-	if strings.TrimSpace(p.InclusiveEndDateBefore) != "" {
-		values.Set("inclusiveEndDate[before]", p.InclusiveEndDateBefore)
-	}
-	if strings.TrimSpace(p.InclusiveEndDateAfter) != "" {
-		values.Set("inclusiveEndDate[after]", p.InclusiveEndDateAfter)
-	}
-	if strings.TrimSpace(p.InclusiveEngagedBefore) != "" {
-		values.Set("inclusiveEngagedThrough[before]", p.InclusiveEngagedBefore)
-	}
-	if strings.TrimSpace(p.InclusiveEngagedAfter) != "" {
-		values.Set("inclusiveEngagedThrough[after]", p.InclusiveEngagedAfter)
+	for _, date := range p.dateFilters() {
+		if strings.TrimSpace(date.before) != "" {
+			values.Set(date.property+"[before]", date.before)
+		}
+		if strings.TrimSpace(date.after) != "" {
+			values.Set(date.property+"[after]", date.after)
+		}
+		date.typed.apply(date.property, APIDateTimeLayout, values)
 	}
 	if p.Consumed != nil {
 		values.Set("consumed", strconv.FormatBool(*p.Consumed))
@@ -394,14 +446,8 @@ func (p XPlorSubscriptionsParams) ToValues(values *url.Values) {
 	if strings.TrimSpace(p.Query) != "" {
 		values.Set("q", p.Query)
 	}
-	if strings.TrimSpace(p.OrderBy) != "" {
-		if strings.TrimSpace(p.OrderDirection) != "" {
-			values.Set("order["+p.OrderBy+"]", p.OrderDirection)
-		}
-	}
-	if p.ExistsTerminatedAt != nil {
-		values.Set("exists[terminatedAt]", strconv.FormatBool(*p.ExistsTerminatedAt))
-	}
+	applyOrders(p.orders(), values)
+	p.exists().apply(values)
 }
 
 // Métodos para obtener IDs
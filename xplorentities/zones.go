@@ -9,11 +9,7 @@ import (
 
 // XPlorZones represents the collection of zones
 type XPlorZones struct {
-	Context    string      `json:"@context"`
-	ID         string      `json:"@id"`
-	Type       string      `json:"@type"`
-	Zones      []XPlorZone `json:"hydra:member"`
-	Pagination *HydraView  `json:"hydra:view,omitempty"`
+	Collection[XPlorZone]
 }
 
 // XPlorZone represents an individual zone
@@ -53,6 +49,11 @@ type XPlorZonesParams struct {
 	Name    string
 }
 
+// Validate implements QueryParams; zone filters take any value
+func (p XPlorZonesParams) Validate() error {
+	return nil
+}
+
 // ToValues converts the zone search parameters to url.Values for query parameters
 func (p XPlorZonesParams) ToValues(values *url.Values) {
 	clubID := strings.TrimSpace(p.ClubID)
@@ -0,0 +1,218 @@
+package xplorexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// Column maps one CSV column to a value of T
+type Column[T any] struct {
+	Header string
+	Value  func(item T) string
+}
+
+// Format controls how values are flattened into CSV cells
+type Format struct {
+	// TimeLayout formats dates; empty keeps the API format: naive datetimes without offset, others in RFC 3339
+	TimeLayout string
+	// ListSeparator joins the values of lists; empty uses "|"
+	ListSeparator string
+	// KeepIRIs writes IRIs as they are instead of their trailing ID
+	KeepIRIs bool
+}
+
+// maxNestedDepth bounds the flattening of nested structs, which may be recursive
+const maxNestedDepth = 3
+
+var (
+	jsonMarshaler = reflect.TypeFor[json.Marshaler]()
+	localTimeType = reflect.TypeFor[util.LocalTime]()
+	timeType      = reflect.TypeFor[time.Time]()
+)
+
+// Columns returns the default mapping of T: one column per JSON field, in declaration order, named after it.
+// Nested structs such as XPlorAddress are flattened into "address.streetAddress" columns; JSON-LD keys
+// other than @id are left out. The mapping only depends on T, so it is stable between exports.
+func Columns[T any](format Format) []Column[T] {
+	var columns []Column[T]
+	add := func(header string, index []int) {
+		columns = append(columns, Column[T]{Header: header, Value: func(item T) string {
+			value, ok := fieldByIndex(reflect.ValueOf(&item).Elem(), index)
+			if !ok {
+				return ""
+			}
+			return format.Value(value.Interface())
+		}})
+	}
+	flatten(reflect.TypeFor[T](), "", nil, 0, add)
+	return columns
+}
+
+func flatten(t reflect.Type, prefix string, index []int, depth int, add func(header string, index []int)) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || isLeaf(t) || depth > maxNestedDepth {
+		if prefix == "" {
+			prefix = "value"
+		}
+		add(prefix, index)
+		return
+	}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			flatten(field.Type, prefix, fieldIndex, depth, add)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.HasPrefix(name, "@") && name != "@id" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		flatten(field.Type, name, fieldIndex, depth+1, add)
+	}
+}
+
+// isLeaf reports whether values of t are written in one cell rather than flattened
+func isLeaf(t reflect.Type) bool {
+	return t == localTimeType || t == timeType || t.Implements(jsonMarshaler) || reflect.PointerTo(t).Implements(jsonMarshaler)
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex returning false on nil pointers along the way
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
+
+// Select returns the columns named by headers, in that order
+func Select[T any](columns []Column[T], headers ...string) ([]Column[T], error) {
+	byHeader := make(map[string]Column[T], len(columns))
+	for _, column := range columns {
+		byHeader[column.Header] = column
+	}
+	selected := make([]Column[T], 0, len(headers))
+	for _, header := range headers {
+		column, ok := byHeader[header]
+		if !ok {
+			return nil, fmt.Errorf("xplorexport: unknown column %q", header)
+		}
+		selected = append(selected, column)
+	}
+	return selected, nil
+}
+
+// Value formats a value as a CSV cell; custom columns can use it to format like the default ones
+func (f Format) Value(value any) string {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return ""
+	}
+
+	switch value := v.Interface().(type) {
+	case util.LocalTime:
+		if value.IsZero() {
+			return ""
+		}
+		if f.TimeLayout != "" {
+			return value.Format(f.TimeLayout)
+		}
+		return f.marshaled(value)
+	case time.Time:
+		if value.IsZero() {
+			return ""
+		}
+		if f.TimeLayout != "" {
+			return value.Format(f.TimeLayout)
+		}
+		return value.Format(time.RFC3339)
+	case json.Marshaler:
+		return f.marshaled(value)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return f.iri(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice, reflect.Array:
+		values := make([]string, v.Len())
+		for i := range values {
+			element := v.Index(i)
+			if kind := reflect.Indirect(element).Kind(); kind == reflect.Struct || kind == reflect.Map {
+				values[i] = f.marshaled(element.Interface())
+			} else {
+				values[i] = f.Value(element.Interface())
+			}
+		}
+		return strings.Join(values, f.listSeparator())
+	}
+	return f.marshaled(v.Interface())
+}
+
+// marshaled writes value as JSON, unquoted when it is a JSON string
+func (f Format) marshaled(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil || string(data) == "null" {
+		return ""
+	}
+	var text string
+	if json.Unmarshal(data, &text) == nil {
+		return f.iri(text)
+	}
+	return string(data)
+}
+
+// iri returns the trailing ID of an IRI (see xplorentities.ShortID) unless KeepIRIs is set
+func (f Format) iri(value string) string {
+	if f.KeepIRIs {
+		return value
+	}
+	return xplorentities.ShortID(value)
+}
+
+func (f Format) listSeparator() string {
+	if f.ListSeparator == "" {
+		return "|"
+	}
+	return f.ListSeparator
+}
@@ -0,0 +1,179 @@
+// Package xplorexport writes XPlor collections to flat files: CSV with a configurable column mapping, or NDJSON.
+// Exports read the collection page by page, so memory use does not grow with the number of rows.
+package xplorexport
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorcore"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// DefaultItemsPerPage is the page size used when none is given
+const DefaultItemsPerPage = 100
+
+// Source yields the items of a collection in order until yield returns false
+type Source[T any] func(yield func(item T) bool) error
+
+// PageFetcher fetches one page of a collection, e.g. a closure over XplorProvider.Contacts and its params
+type PageFetcher[T any] func(pagination *xplorentities.XPlorPagination) ([]T, xplorentities.PageInfo, *xplorentities.ErrorResponse)
+
+// APIError is an API failure while reading a source
+type APIError struct {
+	*xplorentities.ErrorResponse
+	Page int // Page being read
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("xplorexport: page %d: %s (HTTP %d)", e.Page, e.Message, e.Code)
+}
+
+// SkippedError reports the members that could not be decoded; every other member was exported
+type SkippedError struct {
+	Members []util.MemberError
+}
+
+func (e *SkippedError) Error() string {
+	return fmt.Sprintf("xplorexport: %d members could not be decoded and were skipped, first: %v", len(e.Members), e.Members[0])
+}
+
+// Stream reads every page of the collection of T, decoding one member at a time with xplorcore.Stream.
+// query holds the filters, e.g. built with the ToValues method of the typed params.
+// Members that fail to decode are skipped and reported in a *SkippedError once the collection is read.
+func Stream[T xplorentities.Entity](ctx context.Context, xe *xplorcore.XplorProvider, nodeId string, query url.Values, itemsPerPage int) Source[T] {
+	return func(yield func(item T) bool) error {
+		var skipped []util.MemberError
+		for page := 1; page > 0; {
+			stopped := false
+			result, err := xplorcore.Stream(ctx, xe, nodeId, query, pagination(page, itemsPerPage), func(item T) bool {
+				stopped = !yield(item)
+				return !stopped
+			})
+			if err != nil {
+				return &APIError{ErrorResponse: err, Page: page}
+			}
+			if stopped {
+				return nil
+			}
+			skipped = append(skipped, result.Errors...)
+			page = nextPage(page, result.PageInfo())
+		}
+		if len(skipped) > 0 {
+			return &SkippedError{Members: skipped}
+		}
+		return nil
+	}
+}
+
+// Pages reads every page returned by fetch, keeping one page in memory at a time
+func Pages[T any](itemsPerPage int, fetch PageFetcher[T]) Source[T] {
+	return func(yield func(item T) bool) error {
+		for page := 1; page > 0; {
+			items, info, err := fetch(pagination(page, itemsPerPage))
+			if err != nil {
+				return &APIError{ErrorResponse: err, Page: page}
+			}
+			for _, item := range items {
+				if !yield(item) {
+					return nil
+				}
+			}
+			page = nextPage(page, info)
+		}
+		return nil
+	}
+}
+
+// Slice yields items already in memory
+func Slice[T any](items []T) Source[T] {
+	return func(yield func(item T) bool) error {
+		for _, item := range items {
+			if !yield(item) {
+				return nil
+			}
+		}
+		return nil
+	}
+}
+
+func pagination(page, itemsPerPage int) *xplorentities.XPlorPagination {
+	if itemsPerPage <= 0 {
+		itemsPerPage = DefaultItemsPerPage
+	}
+	return &xplorentities.XPlorPagination{Page: page, ItemsPerPage: itemsPerPage}
+}
+
+// nextPage returns the page following page, or 0 after the last one
+func nextPage(page int, info xplorentities.PageInfo) int {
+	if info.NextPage <= page {
+		return 0
+	}
+	return info.NextPage
+}
+
+// WriteCSV writes a header row and one row per item of src, and returns the number of rows written.
+// Rows written before a source error are flushed to w.
+func WriteCSV[T any](w io.Writer, src Source[T], columns []Column[T]) (int, error) {
+	writer := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Header
+	}
+	if err := writer.Write(header); err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	record := make([]string, len(columns))
+	var writeErr error
+	err := src(func(item T) bool {
+		for i, column := range columns {
+			record[i] = column.Value(item)
+		}
+		if writeErr = writer.Write(record); writeErr != nil {
+			return false
+		}
+		rows++
+		return true
+	})
+	writer.Flush()
+	if writeErr != nil {
+		return rows, writeErr
+	}
+	if flushErr := writer.Error(); flushErr != nil {
+		return rows, flushErr
+	}
+	return rows, err
+}
+
+// WriteNDJSON writes each item of src as one JSON line, as the SDK serializes it, and returns the number of lines written.
+// Lines written before a source error are flushed to w.
+func WriteNDJSON[T any](w io.Writer, src Source[T]) (int, error) {
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+	encoder.SetEscapeHTML(false)
+
+	rows := 0
+	var writeErr error
+	err := src(func(item T) bool {
+		if writeErr = encoder.Encode(item); writeErr != nil {
+			return false
+		}
+		rows++
+		return true
+	})
+	if flushErr := buffered.Flush(); writeErr == nil {
+		writeErr = flushErr
+	}
+	if writeErr != nil {
+		return rows, writeErr
+	}
+	return rows, err
+}
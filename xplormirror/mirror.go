@@ -0,0 +1,307 @@
+// Package xplormirror keeps a local SQLite copy of XPlor data for offline SQL queries.
+// It creates a normalized schema (see Migrations) with foreign keys derived from the IRIs,
+// loads whole collections through the SDK and applies incremental changes read by xplorsync.
+// The database is opened by the caller with any SQLite database/sql driver, so the SDK does not depend on one.
+package xplormirror
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/xplorcore"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+	"github.com/angelbarreiros/XPlorGo/xplorexport"
+	"github.com/angelbarreiros/XPlorGo/xplorsync"
+)
+
+const (
+	// DefaultBatchSize is the number of rows written per transaction when none is given
+	DefaultBatchSize = 500
+	// CursorTable keeps the xplorsync cursors of incremental loads
+	CursorTable = "sync_cursors"
+
+	// mirroredAtLayout has a fixed width so that stored values sort as text
+	mirroredAtLayout = "2006-01-02T15:04:05.000000000Z"
+)
+
+// Options tunes a Mirror
+type Options struct {
+	// Location is the time zone of naive API datetimes and of the stored wall-clock readings; nil uses UTC
+	Location *time.Location
+	// ItemsPerPage is the page size; zero uses xplorexport.DefaultItemsPerPage
+	ItemsPerPage int
+	// BatchSize is the number of rows written per transaction by Load; zero uses DefaultBatchSize
+	BatchSize int
+	// Sync tunes incremental loads; its Location and ItemsPerPage default to the ones above.
+	// Set its FullScanInterval for Sync to remove the rows of hard-deleted records.
+	Sync xplorsync.Options
+}
+
+// Mirror writes XPlor entities to a SQLite database
+type Mirror struct {
+	db       *sql.DB
+	provider *xplorcore.XplorProvider
+	syncer   *xplorsync.Syncer
+	options  Options
+}
+
+// Open migrates db to the latest schema and returns a Mirror loading it through provider.
+// Foreign keys are declared but not enforced unless the connection enables them,
+// since partial loads may reference rows that are not mirrored.
+func Open(ctx context.Context, db *sql.DB, provider *xplorcore.XplorProvider, options Options) (*Mirror, error) {
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultBatchSize
+	}
+	if options.Sync.Location == nil {
+		options.Sync.Location = options.Location
+	}
+	if options.Sync.ItemsPerPage == 0 {
+		options.Sync.ItemsPerPage = options.ItemsPerPage
+	}
+	if err := Migrate(ctx, db); err != nil {
+		return nil, err
+	}
+	store := xplorsync.NewSQLStore(db, CursorTable, xplorsync.QuestionMark)
+	if err := store.CreateTable(ctx); err != nil {
+		return nil, fmt.Errorf("xplormirror: create cursor table: %w", err)
+	}
+	return &Mirror{
+		db:       db,
+		provider: provider,
+		syncer:   xplorsync.New(provider, store, options.Sync),
+		options:  options,
+	}, nil
+}
+
+// LoadResult summarizes a full load
+type LoadResult struct {
+	Rows   int // Rows written
+	Pruned int // Rows removed because the collection no longer lists them
+}
+
+// Load reads the whole collection of T on the node and writes every record, replacing the stored version.
+// query holds the filters, e.g. built with the ToValues method of the typed params.
+// With prune, the rows of the table not written by this load are removed once the collection was read entirely;
+// only prune when the query covers everything the table should hold.
+func Load[T xplorentities.Entity](ctx context.Context, m *Mirror, nodeId string, query url.Values, prune bool) (LoadResult, error) {
+	var zero T
+	table, ok := tables[zero.Resource()]
+	if !ok {
+		return LoadResult{}, fmt.Errorf("xplormirror: %s is not mirrored", zero.Resource())
+	}
+
+	var result LoadResult
+	started := time.Now().UTC().Format(mirroredAtLayout)
+	batch := newBatch(m, ctx)
+	var writeErr error
+	readErr := xplorexport.Stream[T](ctx, m.provider, nodeId, query, m.options.ItemsPerPage)(func(item T) bool {
+		rec, ok, err := m.record(item)
+		if err == nil && ok {
+			err = batch.upsert(rec)
+		}
+		if err != nil {
+			writeErr = err
+			return false
+		}
+		if ok {
+			result.Rows++
+		}
+		return true
+	})
+	if writeErr != nil {
+		batch.rollback()
+		return result, writeErr
+	}
+	if err := batch.commit(); err != nil {
+		return result, err
+	}
+	if readErr != nil || !prune {
+		return result, readErr
+	}
+
+	pruned, err := m.prune(ctx, table.name, table.children, started)
+	result.Pruned = pruned
+	return result, err
+}
+
+// Sync applies the changes of feed on the node read since the previous Sync of the resource,
+// starting with a full read (see xplorsync.Run). Deleted records are removed from the mirror as xplorsync
+// reports them: soft deletes when read, hard deletes on the full scans run every Options.Sync.FullScanInterval.
+// With a zero interval only the first Sync scans fully; remove the rest with Load(..., prune=true).
+func Sync[T xplorentities.Entity](ctx context.Context, m *Mirror, nodeId string, feed xplorsync.Feed[T]) (xplorsync.Result, error) {
+	var zero T
+	table, ok := tables[zero.Resource()]
+	if !ok {
+		return xplorsync.Result{}, fmt.Errorf("xplormirror: %s is not mirrored", zero.Resource())
+	}
+	// Each change is committed before the cursor is saved, so an interrupted run never skips one
+	return xplorsync.Run(ctx, m.syncer, nodeId, feed, func(change xplorsync.Change[T]) error {
+		if change.Kind == xplorsync.Deleted {
+			return m.delete(ctx, table.name, table.children, path.Base(change.IRI))
+		}
+		rec, ok, err := m.record(change.Item)
+		if err != nil || !ok {
+			return err
+		}
+		batch := newBatch(m, ctx)
+		if err := batch.upsert(rec); err != nil {
+			batch.rollback()
+			return err
+		}
+		return batch.commit()
+	})
+}
+
+// batch writes rows in transactions of up to BatchSize rows
+type batch struct {
+	mirror *Mirror
+	ctx    context.Context
+	tx     *sql.Tx
+	rows   int
+	stamp  string
+}
+
+func newBatch(m *Mirror, ctx context.Context) *batch {
+	return &batch{mirror: m, ctx: ctx}
+}
+
+// upsert inserts the record or updates its row, and replaces its child rows
+func (b *batch) upsert(rec record) error {
+	if b.tx == nil {
+		tx, err := b.mirror.db.BeginTx(b.ctx, nil)
+		if err != nil {
+			return err
+		}
+		b.tx, b.rows = tx, 0
+		b.stamp = time.Now().UTC().Format(mirroredAtLayout)
+	}
+
+	columns := append(slices.Clip(rec.columns), column{"mirrored_at", b.stamp})
+	if _, err := b.tx.ExecContext(b.ctx, upsertStatement(rec.table, columns), values(columns)...); err != nil {
+		return fmt.Errorf("xplormirror: write %s %v: %w", rec.table, rec.id(), err)
+	}
+	for _, child := range rec.children {
+		if _, err := b.tx.ExecContext(b.ctx, "DELETE FROM "+child.table+" WHERE "+child.parent+" = ?", rec.id()); err != nil {
+			return fmt.Errorf("xplormirror: write %s of %s %v: %w", child.table, rec.table, rec.id(), err)
+		}
+		for _, row := range child.rows {
+			if _, err := b.tx.ExecContext(b.ctx, insertStatement(child.table, row), values(row)...); err != nil {
+				return fmt.Errorf("xplormirror: write %s of %s %v: %w", child.table, rec.table, rec.id(), err)
+			}
+		}
+	}
+
+	b.rows++
+	if b.rows >= b.mirror.options.BatchSize {
+		return b.commit()
+	}
+	return nil
+}
+
+// commit ends the current transaction, if any
+func (b *batch) commit() error {
+	if b.tx == nil {
+		return nil
+	}
+	tx := b.tx
+	b.tx = nil
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("xplormirror: commit: %w", err)
+	}
+	return nil
+}
+
+// rollback drops the rows of the current transaction, if any
+func (b *batch) rollback() {
+	if b.tx != nil {
+		b.tx.Rollback()
+		b.tx = nil
+	}
+}
+
+// upsertStatement inserts a row or, when its first column conflicts, updates the other columns
+func upsertStatement(table string, columns []column) string {
+	names := make([]string, len(columns))
+	updates := make([]string, 0, len(columns)-1)
+	for i, column := range columns {
+		names[i] = column.name
+		if i > 0 {
+			updates = append(updates, column.name+" = excluded."+column.name)
+		}
+	}
+	statement := "INSERT INTO " + table + " (" + strings.Join(names, ", ") + ") VALUES (" +
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ") ON CONFLICT (" + names[0] + ") DO "
+	if len(updates) == 0 {
+		return statement + "NOTHING"
+	}
+	return statement + "UPDATE SET " + strings.Join(updates, ", ")
+}
+
+// insertStatement inserts a child row, replacing the row it conflicts with
+func insertStatement(table string, columns []column) string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.name
+	}
+	return "INSERT OR REPLACE INTO " + table + " (" + strings.Join(names, ", ") + ") VALUES (" +
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+}
+
+func values(columns []column) []any {
+	values := make([]any, len(columns))
+	for i, column := range columns {
+		values[i] = column.value
+	}
+	return values
+}
+
+// delete removes a row and its child rows
+func (m *Mirror) delete(ctx context.Context, table string, children []children, id string) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, child := range children {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+child.table+" WHERE "+child.parent+" = ?", id); err != nil {
+			return fmt.Errorf("xplormirror: delete %s of %s %s: %w", child.table, table, id, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE id = ?", id); err != nil {
+		return fmt.Errorf("xplormirror: delete %s %s: %w", table, id, err)
+	}
+	return tx.Commit()
+}
+
+// prune removes the rows written before started and the child rows left without a parent
+func (m *Mirror) prune(ctx context.Context, table string, children []children, started string) (int, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	deleted, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE mirrored_at < ?", started)
+	if err != nil {
+		return 0, fmt.Errorf("xplormirror: prune %s: %w", table, err)
+	}
+	for _, child := range children {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+child.table+" WHERE "+child.parent+" NOT IN (SELECT id FROM "+table+")"); err != nil {
+			return 0, fmt.Errorf("xplormirror: prune %s: %w", child.table, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("xplormirror: prune %s: %w", table, err)
+	}
+	pruned, err := deleted.RowsAffected()
+	return int(pruned), err
+}
@@ -0,0 +1,265 @@
+package xplormirror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// datetimeLayout is the wall-clock format of stored datetimes, understood by the SQLite date functions
+const datetimeLayout = "2006-01-02 15:04:05"
+
+// column is a value written to a column of a row
+type column struct {
+	name  string
+	value any
+}
+
+// record is the row of an entity and the rows of its child tables, which are replaced with it
+type record struct {
+	table    string
+	columns  []column // The id column first
+	children []children
+}
+
+type children struct {
+	table  string
+	parent string // Column referencing the record
+	rows   [][]column
+}
+
+func (r record) id() any {
+	return r.columns[0].value
+}
+
+// tables lists the tables of the mirrored resources and their child tables
+var tables = map[xplorentities.Resource]struct {
+	name     string
+	children []children
+}{
+	xplorentities.ResourceClubs:         {name: "clubs"},
+	xplorentities.ResourceStudios:       {name: "studios"},
+	xplorentities.ResourceActivities:    {name: "activities"},
+	xplorentities.ResourceCoaches:       {name: "coaches", children: []children{{table: "coach_activities", parent: "coach_id"}}},
+	xplorentities.ResourceContacts:      {name: "contacts"},
+	xplorentities.ResourceSubscriptions: {name: "subscriptions"},
+	xplorentities.ResourceContactTags:   {name: "contact_tags"},
+	xplorentities.ResourceCounterLines:  {name: "counter_lines"},
+	xplorentities.ResourceRecurrences:   {name: "recurrences"},
+	xplorentities.ResourceClasses:       {name: "classes"},
+	xplorentities.ResourceAttendees:     {name: "attendees"},
+	xplorentities.ResourceFamilies:      {name: "families", children: []children{{table: "family_members", parent: "family_id"}}},
+}
+
+// record maps an entity to its rows; ok is false for entities without an ID
+func (m *Mirror) record(item any) (rec record, ok bool, err error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return record{}, false, err
+	}
+
+	switch item := item.(type) {
+	case xplorentities.XPlorClub:
+		rec = record{table: "clubs", columns: []column{
+			{"id", ref(item.ID)}, {"iri", item.ID},
+			{"number", item.Number}, {"code", item.Code}, {"name", item.Name}, {"email", item.Email}, {"phone", item.Phone},
+			{"street_address", item.StreetAddress}, {"postal_code", item.PostalCode}, {"locality", item.AddressLocality},
+			{"country_iso", item.AddressCountryIso}, {"opening_date", m.instant(item.OpeningDate)},
+			{"created_at", m.instant(item.CreatedAt)}, {"deleted_at", m.instant(item.DeletedAt)},
+		}}
+	case xplorentities.XPlorStudio:
+		rec = record{table: "studios", columns: []column{
+			{"id", refPtr(item.ID)}, {"iri", item.ID},
+			{"club_id", refPtr(item.Club)}, {"zone_id", refPtr(item.ZoneId)}, {"name", item.Name},
+			{"capacity", item.Capacity}, {"overbooking", item.Overbooking},
+			{"street_address", item.StreetAddress}, {"postal_code", item.PostalCode}, {"locality", item.AddressLocality},
+			{"created_at", m.datetime(&item.CreatedAt)}, {"archived_at", m.datetime(item.ArchivedAt)},
+		}}
+	case xplorentities.XPlorActivity:
+		rec = record{table: "activities", columns: []column{
+			{"id", refPtr(item.ID)}, {"iri", item.ID},
+			{"club_id", refPtr(item.ClubId)}, {"name", item.Name}, {"color_hex", item.ColorHex},
+			{"bookable", item.IsBookable}, {"viewable", item.IsViewable},
+			{"created_at", m.datetime(item.CreatedAt)}, {"archived_at", m.datetime(item.ArchivedAt)},
+		}}
+	case xplorentities.XPloreCoach:
+		activities := children{table: "coach_activities", parent: "coach_id"}
+		for _, activity := range item.Activities {
+			if id := refPtr(activity); id != nil {
+				activities.rows = append(activities.rows, []column{{"coach_id", refPtr(item.Id)}, {"activity_id", id}})
+			}
+		}
+		rec = record{table: "coaches", children: []children{activities}, columns: []column{
+			{"id", refPtr(item.Id)}, {"iri", item.Id},
+			{"given_name", item.GivenName}, {"family_name", item.FamilyName}, {"alternate_name", item.AlternateName},
+			{"email", item.Email}, {"mobile", item.Mobile},
+			{"created_at", m.datetime(item.CreatedAt)}, {"archived_at", m.datetime(item.ArchivedAt)},
+		}}
+	case xplorentities.XPlorContact:
+		rec = record{table: "contacts", columns: []column{
+			{"id", refPtr(item.ID)}, {"iri", item.ID},
+			{"club_id", refPtr(item.ClubID)}, {"number", item.Number}, {"given_name", item.GivenName}, {"family_name", item.FamilyName},
+			{"email", item.Email}, {"mobile", item.Mobile}, {"gender", item.Gender}, {"birth_date", date(item.BirthDate)}, {"state", item.State},
+			{"created_at", m.datetime(item.CreatedAt)}, {"updated_at", m.datetime(item.UpdatedAt)},
+		}}
+	case xplorentities.XPlorSubscription:
+		rec = record{table: "subscriptions", columns: []column{
+			{"id", refPtr(item.Id)}, {"iri", item.Id},
+			{"contact_id", refPtr(item.Contact.Id)}, {"club_id", ref(item.ClubId)}, {"article_id", ref(item.ArticleId)},
+			{"name", item.Name}, {"tag_name", item.TagName},
+			{"valid_from", m.datetimeString(item.ValidFrom)}, {"valid_through", m.datetimeString(item.ValidThrough)},
+			{"engaged_through", m.datetimeString(item.EngagedThrough)}, {"terminated_at", m.datetimeString(item.TerminatedAt)},
+			{"auto_renewal", item.AutoRenewal},
+			{"created_at", m.datetimeString(item.CreatedAt)}, {"updated_at", m.datetimeString(item.UpdatedAt)},
+		}}
+	case xplorentities.XPlorContactTag:
+		rec = record{table: "contact_tags", columns: []column{
+			{"id", refPtr(item.ID)}, {"iri", item.ID},
+			{"contact_id", refPtr(item.Contact)}, {"subscription_id", refPtr(item.Subscription)}, {"name", item.Name},
+			{"valid_from", m.datetime(&item.ValidFrom)}, {"valid_through", m.datetime(item.ValidThrough)},
+			{"created_at", m.datetime(item.CreatedAt)}, {"deleted_at", m.datetime(item.DeletedAt)},
+		}}
+	case xplorentities.XPlorCounterLine:
+		rec = record{table: "counter_lines", columns: []column{
+			{"id", counterLineID(item)},
+			{"contact_id", refPtr(item.ContactID)}, {"article_id", refPtr(item.ArticleID)}, {"unit", item.Unit},
+			{"total_unities", item.TotalUnities}, {"remaining_unities", item.RemainingUnities},
+			{"valid_from", m.datetime(item.ValidFrom)}, {"valid_through", m.datetime(item.ValidThrough)},
+			{"created_at", m.datetime(item.CreatedAt)}, {"updated_at", m.datetime(&item.UpdatedAt)}, {"deleted_at", m.datetime(item.DeletedAt)},
+		}}
+	case xplorentities.XPlorRecurrence:
+		classType := item.ClassEventType
+		rec = record{table: "recurrences", columns: []column{
+			{"id", refPtr(item.ID)}, {"iri", item.ID},
+			{"club_id", refPtr(classType.Club)}, {"studio_id", refPtr(classType.Studio)},
+			{"activity_id", refPtr(classType.Activity)}, {"coach_id", refPtr(classType.Coach)},
+			{"frequency", item.Frequency}, {"day", item.Day},
+			{"started_at", m.datetime(&item.StartedAt)}, {"ended_at", m.datetime(&item.EndedAt)}, {"deleted_at", m.datetime(item.DeletedAt)},
+		}}
+	case xplorentities.XPlorClass:
+		rec = record{table: "classes", columns: []column{
+			{"id", refPtr(item.ID)}, {"iri", item.ID},
+			{"club_id", refPtr(item.Club)}, {"studio_id", refPtr(item.Studio)}, {"activity_id", refPtr(item.Activity)},
+			{"coach_id", refPtr(item.Coach)}, {"recurrence_id", refPtr(item.Recurrence)}, {"summary", item.Summary},
+			{"started_at", m.datetime(&item.StartedAt)}, {"ended_at", m.datetime(&item.EndedAt)},
+			{"attending_limit", item.AttendingLimit}, {"queue_limit", item.QueueLimit},
+			{"attendee_remaining", item.AttendeeRemaining}, {"queue_remaining", item.QueueRemaining},
+			{"created_at", m.datetime(item.CreatedAt)}, {"updated_at", m.datetime(item.UpdatedAt)}, {"deleted_at", m.datetime(item.DeletedAt)},
+		}}
+	case xplorentities.XPlorAttendee:
+		var class *string
+		if item.ClassEvent != nil {
+			class = item.ClassEvent.AtID
+		}
+		rec = record{table: "attendees", columns: []column{
+			{"id", refPtr(item.AtID)}, {"iri", item.AtID},
+			{"class_id", refPtr(class)}, {"contact_id", refPtr(item.ContactId)}, {"state", item.State},
+			{"showed", item.Showed}, {"cancel_delay_over", item.CancelDelayOver},
+			{"created_at", m.datetimeText(item.CreatedAt)}, {"queued_at", m.datetimeText(item.QueuedAt)},
+			{"validated_at", m.datetimeText(item.ValidatedAt)}, {"canceled_at", m.datetimeText(item.CanceledAt)},
+			{"deleted_at", m.datetimeText(item.DeletedAt)},
+		}}
+	case xplorentities.XPlorFamily:
+		members := children{table: "family_members", parent: "family_id"}
+		for _, member := range item.Members {
+			if id := refPtr(member.ID); id != nil {
+				members.rows = append(members.rows, []column{
+					{"id", id}, {"family_id", refPtr(item.ID)}, {"contact_id", refPtr(member.Contact.ID)},
+					{"role", member.Role}, {"responsible", member.Responsible}, {"created_at", date(&member.CreatedAt)},
+				})
+			}
+		}
+		rec = record{table: "families", children: []children{members}, columns: []column{
+			{"id", refPtr(item.ID)}, {"iri", item.ID}, {"name", item.Name}, {"created_at", date(&item.CreatedAt)},
+		}}
+	default:
+		return record{}, false, fmt.Errorf("xplormirror: %T is not mirrored", item)
+	}
+
+	if rec.id() == nil {
+		return record{}, false, nil
+	}
+	rec.columns = append(rec.columns, column{"data", string(data)})
+	return rec, true, nil
+}
+
+// ref returns the ID at the end of an IRI, nil when it is empty
+func ref(iri string) any {
+	iri = strings.TrimSpace(iri)
+	if iri == "" {
+		return nil
+	}
+	return xplorentities.ShortID(iri)
+}
+
+func refPtr(iri *string) any {
+	if iri == nil {
+		return nil
+	}
+	return ref(*iri)
+}
+
+// counterLineID derives a stable key for counter lines, which have no @id, from their contact, article and creation
+func counterLineID(line xplorentities.XPlorCounterLine) any {
+	var created string
+	if line.CreatedAt != nil {
+		created = line.CreatedAt.Format(time.RFC3339Nano)
+	}
+	if line.ContactID == nil || created == "" {
+		return nil
+	}
+	var article string
+	if line.ArticleID != nil {
+		article = *line.ArticleID
+	}
+	sum := sha256.Sum256([]byte(*line.ContactID + "\x00" + article + "\x00" + created))
+	return hex.EncodeToString(sum[:10])
+}
+
+// datetime returns the wall-clock reading of an API datetime in the mirror location, nil when empty
+func (m *Mirror) datetime(value *util.LocalTime) any {
+	if value == nil || value.IsZero() {
+		return nil
+	}
+	return value.InLocation(m.options.Location).Format(datetimeLayout)
+}
+
+// datetimeString parses a datetime sent as a plain string; invalid values are stored as sent
+func (m *Mirror) datetimeString(value string) any {
+	if value == "" {
+		return nil
+	}
+	parsed := util.ParseLocalTime(value)
+	if parsed == nil {
+		return value
+	}
+	return m.datetime(parsed)
+}
+
+func (m *Mirror) datetimeText(value *string) any {
+	if value == nil {
+		return nil
+	}
+	return m.datetimeString(*value)
+}
+
+// instant returns the wall-clock reading of an exact instant in the mirror location
+func (m *Mirror) instant(value *xplorentities.XTime) any {
+	if value == nil || value.IsZero() {
+		return nil
+	}
+	return value.In(m.options.Location).Format(datetimeLayout)
+}
+
+func date(value *util.LocalDate) any {
+	if value == nil || value.IsZero() {
+		return nil
+	}
+	return value.Format(time.DateOnly)
+}
@@ -0,0 +1,286 @@
+package xplormirror
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Migration is a versioned change of the mirror schema, applied once in a transaction
+type Migration struct {
+	Version    int
+	Name       string
+	Statements []string
+}
+
+// Migrations lists the schema changes in order. Every table keeps the record as the SDK serializes it
+// in its data column, so when an entity gains a field, a new migration adds the column and fills it
+// from the stored records without reloading them, e.g.
+//
+//	ALTER TABLE contacts ADD COLUMN channel TEXT;
+//	UPDATE contacts SET channel = json_extract(data, '$.channel');
+//
+// Applied migrations must never be edited.
+var Migrations = []Migration{
+	{Version: 1, Name: "initial schema", Statements: []string{
+		`CREATE TABLE clubs (
+	id TEXT PRIMARY KEY,
+	iri TEXT NOT NULL,
+	number TEXT,
+	code TEXT,
+	name TEXT,
+	email TEXT,
+	phone TEXT,
+	street_address TEXT,
+	postal_code TEXT,
+	locality TEXT,
+	country_iso TEXT,
+	opening_date TEXT,
+	created_at TEXT,
+	deleted_at TEXT,
+	data TEXT NOT NULL,
+	mirrored_at TEXT NOT NULL
+)`,
+		`CREATE TABLE studios (
+	id TEXT PRIMARY KEY,
+	iri TEXT NOT NULL,
+	club_id TEXT REFERENCES clubs (id),
+	zone_id TEXT,
+	name TEXT,
+	capacity INTEGER,
+	overbooking INTEGER,
+	street_address TEXT,
+	postal_code TEXT,
+	locality TEXT,
+	created_at TEXT,
+	archived_at TEXT,
+	data TEXT NOT NULL,
+	mirrored_at TEXT NOT NULL
+)`,
+		`CREATE TABLE activities (
+	id TEXT PRIMARY KEY,
+	iri TEXT NOT NULL,
+	club_id TEXT REFERENCES clubs (id),
+	name TEXT,
+	color_hex TEXT,
+	bookable INTEGER,
+	viewable INTEGER,
+	created_at TEXT,
+	archived_at TEXT,
+	data TEXT NOT NULL,
+	mirrored_at TEXT NOT NULL
+)`,
+		`CREATE TABLE coaches (
+	id TEXT PRIMARY KEY,
+	iri TEXT NOT NULL,
+	given_name TEXT,
+	family_name TEXT,
+	alternate_name TEXT,
+	email TEXT,
+	mobile TEXT,
+	created_at TEXT,
+	archived_at TEXT,
+	data TEXT NOT NULL,
+	mirrored_at TEXT NOT NULL
+)`,
+		`CREATE TABLE coach_activities (
+	coach_id TEXT NOT NULL REFERENCES coaches (id) ON DELETE CASCADE,
+	activity_id TEXT NOT NULL REFERENCES activities (id),
+	PRIMARY KEY (coach_id, activity_id)
+)`,
+		`CREATE TABLE contacts (
+	id TEXT PRIMARY KEY,
+	iri TEXT NOT NULL,
+	club_id TEXT REFERENCES clubs (id),
+	number TEXT,
+	given_name TEXT,
+	family_name TEXT,
+	email TEXT,
+	mobile TEXT,
+	gender TEXT,
+	birth_date TEXT,
+	state TEXT,
+	created_at TEXT,
+	updated_at TEXT,
+	data TEXT NOT NULL,
+	mirrored_at TEXT NOT NULL
+)`,
+		`CREATE TABLE subscriptions (
+	id TEXT PRIMARY KEY,
+	iri TEXT NOT NULL,
+	contact_id TEXT REFERENCES contacts (id),
+	club_id TEXT REFERENCES clubs (id),
+	article_id TEXT,
+	name TEXT,
+	tag_name TEXT,
+	valid_from TEXT,
+	valid_through TEXT,
+	engaged_through TEXT,
+	terminated_at TEXT,
+	auto_renewal INTEGER,
+	created_at TEXT,
+	updated_at TEXT,
+	data TEXT NOT NULL,
+	mirrored_at TEXT NOT NULL
+)`,
+		`CREATE TABLE contact_tags (
+	id TEXT PRIMARY KEY,
+	iri TEXT NOT NULL,
+	contact_id TEXT REFERENCES contacts (id),
+	subscription_id TEXT REFERENCES subscriptions (id),
+	name TEXT,
+	valid_from TEXT,
+	valid_through TEXT,
+	created_at TEXT,
+	deleted_at TEXT,
+	data TEXT NOT NULL,
+	mirrored_at TEXT NOT NULL
+)`,
+		`CREATE TABLE counter_lines (
+	id TEXT PRIMARY KEY,
+	contact_id TEXT REFERENCES contacts (id),
+	article_id TEXT,
+	unit TEXT,
+	total_unities INTEGER,
+	remaining_unities INTEGER,
+	valid_from TEXT,
+	valid_through TEXT,
+	created_at TEXT,
+	updated_at TEXT,
+	deleted_at TEXT,
+	data TEXT NOT NULL,
+	mirrored_at TEXT NOT NULL
+)`,
+		`CREATE TABLE recurrences (
+	id TEXT PRIMARY KEY,
+	iri TEXT NOT NULL,
+	club_id TEXT REFERENCES clubs (id),
+	studio_id TEXT REFERENCES studios (id),
+	activity_id TEXT REFERENCES activities (id),
+	coach_id TEXT REFERENCES coaches (id),
+	frequency TEXT,
+	day TEXT,
+	started_at TEXT,
+	ended_at TEXT,
+	deleted_at TEXT,
+	data TEXT NOT NULL,
+	mirrored_at TEXT NOT NULL
+)`,
+		`CREATE TABLE classes (
+	id TEXT PRIMARY KEY,
+	iri TEXT NOT NULL,
+	club_id TEXT REFERENCES clubs (id),
+	studio_id TEXT REFERENCES studios (id),
+	activity_id TEXT REFERENCES activities (id),
+	coach_id TEXT REFERENCES coaches (id),
+	recurrence_id TEXT REFERENCES recurrences (id),
+	summary TEXT,
+	started_at TEXT,
+	ended_at TEXT,
+	attending_limit INTEGER,
+	queue_limit INTEGER,
+	attendee_remaining INTEGER,
+	queue_remaining INTEGER,
+	created_at TEXT,
+	updated_at TEXT,
+	deleted_at TEXT,
+	data TEXT NOT NULL,
+	mirrored_at TEXT NOT NULL
+)`,
+		`CREATE TABLE attendees (
+	id TEXT PRIMARY KEY,
+	iri TEXT NOT NULL,
+	class_id TEXT REFERENCES classes (id),
+	contact_id TEXT REFERENCES contacts (id),
+	state TEXT,
+	showed INTEGER,
+	cancel_delay_over INTEGER,
+	created_at TEXT,
+	queued_at TEXT,
+	validated_at TEXT,
+	canceled_at TEXT,
+	deleted_at TEXT,
+	data TEXT NOT NULL,
+	mirrored_at TEXT NOT NULL
+)`,
+		`CREATE TABLE families (
+	id TEXT PRIMARY KEY,
+	iri TEXT NOT NULL,
+	name TEXT,
+	created_at TEXT,
+	data TEXT NOT NULL,
+	mirrored_at TEXT NOT NULL
+)`,
+		`CREATE TABLE family_members (
+	id TEXT PRIMARY KEY,
+	family_id TEXT NOT NULL REFERENCES families (id) ON DELETE CASCADE,
+	contact_id TEXT REFERENCES contacts (id),
+	role TEXT,
+	responsible INTEGER,
+	created_at TEXT
+)`,
+		`CREATE INDEX studios_club ON studios (club_id)`,
+		`CREATE INDEX contacts_club ON contacts (club_id)`,
+		`CREATE INDEX subscriptions_contact ON subscriptions (contact_id)`,
+		`CREATE INDEX contact_tags_contact ON contact_tags (contact_id)`,
+		`CREATE INDEX counter_lines_contact ON counter_lines (contact_id)`,
+		`CREATE INDEX classes_started ON classes (started_at)`,
+		`CREATE INDEX attendees_class ON attendees (class_id)`,
+		`CREATE INDEX attendees_contact ON attendees (contact_id)`,
+		`CREATE INDEX family_members_contact ON family_members (contact_id)`,
+	}},
+}
+
+// SchemaVersion returns the version of the latest migration applied to db, 0 for an empty database
+func SchemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	applied_at TEXT NOT NULL
+)`); err != nil {
+		return 0, err
+	}
+	var version int
+	err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	return version, err
+}
+
+// Migrate applies the migrations newer than the schema of db.
+// It fails when db was migrated by a newer version of the package.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	current, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return fmt.Errorf("xplormirror: read schema version: %w", err)
+	}
+	if latest := Migrations[len(Migrations)-1].Version; current > latest {
+		return fmt.Errorf("xplormirror: schema version %d is newer than this package supports (%d)", current, latest)
+	}
+	for _, migration := range Migrations {
+		if migration.Version <= current {
+			continue
+		}
+		if err := apply(ctx, db, migration); err != nil {
+			return fmt.Errorf("xplormirror: migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+func apply(ctx context.Context, db *sql.DB, migration Migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, statement := range migration.Statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+		migration.Version, migration.Name, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,10 @@
+module github.com/angelbarreiros/XPlorGo/xplorotel
+
+go 1.24.3
+
+require (
+	github.com/angelbarreiros/XPlorGo v0.0.0-20261019155227-d1ee08e22e60
+	go.opentelemetry.io/otel v1.35.0
+	go.opentelemetry.io/otel/metric v1.35.0
+	go.opentelemetry.io/otel/trace v1.35.0
+)
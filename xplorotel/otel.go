@@ -0,0 +1,130 @@
+// Package xplorotel reports XPlorGo API calls to OpenTelemetry.
+// It lives in its own module so that the SDK itself has no OpenTelemetry dependency.
+//
+//	telemetry, err := xplorotel.New(otel.GetTracerProvider(), otel.GetMeterProvider())
+//	config.Telemetry = telemetry
+package xplorotel
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/xplorcore"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies the tracer and meter of the SDK
+const InstrumentationName = "github.com/angelbarreiros/XPlorGo"
+
+// Telemetry implements xplorcore.Telemetry with an OpenTelemetry tracer and meter
+type Telemetry struct {
+	tracer         trace.Tracer
+	duration       metric.Float64Histogram
+	requests       metric.Int64Counter
+	errors         metric.Int64Counter
+	tokenRefreshes metric.Int64Counter
+	cacheLookups   metric.Int64Counter
+}
+
+var _ xplorcore.Telemetry = (*Telemetry)(nil)
+
+// New creates the instruments on the given providers
+func New(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*Telemetry, error) {
+	meter := meterProvider.Meter(InstrumentationName)
+	t := &Telemetry{tracer: tracerProvider.Tracer(InstrumentationName)}
+	var err error
+	if t.duration, err = meter.Float64Histogram("xplor.client.request.duration",
+		metric.WithDescription("Duration of XPlor API requests"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if t.requests, err = meter.Int64Counter("xplor.client.requests",
+		metric.WithDescription("XPlor API requests sent")); err != nil {
+		return nil, err
+	}
+	if t.errors, err = meter.Int64Counter("xplor.client.errors",
+		metric.WithDescription("XPlor API requests that failed, by status code (0 when no response was received)")); err != nil {
+		return nil, err
+	}
+	if t.tokenRefreshes, err = meter.Int64Counter("xplor.client.token_refreshes",
+		metric.WithDescription("Access token requests")); err != nil {
+		return nil, err
+	}
+	if t.cacheLookups, err = meter.Int64Counter("xplor.client.cache.lookups",
+		metric.WithDescription("Response cache lookups, by hit")); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// StartSpan starts a client span as a child of the span in ctx
+func (t *Telemetry) StartSpan(ctx context.Context, name string, attrs ...xplorcore.Attribute) (context.Context, xplorcore.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(convert(attrs)...))
+	return ctx, otelSpan{span}
+}
+
+// RecordRequest records the duration and outcome of an HTTP exchange
+func (t *Telemetry) RecordRequest(ctx context.Context, resource xplorentities.Resource, method string, status int, latency time.Duration) {
+	attrs := metric.WithAttributes(
+		attribute.String(xplorcore.AttrResource, string(resource)),
+		attribute.String(xplorcore.AttrMethod, method),
+		attribute.Int(xplorcore.AttrStatusCode, status),
+	)
+	t.duration.Record(ctx, latency.Seconds(), attrs)
+	t.requests.Add(ctx, 1, attrs)
+	if status == 0 || status >= http.StatusBadRequest {
+		t.errors.Add(ctx, 1, attrs)
+	}
+}
+
+// RecordTokenRefresh counts access token requests by outcome
+func (t *Telemetry) RecordTokenRefresh(ctx context.Context, success bool) {
+	t.tokenRefreshes.Add(ctx, 1, metric.WithAttributes(attribute.Bool("xplor.success", success)))
+}
+
+// RecordCacheLookup counts response cache hits and misses
+func (t *Telemetry) RecordCacheLookup(ctx context.Context, resource xplorentities.Resource, hit bool) {
+	t.cacheLookups.Add(ctx, 1, metric.WithAttributes(
+		attribute.String(xplorcore.AttrResource, string(resource)),
+		attribute.Bool(xplorcore.AttrCacheHit, hit),
+	))
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) SetAttributes(attrs ...xplorcore.Attribute) {
+	s.span.SetAttributes(convert(attrs)...)
+}
+
+func (s otelSpan) SetError(message string) {
+	s.span.SetStatus(codes.Error, message)
+}
+
+func (s otelSpan) End() {
+	s.span.End()
+}
+
+func convert(attrs []xplorcore.Attribute) []attribute.KeyValue {
+	converted := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			converted = append(converted, attribute.String(a.Key, v))
+		case int:
+			converted = append(converted, attribute.Int(a.Key, v))
+		case int64:
+			converted = append(converted, attribute.Int64(a.Key, v))
+		case bool:
+			converted = append(converted, attribute.Bool(a.Key, v))
+		case float64:
+			converted = append(converted, attribute.Float64(a.Key, v))
+		}
+	}
+	return converted
+}
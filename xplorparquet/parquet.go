@@ -0,0 +1,198 @@
+// Package xplorparquet writes XPlor entities to Parquet files for analytics warehouses.
+// Each entity is flattened into a row type with an explicit schema (see rows.go): typed timestamps,
+// dates and decimal prices. Rows are read from an xplorexport.Source, one page at a time,
+// into a single file or into Hive-style partitions by club and date.
+// It lives in its own module so the SDK does not depend on a Parquet library.
+package xplorparquet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/xplorexport"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+const (
+	// DefaultRowGroupSize is the number of rows per row group used when none is given
+	DefaultRowGroupSize = 100_000
+	// DefaultPartition names the partition of rows without a club or a date, as Hive does
+	DefaultPartition = "__HIVE_DEFAULT_PARTITION__"
+
+	batchSize = 1024 // Rows handed to the Parquet writer at once
+)
+
+// Granularity is the date resolution of partitions
+type Granularity int
+
+const (
+	Daily   Granularity = iota // date=2025-06-02
+	Monthly                    // date=2025-06
+	Yearly                     // date=2025
+)
+
+// Options tunes the Parquet output
+type Options struct {
+	// RowGroupSize caps the rows of each row group; zero uses DefaultRowGroupSize
+	RowGroupSize int64
+	// Compression is the page codec; nil uses Snappy
+	Compression compress.Codec
+	// Granularity is the date resolution of partitions written by WritePartitioned
+	Granularity Granularity
+}
+
+func (o Options) writerOptions() []parquet.WriterOption {
+	rowGroupSize := o.RowGroupSize
+	if rowGroupSize <= 0 {
+		rowGroupSize = DefaultRowGroupSize
+	}
+	var codec compress.Codec = &parquet.Snappy
+	if o.Compression != nil {
+		codec = o.Compression
+	}
+	return []parquet.WriterOption{parquet.MaxRowsPerRowGroup(rowGroupSize), parquet.Compression(codec)}
+}
+
+// Row is a flattened entity filed under a partition
+type Row interface {
+	Partition() Partition
+}
+
+// Partition identifies the club and UTC date a row is filed under; empty values go to DefaultPartition
+type Partition struct {
+	ClubID string
+	Date   time.Time
+}
+
+// Path returns the Hive-style directory of the partition, e.g. club_id=1249/date=2025-06-02
+func (p Partition) Path(granularity Granularity) string {
+	club := DefaultPartition
+	if p.ClubID != "" {
+		club = url.PathEscape(p.ClubID)
+	}
+	date := DefaultPartition
+	if !p.Date.IsZero() {
+		switch granularity {
+		case Monthly:
+			date = p.Date.Format("2006-01")
+		case Yearly:
+			date = p.Date.Format("2006")
+		default:
+			date = p.Date.Format(time.DateOnly)
+		}
+	}
+	return filepath.Join("club_id="+club, "date="+date)
+}
+
+// Write converts every item of src and writes the rows to w as one Parquet file.
+// The file is completed even when src fails, so the rows read before the error are kept.
+func Write[T, R any](w io.Writer, src xplorexport.Source[T], convert func(item T) R, options Options) (int, error) {
+	file := newBatchWriter[R](w, options)
+	var writeErr error
+	err := src(func(item T) bool {
+		writeErr = file.add(convert(item))
+		return writeErr == nil
+	})
+	if joined := errors.Join(writeErr, file.close()); joined != nil {
+		return file.rows, joined
+	}
+	return file.rows, err
+}
+
+// WritePartitioned writes the rows of src under dir, one file per partition:
+// dir/club_id=1249/date=2025-06-02/part-00000.parquet. Existing files are replaced.
+// Each open partition buffers up to one row group; use a coarser Granularity for wide date ranges.
+// It returns the rows written per file, relative to dir.
+func WritePartitioned[T any, R Row](dir string, src xplorexport.Source[T], convert func(item T) R, options Options) (map[string]int, error) {
+	partitions := make(map[string]*partitionFile[R])
+	var writeErr error
+	err := src(func(item T) bool {
+		row := convert(item)
+		name := filepath.Join(row.Partition().Path(options.Granularity), "part-00000.parquet")
+		partition, ok := partitions[name]
+		if !ok {
+			if partition, writeErr = createPartition[R](filepath.Join(dir, name), options); writeErr != nil {
+				return false
+			}
+			partitions[name] = partition
+		}
+		writeErr = partition.add(row)
+		return writeErr == nil
+	})
+
+	written := make(map[string]int, len(partitions))
+	errs := []error{writeErr}
+	for name, partition := range partitions {
+		errs = append(errs, partition.close(), partition.file.Close())
+		written[name] = partition.rows
+	}
+	if joined := errors.Join(errs...); joined != nil {
+		return written, joined
+	}
+	return written, err
+}
+
+type partitionFile[R any] struct {
+	*batchWriter[R]
+	file *os.File
+}
+
+func createPartition[R any](name string, options Options) (*partitionFile[R], error) {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return &partitionFile[R]{batchWriter: newBatchWriter[R](file, options), file: file}, nil
+}
+
+// batchWriter hands rows to the Parquet writer in batches
+type batchWriter[R any] struct {
+	writer *parquet.GenericWriter[R]
+	batch  []R
+	rows   int
+}
+
+func newBatchWriter[R any](w io.Writer, options Options) *batchWriter[R] {
+	return &batchWriter[R]{writer: parquet.NewGenericWriter[R](w, options.writerOptions()...), batch: make([]R, 0, batchSize)}
+}
+
+func (b *batchWriter[R]) add(row R) error {
+	b.batch = append(b.batch, row)
+	if len(b.batch) < batchSize {
+		return nil
+	}
+	return b.flush()
+}
+
+func (b *batchWriter[R]) flush() error {
+	if len(b.batch) == 0 {
+		return nil
+	}
+	n, err := b.writer.Write(b.batch)
+	b.rows += n
+	b.batch = b.batch[:0]
+	if err != nil {
+		return fmt.Errorf("xplorparquet: %w", err)
+	}
+	return nil
+}
+
+func (b *batchWriter[R]) close() error {
+	if err := b.flush(); err != nil {
+		b.writer.Close()
+		return err
+	}
+	if err := b.writer.Close(); err != nil {
+		return fmt.Errorf("xplorparquet: %w", err)
+	}
+	return nil
+}
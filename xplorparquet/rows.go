@@ -0,0 +1,445 @@
+package xplorparquet
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+// Converter flattens entities into rows. IRIs become their trailing ID, datetimes become UTC timestamps
+// and prices become decimals with two digits. Optional columns are null when the value is missing or zero.
+// Article prices are sent in currency units, subscription renewal amounts in cents.
+type Converter struct {
+	// Location reads naive API datetimes, usually the club's time zone; nil uses UTC
+	Location *time.Location
+}
+
+// ContactRow is the Parquet schema of contacts, partitioned by club and creation date
+type ContactRow struct {
+	ID                      string    `parquet:"id"`
+	Number                  string    `parquet:"number"`
+	GivenName               string    `parquet:"given_name"`
+	FamilyName              string    `parquet:"family_name"`
+	Email                   string    `parquet:"email"`
+	Mobile                  *string   `parquet:"mobile,optional"`
+	Gender                  string    `parquet:"gender"`
+	BirthDate               int32     `parquet:"birth_date,optional,date"`
+	ClubID                  string    `parquet:"club_id"`
+	State                   string    `parquet:"state"`
+	ProspectingState        *string   `parquet:"prospecting_state,optional"`
+	Channel                 string    `parquet:"channel"`
+	StreetAddress           string    `parquet:"street_address"`
+	PostalCode              string    `parquet:"postal_code"`
+	Locality                string    `parquet:"locality"`
+	CountryISO              string    `parquet:"country_iso"`
+	IdentificationValidated bool      `parquet:"identification_validated"`
+	CreatedAt               time.Time `parquet:"created_at,optional,timestamp(millisecond)"`
+	UpdatedAt               time.Time `parquet:"updated_at,optional,timestamp(millisecond)"`
+}
+
+func (r ContactRow) Partition() Partition {
+	return Partition{ClubID: r.ClubID, Date: day(r.CreatedAt)}
+}
+
+// Contact flattens a contact and its address
+func (c Converter) Contact(contact xplorentities.XPlorContact) ContactRow {
+	return ContactRow{
+		ID:                      xplorentities.ShortID(util.Deref(contact.ID)),
+		Number:                  contact.Number,
+		GivenName:               contact.GivenName,
+		FamilyName:              contact.FamilyName,
+		Email:                   contact.Email,
+		Mobile:                  contact.Mobile,
+		Gender:                  contact.Gender,
+		BirthDate:               localDate(contact.BirthDate),
+		ClubID:                  xplorentities.ShortID(util.Deref(contact.ClubID)),
+		State:                   contact.State,
+		ProspectingState:        contact.ProspectingState,
+		Channel:                 contact.Channel,
+		StreetAddress:           contact.Address.StreetAddress,
+		PostalCode:              contact.Address.PostalCode,
+		Locality:                contact.Address.Locality,
+		CountryISO:              contact.Address.CountryIso,
+		IdentificationValidated: contact.IdentificationValidated,
+		CreatedAt:               c.timestamp(contact.CreatedAt),
+		UpdatedAt:               c.timestamp(contact.UpdatedAt),
+	}
+}
+
+// SubscriptionRow is the Parquet schema of subscriptions with their renewal and payment summaries,
+// partitioned by club and start date
+type SubscriptionRow struct {
+	ID                      string    `parquet:"id"`
+	Name                    string    `parquet:"name"`
+	TagName                 string    `parquet:"tag_name"`
+	ContactID               string    `parquet:"contact_id"`
+	ContactNumber           string    `parquet:"contact_number"`
+	ContactGivenName        string    `parquet:"contact_given_name"`
+	ContactFamilyName       string    `parquet:"contact_family_name"`
+	ClubID                  string    `parquet:"club_id"`
+	ArticleID               string    `parquet:"article_id"`
+	ValidFrom               int32     `parquet:"valid_from,optional,date"`
+	ValidThrough            int32     `parquet:"valid_through,optional,date"`
+	EngagedThrough          int32     `parquet:"engaged_through,optional,date"`
+	TerminatedAt            int32     `parquet:"terminated_at,optional,date"`
+	Unlimited               bool      `parquet:"unlimited"`
+	Consumed                bool      `parquet:"consumed"`
+	FixedPeriod             bool      `parquet:"fixed_period"`
+	SuspensionQuota         int32     `parquet:"suspension_quota"`
+	OfferName               string    `parquet:"offer_name"`
+	ProductName             string    `parquet:"product_name"`
+	ProductionCode          string    `parquet:"production_code"`
+	BillingRhythm           string    `parquet:"billing_rhythm"`
+	PaymentCount            int32     `parquet:"payment_count"`
+	RegularDebitDay         int32     `parquet:"regular_debit_day"`
+	AutoRenewal             bool      `parquet:"auto_renewal"`
+	RenewalType             string    `parquet:"renewal_type"`
+	NextRenewalDate         int32     `parquet:"next_renewal_date,optional,date"`
+	EngagementRenewalPeriod string    `parquet:"engagement_renewal_period"`
+	EngagementRenewalMonths int32     `parquet:"engagement_renewal_months_before_end"`
+	RenewalCount            int32     `parquet:"renewal_count"`
+	RenewalActivatedAt      int32     `parquet:"renewal_activated_at,optional,date"`
+	RenewalPeriod           string    `parquet:"renewal_period"`
+	RenewalProductName      string    `parquet:"renewal_product_name"`
+	RenewalPriceTI          int64     `parquet:"renewal_price_ti,decimal(2:18)"`
+	RenewalPriceTE          int64     `parquet:"renewal_price_te,decimal(2:18)"`
+	RenewalCurrency         string    `parquet:"renewal_currency"`
+	CreatedAt               time.Time `parquet:"created_at,optional,timestamp(millisecond)"`
+	UpdatedAt               time.Time `parquet:"updated_at,optional,timestamp(millisecond)"`
+}
+
+func (r SubscriptionRow) Partition() Partition {
+	return Partition{ClubID: r.ClubID, Date: epochDay(r.ValidFrom)}
+}
+
+// Subscription flattens a subscription; the renewal columns describe its latest renewal
+func (c Converter) Subscription(subscription xplorentities.XPlorSubscription) SubscriptionRow {
+	row := SubscriptionRow{
+		ID:                      xplorentities.ShortID(util.Deref(subscription.Id)),
+		Name:                    subscription.Name,
+		TagName:                 subscription.TagName,
+		ContactID:               xplorentities.ShortID(util.Deref(subscription.Contact.Id)),
+		ContactNumber:           subscription.Contact.Number,
+		ContactGivenName:        subscription.Contact.GivenName,
+		ContactFamilyName:       subscription.Contact.FamilyName,
+		ClubID:                  xplorentities.ShortID(subscription.ClubId),
+		ArticleID:               xplorentities.ShortID(subscription.ArticleId),
+		ValidFrom:               dateString(subscription.ValidFrom),
+		ValidThrough:            dateString(subscription.ValidThrough),
+		EngagedThrough:          dateString(subscription.EngagedThrough),
+		TerminatedAt:            dateString(subscription.TerminatedAt),
+		Unlimited:               subscription.Unlimited,
+		Consumed:                subscription.Consumed,
+		FixedPeriod:             subscription.FixedPeriod,
+		SuspensionQuota:         int32(subscription.SuspensionQuota),
+		OfferName:               subscription.InitialInfo.OfferName,
+		ProductName:             subscription.InitialInfo.ProductName,
+		ProductionCode:          subscription.InitialInfo.ProductionCode,
+		BillingRhythm:           subscription.InitialInfo.BillingRhythm,
+		PaymentCount:            int32(len(subscription.InitialInfo.Payments)),
+		RegularDebitDay:         int32(subscription.RegularDebitDay),
+		AutoRenewal:             subscription.AutoRenewal,
+		RenewalType:             subscription.RenewalType,
+		NextRenewalDate:         dateString(subscription.NextRenewalDate),
+		EngagementRenewalPeriod: subscription.EngagementRenewal.Period,
+		EngagementRenewalMonths: int32(subscription.EngagementRenewal.MonthBeforeEnd),
+		RenewalCount:            int32(len(subscription.RenewalInfo)),
+		CreatedAt:               c.timestampString(subscription.CreatedAt),
+		UpdatedAt:               c.timestampString(subscription.UpdatedAt),
+	}
+	if len(subscription.RenewalInfo) > 0 {
+		latest := slices.MaxFunc(subscription.RenewalInfo, func(a, b xplorentities.RenewalInfo) int {
+			return a.ActivatedAt.Compare(b.ActivatedAt.Time)
+		})
+		row.RenewalActivatedAt = localDate(&latest.ActivatedAt)
+		row.RenewalPeriod = latest.RenewalPeriod
+		row.RenewalProductName = latest.ProductName
+		row.RenewalCurrency = latest.PriceCurrency
+		if latest.Amount != nil {
+			row.RenewalPriceTI = int64(latest.Amount.PriceTI)
+			row.RenewalPriceTE = int64(latest.Amount.PriceTE)
+		}
+	}
+	return row
+}
+
+// AttendeeRow is the Parquet schema of class attendees, partitioned by club and class date
+type AttendeeRow struct {
+	ID                string    `parquet:"id"`
+	ContactID         string    `parquet:"contact_id"`
+	ContactNumber     string    `parquet:"contact_number"`
+	ContactGivenName  string    `parquet:"contact_given_name"`
+	ContactFamilyName string    `parquet:"contact_family_name"`
+	ContactClubID     string    `parquet:"contact_club_id"`
+	ClassID           string    `parquet:"class_id"`
+	ClubID            string    `parquet:"club_id"`
+	ActivityID        string    `parquet:"activity_id"`
+	ActivityName      string    `parquet:"activity_name"`
+	CoachID           string    `parquet:"coach_id"`
+	StudioID          string    `parquet:"studio_id"`
+	ClassStartedAt    time.Time `parquet:"class_started_at,optional,timestamp(millisecond)"`
+	ClassEndedAt      time.Time `parquet:"class_ended_at,optional,timestamp(millisecond)"`
+	State             string    `parquet:"state"`
+	Showed            bool      `parquet:"showed"`
+	CancelDelayOver   bool      `parquet:"cancel_delay_over"`
+	CreatedAt         time.Time `parquet:"created_at,optional,timestamp(millisecond)"`
+	ValidatedAt       time.Time `parquet:"validated_at,optional,timestamp(millisecond)"`
+	QueuedAt          time.Time `parquet:"queued_at,optional,timestamp(millisecond)"`
+	CanceledAt        time.Time `parquet:"canceled_at,optional,timestamp(millisecond)"`
+}
+
+func (r AttendeeRow) Partition() Partition {
+	if !r.ClassStartedAt.IsZero() {
+		return Partition{ClubID: r.ClubID, Date: day(r.ClassStartedAt)}
+	}
+	return Partition{ClubID: r.ClubID, Date: day(r.CreatedAt)}
+}
+
+// Attendee flattens an attendee and the class it booked
+func (c Converter) Attendee(attendee xplorentities.XPlorAttendee) AttendeeRow {
+	row := AttendeeRow{
+		ID:                xplorentities.ShortID(util.Deref(attendee.AtID)),
+		ContactID:         xplorentities.ShortID(util.Deref(attendee.ContactId)),
+		ContactNumber:     util.Deref(attendee.ContactNumber),
+		ContactGivenName:  util.Deref(attendee.ContactGivenName),
+		ContactFamilyName: util.Deref(attendee.ContactFamilyName),
+		ContactClubID:     xplorentities.ShortID(util.Deref(attendee.ContactClubId)),
+		ActivityName:      util.Deref(attendee.ActivityName),
+		ClassStartedAt:    c.timestampText(attendee.ClassEventStart),
+		State:             util.Deref(attendee.State),
+		Showed:            attendee.Showed,
+		CancelDelayOver:   attendee.CancelDelayOver,
+		CreatedAt:         c.timestampText(attendee.CreatedAt),
+		ValidatedAt:       c.timestampText(attendee.ValidatedAt),
+		QueuedAt:          c.timestampText(attendee.QueuedAt),
+		CanceledAt:        c.timestampText(attendee.CanceledAt),
+	}
+	if class := attendee.ClassEvent; class != nil {
+		row.ClassID = xplorentities.ShortID(util.Deref(class.AtID))
+		row.ClubID = xplorentities.ShortID(util.Deref(class.Club))
+		row.ActivityID = xplorentities.ShortID(util.Deref(class.Activity))
+		row.CoachID = xplorentities.ShortID(util.Deref(class.Coach))
+		row.StudioID = xplorentities.ShortID(util.Deref(class.Studio))
+		if startedAt := c.timestampText(class.StartedAt); !startedAt.IsZero() {
+			row.ClassStartedAt = startedAt
+		}
+		row.ClassEndedAt = c.timestampText(class.EndedAt)
+	}
+	return row
+}
+
+// ClassRow is the Parquet schema of classes, partitioned by club and start date
+type ClassRow struct {
+	ID                string    `parquet:"id"`
+	ClubID            string    `parquet:"club_id"`
+	StudioID          string    `parquet:"studio_id"`
+	ActivityID        string    `parquet:"activity_id"`
+	CoachID           string    `parquet:"coach_id"`
+	RecurrenceID      string    `parquet:"recurrence_id"`
+	Summary           string    `parquet:"summary"`
+	StartedAt         time.Time `parquet:"started_at,optional,timestamp(millisecond)"`
+	EndedAt           time.Time `parquet:"ended_at,optional,timestamp(millisecond)"`
+	AttendingLimit    *int32    `parquet:"attending_limit,optional"`
+	QueueLimit        int32     `parquet:"queue_limit"`
+	Booked            int32     `parquet:"booked"`
+	Queued            int32     `parquet:"queued"`
+	AttendeeRemaining int32     `parquet:"attendee_remaining"`
+	QueueRemaining    int32     `parquet:"queue_remaining"`
+	CoachAvailable    bool      `parquet:"coach_available"`
+	CreatedAt         time.Time `parquet:"created_at,optional,timestamp(millisecond)"`
+	DeletedAt         time.Time `parquet:"deleted_at,optional,timestamp(millisecond)"`
+}
+
+func (r ClassRow) Partition() Partition {
+	return Partition{ClubID: r.ClubID, Date: day(r.StartedAt)}
+}
+
+// Class flattens a class; attendees are counted, export them with Attendee for the details
+func (c Converter) Class(class xplorentities.XPlorClass) ClassRow {
+	row := ClassRow{
+		ID:                xplorentities.ShortID(util.Deref(class.ID)),
+		ClubID:            xplorentities.ShortID(util.Deref(class.Club)),
+		StudioID:          xplorentities.ShortID(util.Deref(class.Studio)),
+		ActivityID:        xplorentities.ShortID(util.Deref(class.Activity)),
+		CoachID:           xplorentities.ShortID(util.Deref(class.Coach)),
+		RecurrenceID:      xplorentities.ShortID(util.Deref(class.Recurrence)),
+		Summary:           class.Summary,
+		StartedAt:         c.timestamp(&class.StartedAt),
+		EndedAt:           c.timestamp(&class.EndedAt),
+		QueueLimit:        int32(class.QueueLimit),
+		Booked:            int32(len(class.BookedAttendees)),
+		Queued:            int32(len(class.QueuedAttendees)),
+		AttendeeRemaining: int32(class.AttendeeRemaining),
+		QueueRemaining:    int32(class.QueueRemaining),
+		CoachAvailable:    class.CoachAvailable,
+		CreatedAt:         c.timestamp(class.CreatedAt),
+		DeletedAt:         c.timestamp(class.DeletedAt),
+	}
+	if class.AttendingLimit != nil {
+		limit := int32(*class.AttendingLimit)
+		row.AttendingLimit = &limit
+	}
+	return row
+}
+
+// CounterLineRow is the Parquet schema of counter lines. Counter lines carry no club,
+// so they are partitioned by start date under the default club partition.
+type CounterLineRow struct {
+	ContactID         string    `parquet:"contact_id"`
+	ContactNumber     string    `parquet:"contact_number"`
+	ContactGivenName  string    `parquet:"contact_given_name"`
+	ContactFamilyName string    `parquet:"contact_family_name"`
+	ArticleID         string    `parquet:"article_id"`
+	UnitID            string    `parquet:"unit_id"`
+	Service           string    `parquet:"service"`
+	TotalUnits        int32     `parquet:"total_units"`
+	RemainingUnits    int32     `parquet:"remaining_units"`
+	Movements         int32     `parquet:"movements"`
+	ValidFrom         time.Time `parquet:"valid_from,optional,timestamp(millisecond)"`
+	ValidThrough      time.Time `parquet:"valid_through,optional,timestamp(millisecond)"`
+	CreatedAt         time.Time `parquet:"created_at,optional,timestamp(millisecond)"`
+	UpdatedAt         time.Time `parquet:"updated_at,optional,timestamp(millisecond)"`
+	DeletedAt         time.Time `parquet:"deleted_at,optional,timestamp(millisecond)"`
+}
+
+func (r CounterLineRow) Partition() Partition {
+	return Partition{Date: day(r.ValidFrom)}
+}
+
+// CounterLine flattens a counter line
+func (c Converter) CounterLine(line xplorentities.XPlorCounterLine) CounterLineRow {
+	row := CounterLineRow{
+		ContactID:         xplorentities.ShortID(util.Deref(line.ContactID)),
+		ContactNumber:     line.ContactNumber,
+		ContactGivenName:  line.ContactFirstName,
+		ContactFamilyName: line.ContactFamilyName,
+		ArticleID:         xplorentities.ShortID(util.Deref(line.ArticleID)),
+		UnitID:            xplorentities.ShortID(util.Deref(line.Unit)),
+		TotalUnits:        int32(line.TotalUnities),
+		RemainingUnits:    int32(line.RemainingUnities),
+		Movements:         int32(len(line.CounterMovements)),
+		ValidFrom:         c.timestamp(line.ValidFrom),
+		ValidThrough:      c.timestamp(line.ValidThrough),
+		CreatedAt:         c.timestamp(line.CreatedAt),
+		UpdatedAt:         c.timestamp(&line.UpdatedAt),
+		DeletedAt:         c.timestamp(line.DeletedAt),
+	}
+	if line.ServiceProperty != nil {
+		row.Service = xplorentities.ShortID(util.Deref(line.ServiceProperty.Service))
+	}
+	return row
+}
+
+// ArticleRow is the Parquet schema of sold articles, partitioned by club and sale date
+type ArticleRow struct {
+	ID                string    `parquet:"id"`
+	ClubID            string    `parquet:"club_id"`
+	ContactID         string    `parquet:"contact_id"`
+	ContactNumber     string    `parquet:"contact_number"`
+	ProductCode       string    `parquet:"product_code"`
+	ProductName       string    `parquet:"product_name"`
+	ProductType       string    `parquet:"product_type"`
+	OfferName         string    `parquet:"offer_name"`
+	Currency          string    `parquet:"currency"`
+	PriceTI           int64     `parquet:"price_ti,decimal(2:18)"`
+	PriceTE           int64     `parquet:"price_te,decimal(2:18)"`
+	Tax               int64     `parquet:"tax,decimal(2:18)"`
+	TaxRate           int64     `parquet:"tax_rate,decimal(2:18)"`
+	RegistrationFeeTI int64     `parquet:"registration_fee_ti,decimal(2:18)"`
+	TotalTI           int64     `parquet:"total_ti,decimal(2:18)"`
+	TotalTE           int64     `parquet:"total_te,decimal(2:18)"`
+	InvoiceReference  string    `parquet:"invoice_reference"`
+	RenewalType       string    `parquet:"renewal_type"`
+	CreatedAt         time.Time `parquet:"created_at,optional,timestamp(millisecond)"`
+	DeletedAt         time.Time `parquet:"deleted_at,optional,timestamp(millisecond)"`
+}
+
+func (r ArticleRow) Partition() Partition {
+	return Partition{ClubID: r.ClubID, Date: day(r.CreatedAt)}
+}
+
+// Article flattens a sold article
+func (c Converter) Article(article xplorentities.XPlorArticle) ArticleRow {
+	return ArticleRow{
+		ID:                xplorentities.ShortID(util.Deref(article.ID)),
+		ClubID:            xplorentities.ShortID(util.Deref(article.ClubID)),
+		ContactID:         xplorentities.ShortID(util.Deref(article.ContactID)),
+		ContactNumber:     article.ContactNumber,
+		ProductCode:       article.ProductCode,
+		ProductName:       article.ProductName,
+		ProductType:       article.ProductType,
+		OfferName:         article.OfferName,
+		Currency:          article.PriceCurrency,
+		PriceTI:           decimal(article.PriceTI),
+		PriceTE:           decimal(article.PriceTE),
+		Tax:               decimal(article.Tax),
+		TaxRate:           decimal(article.TaxRate),
+		RegistrationFeeTI: decimal(article.RegistrationFeeTI),
+		TotalTI:           decimal(article.TotalTI),
+		TotalTE:           decimal(article.TotalTE),
+		InvoiceReference:  article.InvoiceReference,
+		RenewalType:       article.RenewalType,
+		CreatedAt:         c.timestamp(article.CreatedAt),
+		DeletedAt:         c.timestamp(article.DeletedAt),
+	}
+}
+
+// timestamp returns the instant of an API datetime in UTC, reading naive values in c.Location
+func (c Converter) timestamp(value *util.LocalTime) time.Time {
+	return util.InstantUTC(value, c.Location)
+}
+
+// timestampString parses a datetime sent as a plain string; invalid values are left empty
+func (c Converter) timestampString(value string) time.Time {
+	return c.timestamp(util.ParseLocalTime(value))
+}
+
+func (c Converter) timestampText(value *string) time.Time {
+	return c.timestampString(util.Deref(value))
+}
+
+// localDate returns a date as days since the Unix epoch
+func localDate(value *util.LocalDate) int32 {
+	if value == nil || value.IsZero() {
+		return 0
+	}
+	return int32(value.Unix() / secondsPerDay)
+}
+
+// dateString parses a date, or the date of a datetime, sent as a plain string; invalid values are left empty
+func dateString(value string) int32 {
+	if value == "" {
+		return 0
+	}
+	var parsed util.LocalDate
+	if parsed.UnmarshalJSON([]byte(strconv.Quote(value))) != nil {
+		return 0
+	}
+	return localDate(&parsed)
+}
+
+// day returns the UTC date of a timestamp, used for partitions
+func day(value time.Time) time.Time {
+	if value.IsZero() {
+		return time.Time{}
+	}
+	return value.Truncate(24 * time.Hour)
+}
+
+func epochDay(days int32) time.Time {
+	if days == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(days)*secondsPerDay, 0).UTC()
+}
+
+// decimal scales an amount in currency units to cents
+func decimal(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
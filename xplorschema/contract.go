@@ -0,0 +1,168 @@
+package xplorschema
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+//go:embed fixtures/*.json
+var embeddedFixtures embed.FS
+
+// Fixture is a recorded response body of a resource: one entity or a collection page
+type Fixture struct {
+	Name     string
+	Resource xplorentities.Resource
+	Body     []byte
+}
+
+// entityFactories creates the entity decoding each resource with a schema
+var entityFactories = map[xplorentities.Resource]func() xplorentities.Entity{
+	xplorentities.ResourceActivities:    func() xplorentities.Entity { return &xplorentities.XPlorActivity{} },
+	xplorentities.ResourceArticles:      func() xplorentities.Entity { return &xplorentities.XPlorArticle{} },
+	xplorentities.ResourceAttendees:     func() xplorentities.Entity { return &xplorentities.XPlorAttendee{} },
+	xplorentities.ResourceClasses:       func() xplorentities.Entity { return &xplorentities.XPlorClass{} },
+	xplorentities.ResourceClassTypes:    func() xplorentities.Entity { return &xplorentities.XPlorClassType{} },
+	xplorentities.ResourceClubs:         func() xplorentities.Entity { return &xplorentities.XPlorClub{} },
+	xplorentities.ResourceCoaches:       func() xplorentities.Entity { return &xplorentities.XPloreCoach{} },
+	xplorentities.ResourceContacts:      func() xplorentities.Entity { return &xplorentities.XPlorContact{} },
+	xplorentities.ResourceContactImages: func() xplorentities.Entity { return &xplorentities.XPlorContactImage{} },
+	xplorentities.ResourceContactTags:   func() xplorentities.Entity { return &xplorentities.XPlorContactTag{} },
+	xplorentities.ResourceCounterLines:  func() xplorentities.Entity { return &xplorentities.XPlorCounterLine{} },
+	xplorentities.ResourceEvents:        func() xplorentities.Entity { return &xplorentities.XPlorEvent{} },
+	xplorentities.ResourceFamilies:      func() xplorentities.Entity { return &xplorentities.XPlorFamily{} },
+	xplorentities.ResourceNetworkNodes:  func() xplorentities.Entity { return &xplorentities.XPlorNetworkNode{} },
+	xplorentities.ResourceRecurrences:   func() xplorentities.Entity { return &xplorentities.XPlorRecurrence{} },
+	xplorentities.ResourceStudios:       func() xplorentities.Entity { return &xplorentities.XPlorStudio{} },
+	xplorentities.ResourceSubscriptions: func() xplorentities.Entity { return &xplorentities.XPlorSubscription{} },
+	xplorentities.ResourceUsers:         func() xplorentities.Entity { return &xplorentities.XPlorUser{} },
+	xplorentities.ResourceZones:         func() xplorentities.Entity { return &xplorentities.XPlorZone{} },
+}
+
+// Fixtures returns the fixtures shipped with the package, one per resource of the SDK
+func Fixtures() ([]Fixture, error) {
+	return LoadFixtures(embeddedFixtures)
+}
+
+// LoadFixtures reads every *.json file of fsys. The resource is the file name up to its first dot,
+// matched against the last segment of the resource path ("contact_images" is "files/contact_images"),
+// so recorded responses can be named after their case, e.g. "class_events.cancelled.json".
+func LoadFixtures(fsys fs.FS) ([]Fixture, error) {
+	var fixtures []Fixture
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || path.Ext(name) != ".json" {
+			return err
+		}
+		body, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		base, _, _ := strings.Cut(path.Base(name), ".")
+		fixtures = append(fixtures, Fixture{Name: name, Resource: resourceNamed(base), Body: body})
+		return nil
+	})
+	sort.Slice(fixtures, func(i, j int) bool { return fixtures[i].Name < fixtures[j].Name })
+	return fixtures, err
+}
+
+// Check runs a fixture through the contract: the recorded body must satisfy the schema,
+// decode into its entity in strict mode (no unknown fields, no type mismatches),
+// and the decoded entity must still satisfy the schema once serialized.
+// Collection pages are checked member by member.
+func (s *Spec) Check(fixture Fixture) []Violation {
+	newEntity, ok := entityFactories[fixture.Resource]
+	if _, hasSchema := s.SchemaFor(fixture.Resource); !ok || !hasSchema {
+		return []Violation{{Stage: "fixture", Message: "no entity or schema for resource " + string(fixture.Resource)}}
+	}
+
+	members, prefix, err := fixtureMembers(fixture.Body)
+	if err != nil {
+		return []Violation{{Stage: "fixture", Message: "invalid JSON: " + err.Error()}}
+	}
+	var violations []Violation
+	add := func(stage, memberPath string, found []Violation) {
+		for _, violation := range found {
+			violation.Stage = stage
+			violation.Path = joinPath(memberPath, violation.Path)
+			violations = append(violations, violation)
+		}
+	}
+	for i, member := range members {
+		memberPath := prefix
+		if prefix != "" {
+			memberPath += "[" + strconv.Itoa(i) + "]"
+		}
+
+		found, err := s.ValidateJSON(fixture.Resource, member)
+		if err != nil {
+			add("fixture", memberPath, []Violation{{Message: err.Error()}})
+			continue
+		}
+		add("fixture", memberPath, found)
+
+		entity := newEntity()
+		warnings, err := util.Unmarshal(member, entity, util.DecodeStrict)
+		for _, warning := range warnings {
+			add("decode", memberPath, []Violation{{Path: warning.Path, Rule: string(warning.Kind), Message: "not declared by the entity"}})
+		}
+		if err != nil && len(warnings) == 0 {
+			add("decode", memberPath, []Violation{{Message: err.Error()}})
+			continue
+		}
+
+		found, err = s.Validate(entity)
+		if err != nil {
+			add("entity", memberPath, []Violation{{Message: err.Error()}})
+			continue
+		}
+		add("entity", memberPath, found)
+	}
+	return violations
+}
+
+// fixtureMembers splits a collection page into its members; a single entity is returned as is
+func fixtureMembers(body []byte) ([]json.RawMessage, string, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, "", err
+	}
+	raw, ok := document["hydra:member"]
+	if !ok {
+		return []json.RawMessage{body}, "", nil
+	}
+	var members []json.RawMessage
+	if err := json.Unmarshal(raw, &members); err != nil {
+		return nil, "", fmt.Errorf("hydra:member: %w", err)
+	}
+	return members, "hydra:member", nil
+}
+
+// CheckAll runs every fixture through Check and returns the violations by fixture name.
+// Fixtures without violations are left out, so an empty result means the contract holds.
+func (s *Spec) CheckAll(fixtures []Fixture) map[string][]Violation {
+	found := make(map[string][]Violation)
+	for _, fixture := range fixtures {
+		if violations := s.Check(fixture); len(violations) > 0 {
+			found[fixture.Name] = violations
+		}
+	}
+	return found
+}
+
+// resourceNamed returns the resource whose path ends with name
+func resourceNamed(name string) xplorentities.Resource {
+	for _, resource := range xplorentities.Resources {
+		if path.Base(string(resource)) == name {
+			return resource
+		}
+	}
+	return xplorentities.Resource(name)
+}
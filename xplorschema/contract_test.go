@@ -0,0 +1,34 @@
+package xplorschema
+
+import (
+	"testing"
+
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+func TestContract(t *testing.T) {
+	fixtures, err := Fixtures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	covered := make(map[xplorentities.Resource]bool)
+	for _, fixture := range fixtures {
+		covered[fixture.Resource] = true
+		t.Run(fixture.Name, func(t *testing.T) {
+			for _, violation := range Default().Check(fixture) {
+				t.Error(violation.Error())
+			}
+		})
+	}
+	for _, resource := range xplorentities.Resources {
+		if !covered[resource] {
+			t.Errorf("no fixture for resource %s", resource)
+		}
+		if _, ok := entityFactories[resource]; !ok {
+			t.Errorf("no entity factory for resource %s", resource)
+		}
+		if _, ok := Default().SchemaFor(resource); !ok {
+			t.Errorf("no schema for resource %s", resource)
+		}
+	}
+}
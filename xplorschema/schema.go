@@ -0,0 +1,337 @@
+// Package xplorschema validates XPlor entities against a local copy of the Resamania OpenAPI schemas.
+// The embedded openapi.json covers the resources read by the SDK; it only uses the schema keywords
+// understood by the validator: type, format, nullable, required, properties, items, enum,
+// minLength, maxLength, minimum, maximum, minItems, pattern and $ref.
+package xplorschema
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+//go:embed openapi.json
+var embeddedSpec []byte
+
+// Schema is an OpenAPI 3.0 schema object
+type Schema struct {
+	Ref        string             `json:"$ref,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Nullable   bool               `json:"nullable,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+	Enum       []any              `json:"enum,omitempty"`
+	MinLength  *int               `json:"minLength,omitempty"`
+	MaxLength  *int               `json:"maxLength,omitempty"`
+	Minimum    *float64           `json:"minimum,omitempty"`
+	Maximum    *float64           `json:"maximum,omitempty"`
+	MinItems   *int               `json:"minItems,omitempty"`
+	Pattern    string             `json:"pattern,omitempty"`
+	// Resource names the collection the schema describes (x-resource extension)
+	Resource xplorentities.Resource `json:"x-resource,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// Spec is a parsed OpenAPI document
+type Spec struct {
+	OpenAPI    string `json:"openapi"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+
+	byResource map[xplorentities.Resource]*Schema
+}
+
+// Violation is a value breaking its schema
+type Violation struct {
+	Stage   string // Set by Check: fixture, decode or entity
+	Path    string // JSON path, e.g. hydra:member[0].code
+	Rule    string // Schema keyword, e.g. maxLength
+	Message string
+}
+
+func (v Violation) Error() string {
+	var b strings.Builder
+	if v.Stage != "" {
+		b.WriteString(v.Stage + ": ")
+	}
+	if v.Path != "" {
+		b.WriteString(v.Path + ": ")
+	}
+	b.WriteString(v.Message)
+	if v.Rule != "" {
+		b.WriteString(" (" + v.Rule + ")")
+	}
+	return b.String()
+}
+
+var (
+	defaultSpec     *Spec
+	defaultSpecOnce sync.Once
+)
+
+// Default returns the embedded spec
+func Default() *Spec {
+	defaultSpecOnce.Do(func() {
+		spec, err := Load(embeddedSpec)
+		if err != nil {
+			panic("xplorschema: invalid embedded spec: " + err.Error())
+		}
+		defaultSpec = spec
+	})
+	return defaultSpec
+}
+
+// Load parses an OpenAPI document, such as a newer copy of the Resamania definitions
+func Load(data []byte) (*Spec, error) {
+	spec := &Spec{}
+	if err := json.Unmarshal(data, spec); err != nil {
+		return nil, err
+	}
+	spec.byResource = make(map[xplorentities.Resource]*Schema)
+	for name, schema := range spec.Components.Schemas {
+		if err := spec.compile(schema, name); err != nil {
+			return nil, err
+		}
+		if schema.Resource != "" {
+			spec.byResource[schema.Resource] = schema
+		}
+	}
+	return spec, nil
+}
+
+// compile checks references and compiles patterns
+func (s *Spec) compile(schema *Schema, path string) error {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		if _, err := s.resolve(schema.Ref); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if schema.Pattern != "" {
+		pattern, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", path, err)
+		}
+		schema.pattern = pattern
+	}
+	for name, property := range schema.Properties {
+		if err := s.compile(property, path+"."+name); err != nil {
+			return err
+		}
+	}
+	return s.compile(schema.Items, path+"[]")
+}
+
+func (s *Spec) resolve(ref string) (*Schema, error) {
+	name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %s", ref)
+	}
+	schema, ok := s.Components.Schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %s", ref)
+	}
+	return schema, nil
+}
+
+// Resources lists the resources described by the spec
+func (s *Spec) Resources() []xplorentities.Resource {
+	resources := make([]xplorentities.Resource, 0, len(s.byResource))
+	for resource := range s.byResource {
+		resources = append(resources, resource)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i] < resources[j] })
+	return resources
+}
+
+// SchemaFor returns the schema of the entities of resource
+func (s *Spec) SchemaFor(resource xplorentities.Resource) (*Schema, bool) {
+	schema, ok := s.byResource[resource]
+	return schema, ok
+}
+
+// Validate checks a decoded entity against the schema of its resource.
+// The entity is validated as the SDK would serialize it.
+func (s *Spec) Validate(entity xplorentities.Entity) ([]Violation, error) {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	return s.ValidateJSON(entity.Resource(), data)
+}
+
+// ValidateJSON checks a JSON entity of resource against its schema
+func (s *Spec) ValidateJSON(resource xplorentities.Resource, data []byte) ([]Violation, error) {
+	schema, ok := s.SchemaFor(resource)
+	if !ok {
+		return nil, fmt.Errorf("no schema for resource %s", resource)
+	}
+	var value any
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	var violations []Violation
+	s.validate(schema, value, "", &violations)
+	return violations, nil
+}
+
+func (s *Spec) validate(schema *Schema, value any, path string, violations *[]Violation) {
+	if schema.Ref != "" {
+		resolved, err := s.resolve(schema.Ref)
+		if err != nil {
+			*violations = append(*violations, Violation{Path: path, Rule: "$ref", Message: err.Error()})
+			return
+		}
+		schema = resolved
+	}
+	report := func(rule, format string, args ...any) {
+		*violations = append(*violations, Violation{Path: path, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			report("nullable", "must not be null")
+		}
+		return
+	}
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		report("enum", "%v is not one of %v", value, schema.Enum)
+	}
+
+	switch v := value.(type) {
+	case string:
+		if schema.Type != "" && schema.Type != "string" {
+			report("type", "expected %s, got string", schema.Type)
+			return
+		}
+		length := len([]rune(v))
+		if schema.MinLength != nil && length < *schema.MinLength {
+			report("minLength", "length %d is below %d", length, *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			report("maxLength", "length %d exceeds %d", length, *schema.MaxLength)
+		}
+		if schema.pattern != nil && !schema.pattern.MatchString(v) {
+			report("pattern", "does not match %s", schema.Pattern)
+		}
+		if message := checkFormat(schema.Format, v); message != "" {
+			report("format", "%s", message)
+		}
+	case json.Number:
+		if schema.Type == "integer" {
+			if _, err := strconv.ParseInt(v.String(), 10, 64); err != nil {
+				report("type", "expected integer, got %s", v)
+				return
+			}
+		} else if schema.Type != "" && schema.Type != "number" {
+			report("type", "expected %s, got number", schema.Type)
+			return
+		}
+		number, _ := v.Float64()
+		if schema.Minimum != nil && number < *schema.Minimum {
+			report("minimum", "%s is below %v", v, *schema.Minimum)
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			report("maximum", "%s exceeds %v", v, *schema.Maximum)
+		}
+	case bool:
+		if schema.Type != "" && schema.Type != "boolean" {
+			report("type", "expected %s, got boolean", schema.Type)
+		}
+	case []any:
+		if schema.Type != "" && schema.Type != "array" {
+			report("type", "expected %s, got array", schema.Type)
+			return
+		}
+		if schema.MinItems != nil && len(v) < *schema.MinItems {
+			report("minItems", "%d items, expected at least %d", len(v), *schema.MinItems)
+		}
+		if schema.Items != nil {
+			for i, item := range v {
+				s.validate(schema.Items, item, path+"["+strconv.Itoa(i)+"]", violations)
+			}
+		}
+	case map[string]any:
+		if schema.Type != "" && schema.Type != "object" {
+			report("type", "expected %s, got object", schema.Type)
+			return
+		}
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				*violations = append(*violations, Violation{Path: joinPath(path, name), Rule: "required", Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := v[name]; ok {
+				s.validate(schema.Properties[name], property, joinPath(path, name), violations)
+			}
+		}
+	}
+}
+
+func inEnum(enum []any, value any) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// dateTimeLayouts accepts RFC 3339 and the naive datetimes some endpoints return
+var dateTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05"}
+
+// checkFormat returns why value does not match format, or "" when it does or the format is unknown
+func checkFormat(format, value string) string {
+	switch format {
+	case "date-time":
+		for _, layout := range dateTimeLayouts {
+			if _, err := time.Parse(layout, value); err == nil {
+				return ""
+			}
+		}
+		return "invalid date-time " + strconv.Quote(value)
+	case "date":
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return "invalid date " + strconv.Quote(value)
+		}
+	case "email":
+		if _, err := mail.ParseAddress(value); err != nil {
+			return "invalid email"
+		}
+	case "iri-reference":
+		if !strings.HasPrefix(value, "/") {
+			return "invalid IRI reference " + strconv.Quote(value)
+		}
+	}
+	return ""
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,28 @@
+package xplorserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// writeError writes an ErrorResponse as JSON with the given status
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(xplorentities.ErrorResponse{Code: status, Message: message})
+}
+
+// upstreamStatus maps an SDK error to the status and message returned to clients.
+// Upstream failures are never passed through: their messages carry raw API responses,
+// and a 401 or 404 from the API says nothing about the public request.
+func upstreamStatus(code int) (int, string) {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return http.StatusServiceUnavailable, "Timetable temporarily unavailable"
+	default:
+		return http.StatusBadGateway, "Timetable unavailable"
+	}
+}
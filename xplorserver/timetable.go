@@ -0,0 +1,462 @@
+package xplorserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// TimetableSource is the subset of the provider used to build a timetable.
+// *xplorcore.XplorProvider satisfies it. The context is the one of the client request.
+type TimetableSource interface {
+	ClassesContext(ctx context.Context, nodeId string, params *xplorentities.XPlorClassesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorClasses, *xplorentities.ErrorResponse)
+	ActivitiesContext(ctx context.Context, nodeId string, queryParams *xplorentities.XPlorActivitiesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorActivities, *xplorentities.ErrorResponse)
+	CoachesContext(ctx context.Context, nodeId string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPloreCoaches, *xplorentities.ErrorResponse)
+	StudiosContext(ctx context.Context, nodeId string, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorStudios, *xplorentities.ErrorResponse)
+}
+
+// TimetableHandler serves a read-only JSON timetable for a network node.
+//
+// Supported query parameters:
+//   - club: club ID
+//   - activity, coach, studio: IDs, repeatable
+//   - from, to: dates (2006-01-02) or datetimes (2006-01-02T15:04:05)
+type TimetableHandler struct {
+	Source       TimetableSource
+	NodeID       string
+	MaxAge       time.Duration // Cache-Control max-age sent to clients
+	DefaultRange time.Duration // Range used when "to" is omitted
+	MaxRange     time.Duration // Largest accepted from/to window
+	ItemsPerPage int           // Page size used when fetching from the API
+	MaxPages     int           // Pages fetched per collection before failing the request; zero means no limit
+	Logger       *slog.Logger  // Receives the details of failed requests; nil uses slog.Default()
+}
+
+// NewTimetableHandler creates a timetable handler for the given node with default settings
+func NewTimetableHandler(source TimetableSource, nodeId string) *TimetableHandler {
+	return &TimetableHandler{
+		Source:       source,
+		NodeID:       nodeId,
+		MaxAge:       60 * time.Second,
+		DefaultRange: 7 * 24 * time.Hour,
+		MaxRange:     31 * 24 * time.Hour,
+		ItemsPerPage: 100,
+		MaxPages:     20,
+	}
+}
+
+// Timetable is the JSON document returned by the handler
+type Timetable struct {
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+	Sessions []TimetableSession `json:"sessions"`
+}
+
+// TimetableSession is a single class in the timetable
+type TimetableSession struct {
+	ID             string          `json:"id"`
+	Summary        string          `json:"summary"`
+	StartedAt      string          `json:"startedAt"`
+	EndedAt        string          `json:"endedAt"`
+	ClubID         string          `json:"clubId,omitempty"`
+	Activity       *TimetableLabel `json:"activity,omitempty"`
+	Coach          *TimetableLabel `json:"coach,omitempty"`
+	Studio         *TimetableLabel `json:"studio,omitempty"`
+	Capacity       *int            `json:"capacity"`
+	Remaining      int             `json:"remaining"`
+	QueueRemaining int             `json:"queueRemaining"`
+	Available      bool            `json:"available"`
+	Full           bool            `json:"full"`
+}
+
+// TimetableLabel is a referenced resource reduced to its ID and display name
+type TimetableLabel struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+type timetableQuery struct {
+	club       string
+	activities []string
+	coaches    []string
+	studios    []string
+	from       time.Time
+	to         time.Time
+}
+
+const (
+	timetableDateLayout     = "2006-01-02"
+	timetableDateTimeLayout = "2006-01-02T15:04:05"
+)
+
+func (th *TimetableHandler) logger() *slog.Logger {
+	if th.Logger != nil {
+		return th.Logger
+	}
+	return slog.Default()
+}
+
+// ServeHTTP implements http.Handler
+func (th *TimetableHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query, errMsg := th.parseQuery(r)
+	if errMsg != "" {
+		writeError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+
+	timetable, lastModified, err := th.build(r.Context(), query)
+	if err != nil && r.Context().Err() != nil {
+		// The client went away; nobody reads the answer
+		return
+	}
+	if err != nil {
+		status, message := upstreamStatus(err.Code)
+		th.logger().LogAttrs(r.Context(), slog.LevelError, "timetable upstream request failed",
+			slog.String("node_id", th.NodeID), slog.Int("upstream_status", err.Code), slog.String("error", err.Message))
+		writeError(w, status, message)
+		return
+	}
+
+	body, marshalErr := json.Marshal(timetable)
+	if marshalErr != nil {
+		th.logger().LogAttrs(r.Context(), slog.LevelError, "timetable encoding failed", slog.String("error", marshalErr.Error()))
+		writeError(w, http.StatusInternalServerError, "Failed to encode timetable")
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	header := w.Header()
+	header.Set("Content-Type", "application/json")
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(th.MaxAge.Seconds())))
+	header.Set("Vary", "Accept-Encoding")
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(body)
+	}
+}
+
+func (th *TimetableHandler) parseQuery(r *http.Request) (timetableQuery, string) {
+	values := r.URL.Query()
+	query := timetableQuery{
+		club:       strings.TrimSpace(values.Get("club")),
+		activities: nonEmpty(values["activity"]),
+		coaches:    nonEmpty(values["coach"]),
+		studios:    nonEmpty(values["studio"]),
+	}
+
+	now := time.Now()
+	query.from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if raw := values.Get("from"); raw != "" {
+		from, ok := parseBoundary(raw)
+		if !ok {
+			return query, "Invalid from: " + raw
+		}
+		query.from = from
+	}
+	query.to = query.from.Add(th.DefaultRange)
+	if raw := values.Get("to"); raw != "" {
+		to, ok := parseBoundary(raw)
+		if !ok {
+			return query, "Invalid to: " + raw
+		}
+		if len(raw) == len(timetableDateLayout) {
+			// A bare date includes the whole day
+			to = to.AddDate(0, 0, 1)
+		}
+		query.to = to
+	}
+
+	if !query.to.After(query.from) {
+		return query, "to must be after from"
+	}
+	if th.MaxRange > 0 && query.to.Sub(query.from) > th.MaxRange {
+		return query, "Requested range exceeds " + th.MaxRange.String()
+	}
+	return query, ""
+}
+
+// build fetches the four collections concurrently; the first failure cancels the other fetches and is returned
+func (th *TimetableHandler) build(ctx context.Context, query timetableQuery) (*Timetable, time.Time, *xplorentities.ErrorResponse) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg         sync.WaitGroup
+		failed     sync.Once
+		firstErr   *xplorentities.ErrorResponse
+		classes    []xplorentities.XPlorClass
+		activities []xplorentities.XPlorActivity
+		coaches    []xplorentities.XPloreCoach
+		studios    []xplorentities.XPlorStudio
+	)
+	fetch := func(run func() *xplorentities.ErrorResponse) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := run(); err != nil {
+				failed.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+
+	fetch(func() (err *xplorentities.ErrorResponse) {
+		classes, err = th.fetchClasses(ctx, query)
+		return err
+	})
+	fetch(func() (err *xplorentities.ErrorResponse) {
+		activities, err = fetchAll(ctx, th, "activities", func(pagination *xplorentities.XPlorPagination) (*xplorentities.Collection[xplorentities.XPlorActivity], *xplorentities.ErrorResponse) {
+			page, err := th.Source.ActivitiesContext(ctx, th.NodeID, nil, pagination)
+			if err != nil {
+				return nil, err
+			}
+			return &page.Collection, nil
+		})
+		return err
+	})
+	fetch(func() (err *xplorentities.ErrorResponse) {
+		coaches, err = fetchAll(ctx, th, "coaches", func(pagination *xplorentities.XPlorPagination) (*xplorentities.Collection[xplorentities.XPloreCoach], *xplorentities.ErrorResponse) {
+			page, err := th.Source.CoachesContext(ctx, th.NodeID, pagination)
+			if err != nil {
+				return nil, err
+			}
+			return &page.Collection, nil
+		})
+		return err
+	})
+	fetch(func() (err *xplorentities.ErrorResponse) {
+		studios, err = fetchAll(ctx, th, "studios", func(pagination *xplorentities.XPlorPagination) (*xplorentities.Collection[xplorentities.XPlorStudio], *xplorentities.ErrorResponse) {
+			page, err := th.Source.StudiosContext(ctx, th.NodeID, pagination)
+			if err != nil {
+				return nil, err
+			}
+			return &page.Collection, nil
+		})
+		return err
+	})
+	wg.Wait()
+	if firstErr != nil {
+		return nil, time.Time{}, firstErr
+	}
+
+	activityLabels := make(map[string]*TimetableLabel, len(activities))
+	for _, activity := range activities {
+		if id, err := activity.ActivityID(); err == nil {
+			activityLabels[id] = &TimetableLabel{ID: id, Name: activity.Name, Color: activity.ColorHex}
+		}
+	}
+	coachLabels := make(map[string]*TimetableLabel, len(coaches))
+	for _, coach := range coaches {
+		if id, err := coach.CoachID(); err == nil {
+			coachLabels[id] = &TimetableLabel{ID: id, Name: coachName(coach)}
+		}
+	}
+	studioLabels := make(map[string]*TimetableLabel, len(studios))
+	for _, studio := range studios {
+		if id, err := studio.StudioID(); err == nil {
+			studioLabels[id] = &TimetableLabel{ID: id, Name: studio.Name}
+		}
+	}
+
+	timetable := &Timetable{
+		From:     query.from.Format(timetableDateTimeLayout),
+		To:       query.to.Format(timetableDateTimeLayout),
+		Sessions: make([]TimetableSession, 0, len(classes)),
+	}
+	var lastModified time.Time
+	for _, class := range classes {
+		if !class.IsActive() {
+			continue
+		}
+		activityID, _ := class.ActivityID()
+		coachID, _ := class.CoachID()
+		studioID, _ := class.StudioID()
+		if !matches(query.activities, activityID) || !matches(query.coaches, coachID) || !matches(query.studios, studioID) {
+			continue
+		}
+
+		id, _ := class.ClassEventID()
+		clubID, _ := class.ClubID()
+		session := TimetableSession{
+			ID:             id,
+			Summary:        class.Summary,
+			StartedAt:      class.StartedAt.Format(timetableDateTimeLayout),
+			EndedAt:        class.EndedAt.Format(timetableDateTimeLayout),
+			ClubID:         clubID,
+			Activity:       label(activityLabels, activityID),
+			Coach:          label(coachLabels, coachID),
+			Studio:         label(studioLabels, studioID),
+			Capacity:       class.AttendingLimit,
+			Remaining:      class.AttendeeRemaining,
+			QueueRemaining: class.QueueRemaining,
+			// Classes without an attending limit are never full
+			Available: class.AttendingLimit == nil || class.HasAvailableSpots(),
+			Full:      class.AttendingLimit != nil && !class.HasAvailableSpots(),
+		}
+		timetable.Sessions = append(timetable.Sessions, session)
+
+		modified := class.CreatedAt
+		if class.UpdatedAt != nil {
+			modified = class.UpdatedAt
+		}
+		if modified != nil && modified.After(lastModified) {
+			lastModified = modified.Time
+		}
+	}
+	slices.SortStableFunc(timetable.Sessions, func(a, b TimetableSession) int {
+		return strings.Compare(a.StartedAt, b.StartedAt)
+	})
+
+	return timetable, lastModified.Truncate(time.Second), nil
+}
+
+func (th *TimetableHandler) fetchClasses(ctx context.Context, query timetableQuery) ([]xplorentities.XPlorClass, *xplorentities.ErrorResponse) {
+	params := &xplorentities.XPlorClassesParams{
+		StartedAtAfter:          &query.from,
+		StartedAtStrictlyBefore: &query.to,
+	}
+	if query.club != "" {
+		params.Club = &query.club
+	}
+	return fetchAll(ctx, th, "classes", func(pagination *xplorentities.XPlorPagination) (*xplorentities.Collection[xplorentities.XPlorClass], *xplorentities.ErrorResponse) {
+		page, err := th.Source.ClassesContext(ctx, th.NodeID, params, pagination)
+		if err != nil {
+			return nil, err
+		}
+		return &page.Collection, nil
+	})
+}
+
+// fetchAll reads the pages of a collection until the last one, and fails once MaxPages were read
+// or ctx is done between two pages
+func fetchAll[T any](ctx context.Context, th *TimetableHandler, name string, fetch func(pagination *xplorentities.XPlorPagination) (*xplorentities.Collection[T], *xplorentities.ErrorResponse)) ([]T, *xplorentities.ErrorResponse) {
+	var all []T
+	for page := 1; ; page++ {
+		if th.MaxPages > 0 && page > th.MaxPages {
+			return nil, &xplorentities.ErrorResponse{
+				Code:    http.StatusBadGateway,
+				Message: "More than " + strconv.Itoa(th.MaxPages) + " pages of " + name,
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, &xplorentities.ErrorResponse{Code: http.StatusRequestTimeout, Message: "Timetable request cancelled: " + err.Error()}
+		}
+		result, err := fetch(th.page(page))
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, result.Members...)
+		if !hasNextPage(result.View, len(result.Members)) {
+			return all, nil
+		}
+	}
+}
+
+func (th *TimetableHandler) page(page int) *xplorentities.XPlorPagination {
+	return &xplorentities.XPlorPagination{Page: page, ItemsPerPage: th.ItemsPerPage}
+}
+
+// hasNextPage reports whether the API announced another page after the current one
+func hasNextPage(view *xplorentities.HydraView, received int) bool {
+	if view == nil || received == 0 {
+		return false
+	}
+	_, err := view.NextPageNumber()
+	return err == nil
+}
+
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		if since, err := http.ParseTime(ims); err == nil {
+			return !lastModified.After(since)
+		}
+	}
+	return false
+}
+
+func parseBoundary(raw string) (time.Time, bool) {
+	for _, layout := range []string{timetableDateTimeLayout, "2006-01-02 15:04:05", timetableDateLayout} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func coachName(coach xplorentities.XPloreCoach) string {
+	if coach.AlternateName != nil && *coach.AlternateName != "" {
+		return *coach.AlternateName
+	}
+	var parts []string
+	if coach.GivenName != nil {
+		parts = append(parts, *coach.GivenName)
+	}
+	if coach.FamilyName != nil {
+		parts = append(parts, *coach.FamilyName)
+	}
+	return strings.Join(parts, " ")
+}
+
+func label(labels map[string]*TimetableLabel, id string) *TimetableLabel {
+	if id == "" {
+		return nil
+	}
+	if l, ok := labels[id]; ok {
+		return l
+	}
+	return &TimetableLabel{ID: id}
+}
+
+func matches(filter []string, id string) bool {
+	return len(filter) == 0 || slices.Contains(filter, id)
+}
+
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
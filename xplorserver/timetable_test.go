@@ -0,0 +1,230 @@
+package xplorserver
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// fakeSource serves classes on every page; with endless, every page announces a next one
+type fakeSource struct {
+	classes   []xplorentities.XPlorClass
+	err       *xplorentities.ErrorResponse
+	endless   bool
+	pages     int
+	cancelled bool
+}
+
+func (s *fakeSource) ClassesContext(ctx context.Context, _ string, _ *xplorentities.XPlorClassesParams, pagination *xplorentities.XPlorPagination) (*xplorentities.XPlorClasses, *xplorentities.ErrorResponse) {
+	s.pages++
+	if ctx.Err() != nil {
+		s.cancelled = true
+		return nil, &xplorentities.ErrorResponse{Code: http.StatusRequestTimeout, Message: ctx.Err().Error()}
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	page := &xplorentities.XPlorClasses{}
+	page.Members = s.classes
+	if s.endless {
+		page.View = &xplorentities.HydraView{HydraNext: "/enjoy/classes?page=" + strconv.Itoa(pagination.Page+1)}
+	}
+	return page, nil
+}
+
+func (s *fakeSource) ActivitiesContext(context.Context, string, *xplorentities.XPlorActivitiesParams, *xplorentities.XPlorPagination) (*xplorentities.XPlorActivities, *xplorentities.ErrorResponse) {
+	return &xplorentities.XPlorActivities{}, nil
+}
+
+func (s *fakeSource) CoachesContext(context.Context, string, *xplorentities.XPlorPagination) (*xplorentities.XPloreCoaches, *xplorentities.ErrorResponse) {
+	return &xplorentities.XPloreCoaches{}, nil
+}
+
+func (s *fakeSource) StudiosContext(context.Context, string, *xplorentities.XPlorPagination) (*xplorentities.XPlorStudios, *xplorentities.ErrorResponse) {
+	return &xplorentities.XPlorStudios{}, nil
+}
+
+func newClass(id string, startedAt, updatedAt time.Time) xplorentities.XPlorClass {
+	iri := "/enjoy/class_events/" + id
+	limit := 10
+	return xplorentities.XPlorClass{
+		ID:                &iri,
+		StartedAt:         util.LocalTime{Time: startedAt},
+		EndedAt:           util.LocalTime{Time: startedAt.Add(time.Hour)},
+		UpdatedAt:         &util.LocalTime{Time: updatedAt},
+		AttendingLimit:    &limit,
+		AttendeeRemaining: 4,
+	}
+}
+
+func serve(handler http.Handler, target string, header http.Header) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+const timetablePath = "/timetable?from=2025-06-02&to=2025-06-08"
+
+func TestTimetableETag(t *testing.T) {
+	source := &fakeSource{classes: []xplorentities.XPlorClass{
+		newClass("1", time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC), time.Date(2025, 5, 20, 8, 0, 0, 0, time.UTC)),
+	}}
+	handler := NewTimetableHandler(source, "2675")
+
+	first := serve(handler, timetablePath, nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", first.Code, first.Body)
+	}
+	var timetable Timetable
+	if err := json.Unmarshal(first.Body.Bytes(), &timetable); err != nil {
+		t.Fatal(err)
+	}
+	if len(timetable.Sessions) != 1 || timetable.Sessions[0].ID != "1" || !timetable.Sessions[0].Available {
+		t.Errorf("sessions = %+v, want class 1 available", timetable.Sessions)
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag")
+	}
+
+	second := serve(handler, timetablePath, http.Header{"If-None-Match": {`W/` + etag}})
+	if second.Code != http.StatusNotModified || second.Body.Len() != 0 {
+		t.Errorf("If-None-Match: status = %d with %d bytes, want 304 without body", second.Code, second.Body.Len())
+	}
+	changed := serve(handler, timetablePath, http.Header{"If-None-Match": {`"other"`}})
+	if changed.Code != http.StatusOK {
+		t.Errorf("other ETag: status = %d, want 200", changed.Code)
+	}
+}
+
+func TestTimetableLastModified(t *testing.T) {
+	updatedAt := time.Date(2025, 5, 20, 8, 30, 15, 0, time.UTC)
+	source := &fakeSource{classes: []xplorentities.XPlorClass{
+		newClass("1", time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC), time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC)),
+		newClass("2", time.Date(2025, 6, 4, 9, 0, 0, 0, time.UTC), updatedAt),
+	}}
+	handler := NewTimetableHandler(source, "2675")
+
+	response := serve(handler, timetablePath, nil)
+	if got := response.Header().Get("Last-Modified"); got != updatedAt.Format(http.TimeFormat) {
+		t.Errorf("Last-Modified = %q, want %q", got, updatedAt.Format(http.TimeFormat))
+	}
+	tests := []struct {
+		since time.Time
+		want  int
+	}{
+		{updatedAt, http.StatusNotModified},
+		{updatedAt.Add(time.Hour), http.StatusNotModified},
+		{updatedAt.Add(-time.Second), http.StatusOK},
+	}
+	for _, tt := range tests {
+		response := serve(handler, timetablePath, http.Header{"If-Modified-Since": {tt.since.Format(http.TimeFormat)}})
+		if response.Code != tt.want {
+			t.Errorf("If-Modified-Since %s: status = %d, want %d", tt.since, response.Code, tt.want)
+		}
+	}
+}
+
+func TestTimetableBadRange(t *testing.T) {
+	source := &fakeSource{}
+	handler := NewTimetableHandler(source, "2675")
+	for _, target := range []string{
+		"/timetable?from=2025-06-08&to=2025-06-02",
+		"/timetable?from=2025-06-02T10:00:00&to=2025-06-02T10:00:00",
+		"/timetable?from=2025-06-01&to=2025-08-01",
+		"/timetable?from=tomorrow",
+	} {
+		response := serve(handler, target, nil)
+		if response.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", target, response.Code)
+		}
+	}
+	if source.pages != 0 {
+		t.Errorf("bad ranges fetched %d pages", source.pages)
+	}
+}
+
+func TestTimetableUpstreamErrors(t *testing.T) {
+	tests := []struct {
+		upstream int
+		want     int
+	}{
+		{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+		{http.StatusRequestTimeout, http.StatusServiceUnavailable},
+		{http.StatusGatewayTimeout, http.StatusServiceUnavailable},
+		{http.StatusInternalServerError, http.StatusBadGateway},
+		{http.StatusUnauthorized, http.StatusBadGateway},
+		{http.StatusNotFound, http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		source := &fakeSource{err: &xplorentities.ErrorResponse{Code: tt.upstream, Message: "Response: secret upstream body"}}
+		handler := NewTimetableHandler(source, "2675")
+		handler.Logger = slog.New(slog.DiscardHandler)
+		response := serve(handler, timetablePath, nil)
+		if response.Code != tt.want {
+			t.Errorf("upstream %d: status = %d, want %d", tt.upstream, response.Code, tt.want)
+		}
+		var body xplorentities.ErrorResponse
+		if err := json.Unmarshal(response.Body.Bytes(), &body); err != nil || body.Code != tt.want {
+			t.Errorf("upstream %d: body %s, want an ErrorResponse with code %d", tt.upstream, response.Body, tt.want)
+		}
+		if strings.Contains(response.Body.String(), "secret") {
+			t.Errorf("upstream %d: body %s passes the upstream message through", tt.upstream, response.Body)
+		}
+		if got := response.Header().Get("Cache-Control"); got != "no-store" {
+			t.Errorf("upstream %d: Cache-Control = %q, want no-store", tt.upstream, got)
+		}
+	}
+}
+
+func endlessSource() *fakeSource {
+	return &fakeSource{endless: true, classes: []xplorentities.XPlorClass{
+		newClass("1", time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC), time.Date(2025, 5, 20, 8, 0, 0, 0, time.UTC)),
+	}}
+}
+
+func TestTimetablePageLimit(t *testing.T) {
+	source := endlessSource()
+	handler := NewTimetableHandler(source, "2675")
+	handler.MaxPages = 3
+	handler.Logger = slog.New(slog.DiscardHandler)
+
+	response := serve(handler, timetablePath, nil)
+	if response.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", response.Code)
+	}
+	if source.pages != 3 {
+		t.Errorf("fetched %d pages, want 3", source.pages)
+	}
+}
+
+func TestTimetableClientGone(t *testing.T) {
+	source := endlessSource()
+	handler := NewTimetableHandler(source, "2675")
+	handler.MaxPages = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request := httptest.NewRequest(http.MethodGet, timetablePath, nil).WithContext(ctx)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if source.pages > 1 || (source.pages == 1 && !source.cancelled) {
+		t.Errorf("fetched %d pages for a cancelled request", source.pages)
+	}
+	if recorder.Body.Len() != 0 {
+		t.Errorf("answered %s to a client that went away", recorder.Body)
+	}
+}
@@ -0,0 +1,195 @@
+package xplorsync
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// Key identifies the cursor of a resource on a node
+type Key struct {
+	Resource xplorentities.Resource
+	NodeID   string
+}
+
+func (k Key) String() string {
+	return string(k.Resource) + "@" + k.NodeID
+}
+
+// Cursor is the sync state of a resource on a node
+type Cursor struct {
+	HighWater    time.Time            `json:"highWater"`    // Latest updatedAt read by a complete run
+	LastFullScan time.Time            `json:"lastFullScan"` // Zero until a full scan completes
+	LastRun      time.Time            `json:"lastRun"`
+	Seen         map[string]time.Time `json:"seen"`  // updatedAt of the IRIs emitted within the overlap before HighWater
+	Known        IRISet               `json:"known"` // Every IRI emitted and not deleted since, compared by full scans
+	// Pruned is the updatedAt before which entries were dropped from Seen; zero when Seen holds every IRI
+	Pruned time.Time `json:"pruned"`
+}
+
+// IRISet is a set of IRIs, stored as a sorted JSON array
+type IRISet map[string]struct{}
+
+func (s IRISet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(slices.Sorted(maps.Keys(s)))
+}
+
+func (s *IRISet) UnmarshalJSON(data []byte) error {
+	var iris []string
+	if err := json.Unmarshal(data, &iris); err != nil {
+		return err
+	}
+	*s = make(IRISet, len(iris))
+	for _, iri := range iris {
+		(*s)[iri] = struct{}{}
+	}
+	return nil
+}
+
+// CursorStore persists cursors between runs
+type CursorStore interface {
+	// Load returns the cursor of key, or a zero Cursor when none was saved
+	Load(ctx context.Context, key Key) (Cursor, error)
+	Save(ctx context.Context, key Key, cursor Cursor) error
+}
+
+// MemoryStore keeps cursors in memory, for tests and short-lived processes
+type MemoryStore struct {
+	docs *util.JSONStore
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{docs: util.NewMemoryJSONStore()}
+}
+
+func (s *MemoryStore) Load(_ context.Context, key Key) (Cursor, error) {
+	var cursor Cursor
+	_, err := s.docs.Load(key.String(), &cursor)
+	return cursor, err
+}
+
+func (s *MemoryStore) Save(_ context.Context, key Key, cursor Cursor) error {
+	return s.docs.Save(key.String(), cursor)
+}
+
+// FileStore keeps each cursor in a JSON file of a directory, replaced atomically on save
+type FileStore struct {
+	docs *util.JSONStore
+}
+
+// NewFileStore returns a FileStore writing under dir, created when missing
+func NewFileStore(dir string) (*FileStore, error) {
+	docs, err := util.NewFileJSONStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{docs: docs}, nil
+}
+
+func (s *FileStore) Load(_ context.Context, key Key) (Cursor, error) {
+	var cursor Cursor
+	_, err := s.docs.Load(fileName(key), &cursor)
+	return cursor, err
+}
+
+func (s *FileStore) Save(_ context.Context, key Key, cursor Cursor) error {
+	return s.docs.Save(fileName(key), cursor)
+}
+
+// fileName returns the file name of key without extension, e.g. subscriptions.2675
+func fileName(key Key) string {
+	return strings.ReplaceAll(string(key.Resource), "/", "_") + "." + url.PathEscape(key.NodeID)
+}
+
+// Placeholder formats the bind parameter at 1-based position n for a SQL driver
+type Placeholder func(n int) string
+
+var (
+	// QuestionMark is the placeholder of SQLite and MySQL drivers
+	QuestionMark Placeholder = func(int) string { return "?" }
+	// Dollar is the placeholder of PostgreSQL drivers
+	Dollar Placeholder = func(n int) string { return "$" + strconv.Itoa(n) }
+)
+
+// SQLStore keeps cursors in a table of a database opened by the caller with any database/sql driver
+type SQLStore struct {
+	db          *sql.DB
+	table       string
+	placeholder Placeholder
+}
+
+// NewSQLStore returns a SQLStore on table, a trusted identifier written into the statements as is.
+// A nil placeholder uses QuestionMark.
+func NewSQLStore(db *sql.DB, table string, placeholder Placeholder) *SQLStore {
+	if placeholder == nil {
+		placeholder = QuestionMark
+	}
+	return &SQLStore{db: db, table: table, placeholder: placeholder}
+}
+
+// CreateTable creates the cursor table when it does not exist
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+s.table+` (
+	resource VARCHAR(64) NOT NULL,
+	node_id VARCHAR(64) NOT NULL,
+	state TEXT NOT NULL,
+	updated_at VARCHAR(40) NOT NULL,
+	PRIMARY KEY (resource, node_id)
+)`)
+	return err
+}
+
+func (s *SQLStore) Load(ctx context.Context, key Key) (Cursor, error) {
+	var cursor Cursor
+	var state string
+	err := s.db.QueryRowContext(ctx,
+		"SELECT state FROM "+s.table+" WHERE resource = "+s.placeholder(1)+" AND node_id = "+s.placeholder(2),
+		string(key.Resource), key.NodeID,
+	).Scan(&state)
+	if errors.Is(err, sql.ErrNoRows) {
+		return cursor, nil
+	}
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal([]byte(state), &cursor)
+	return cursor, err
+}
+
+// Save replaces the row of key in a transaction; a delete and an insert work the same on every database
+func (s *SQLStore) Save(ctx context.Context, key Key, cursor Cursor) error {
+	state, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx,
+		"DELETE FROM "+s.table+" WHERE resource = "+s.placeholder(1)+" AND node_id = "+s.placeholder(2),
+		string(key.Resource), key.NodeID,
+	); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO "+s.table+" (resource, node_id, state, updated_at) VALUES ("+
+			s.placeholder(1)+", "+s.placeholder(2)+", "+s.placeholder(3)+", "+s.placeholder(4)+")",
+		string(key.Resource), key.NodeID, string(state), cursor.LastRun.UTC().Format(time.RFC3339),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,287 @@
+// Package xplorsync reads the records changed since the previous run and reports them as change events.
+// For each resource and node a cursor store keeps a high-water mark on updatedAt and the IRIs seen within
+// the overlap before it, so a run only fetches records updated since the mark (minus an overlap tolerating
+// clock skew) and emits each change once, even when the overlap or shifting pages return a record again.
+// The cursor also lists every IRI emitted, which full scans compare with the collection to report deletions.
+package xplorsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorcore"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+	"github.com/angelbarreiros/XPlorGo/xplorexport"
+)
+
+// DefaultOverlap is the window re-read before the high-water mark when none is given
+const DefaultOverlap = 5 * time.Minute
+
+// ChangeKind tells what happened to a record
+type ChangeKind string
+
+const (
+	Created ChangeKind = "created"
+	Updated ChangeKind = "updated"
+	Deleted ChangeKind = "deleted"
+)
+
+// Change is a record created, updated or deleted since the previous run
+type Change[T any] struct {
+	Kind      ChangeKind
+	IRI       string
+	UpdatedAt time.Time // Instant of the change; zero for records that disappeared from the collection
+	Item      T         // Last version read; zero for records that disappeared from the collection
+}
+
+// Feed describes how to read the changes of the collection of T
+type Feed[T xplorentities.Entity] struct {
+	// Query holds the filters of every run, e.g. built with the ToValues method of the typed params.
+	// Full scans report the records that no longer match it as deleted.
+	Query url.Values
+	// IRI returns the @id of an item; items without one are ignored
+	IRI func(item T) string
+	// UpdatedAt returns the last update of an item; items without one are reported once, as created
+	UpdatedAt func(item T) *util.LocalTime
+	// DeletedAt returns the soft deletion time of an item, nil when it is not deleted; nil when T has none
+	DeletedAt func(item T) *util.LocalTime
+	// Since adds the server-side filter on records updated at or after since, given in the API time zone.
+	// Feeds without one read the whole collection on every run and rely on the seen IRIs to skip unchanged records.
+	Since func(query url.Values, since time.Time)
+}
+
+// ContactFeed reads the contacts matching params, filtered on updatedAt by the API
+func ContactFeed(params xplorentities.XPlorContactsParams) Feed[xplorentities.XPlorContact] {
+	query := url.Values{}
+	params.ToValues(&query)
+	return Feed[xplorentities.XPlorContact]{
+		Query:     query,
+		IRI:       func(contact xplorentities.XPlorContact) string { return util.Deref(contact.ID) },
+		UpdatedAt: func(contact xplorentities.XPlorContact) *util.LocalTime { return contact.UpdatedAt },
+		Since: func(query url.Values, since time.Time) {
+			xplorentities.XPlorContactsParams{UpdatedAt: xplorentities.DateFrom(since)}.ToValues(&query)
+		},
+	}
+}
+
+// SubscriptionFeed reads the subscriptions matching params, filtered on updatedAt by the API
+func SubscriptionFeed(params xplorentities.XPlorSubscriptionsParams) Feed[xplorentities.XPlorSubscription] {
+	query := url.Values{}
+	params.ToValues(&query)
+	return Feed[xplorentities.XPlorSubscription]{
+		Query: query,
+		IRI:   func(subscription xplorentities.XPlorSubscription) string { return util.Deref(subscription.Id) },
+		UpdatedAt: func(subscription xplorentities.XPlorSubscription) *util.LocalTime {
+			return util.ParseLocalTime(subscription.UpdatedAt)
+		},
+		Since: func(query url.Values, since time.Time) {
+			xplorentities.XPlorSubscriptionsParams{UpdatedAt: xplorentities.DateFrom(since)}.ToValues(&query)
+		},
+	}
+}
+
+// ClassFeed reads the classes matching params, filtered on updatedAt by the API
+func ClassFeed(organizationName string, params xplorentities.XPlorClassesParams) Feed[xplorentities.XPlorClass] {
+	query := url.Values{}
+	params.ToValues(organizationName, &query)
+	return Feed[xplorentities.XPlorClass]{
+		Query:     query,
+		IRI:       func(class xplorentities.XPlorClass) string { return util.Deref(class.ID) },
+		UpdatedAt: func(class xplorentities.XPlorClass) *util.LocalTime { return class.UpdatedAt },
+		DeletedAt: func(class xplorentities.XPlorClass) *util.LocalTime { return class.DeletedAt },
+		Since: func(query url.Values, since time.Time) {
+			xplorentities.XPlorClassesParams{UpdatedAt: xplorentities.DateFrom(since)}.ToValues(organizationName, &query)
+		},
+	}
+}
+
+// Options tunes a Syncer
+type Options struct {
+	// Location is the club time zone: naive updatedAt values are read in it and Since filters sent in it; nil uses UTC
+	Location *time.Location
+	// Overlap is re-read before the high-water mark to tolerate clock skew; zero uses DefaultOverlap
+	Overlap time.Duration
+	// ItemsPerPage is the page size; zero uses xplorexport.DefaultItemsPerPage
+	ItemsPerPage int
+	// FullScanInterval forces a run reading the whole collection once the last one is older,
+	// reporting the records no longer listed as deleted. Zero only scans fully until one completes.
+	FullScanInterval time.Duration
+	// Now stamps each run; records updated after it do not move the high-water mark. Nil uses time.Now.
+	Now func() time.Time
+}
+
+// Syncer runs feeds against a provider and keeps their cursors in a store.
+// Runs of different resources or nodes may be concurrent; runs of the same one must not.
+type Syncer struct {
+	provider *xplorcore.XplorProvider
+	store    CursorStore
+	options  Options
+}
+
+// New returns a Syncer reading through provider and keeping cursors in store
+func New(provider *xplorcore.XplorProvider, store CursorStore, options Options) *Syncer {
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+	if options.Overlap <= 0 {
+		options.Overlap = DefaultOverlap
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	return &Syncer{provider: provider, store: store, options: options}
+}
+
+// Result summarizes a run
+type Result struct {
+	Created, Updated, Deleted int
+	Unchanged                 int       // Records read again without a newer updatedAt
+	FullScan                  bool      // Whether the whole collection was read
+	HighWater                 time.Time // High-water mark saved for the next run
+}
+
+// Run reads the changes of feed on the node and passes them to emit in the order they are read.
+// The cursor is saved even when the run fails: records already emitted are not emitted again,
+// and the high-water mark only moves once the collection was read entirely, so nothing is missed.
+// An error returned by emit stops the run and is returned as is.
+// Members that cannot be decoded are reported in a *xplorexport.SkippedError; the run then keeps its high-water mark.
+func Run[T xplorentities.Entity](ctx context.Context, s *Syncer, nodeId string, feed Feed[T], emit func(change Change[T]) error) (Result, error) {
+	var zero T
+	key := Key{Resource: zero.Resource(), NodeID: nodeId}
+	cursor, err := s.store.Load(ctx, key)
+	if err != nil {
+		return Result{}, fmt.Errorf("xplorsync: load cursor %s: %w", key, err)
+	}
+	if cursor.Seen == nil {
+		cursor.Seen = make(map[string]time.Time)
+	}
+	if cursor.Known == nil {
+		cursor.Known = make(IRISet, len(cursor.Seen))
+	}
+	for iri := range cursor.Seen {
+		cursor.Known[iri] = struct{}{}
+	}
+
+	now := s.options.Now()
+	result := Result{
+		FullScan:  cursor.LastFullScan.IsZero() || feed.Since == nil || (s.options.FullScanInterval > 0 && now.Sub(cursor.LastFullScan) >= s.options.FullScanInterval),
+		HighWater: cursor.HighWater,
+	}
+	query := url.Values{}
+	for name, values := range feed.Query {
+		query[name] = append([]string(nil), values...)
+	}
+	if !result.FullScan {
+		feed.Since(query, cursor.HighWater.Add(-s.options.Overlap).In(s.options.Location))
+	}
+
+	highWater := cursor.HighWater
+	listed := make(map[string]bool)
+	var emitErr error
+	readErr := xplorexport.Stream[T](ctx, s.provider, nodeId, query, s.options.ItemsPerPage)(func(item T) bool {
+		iri := feed.IRI(item)
+		if iri == "" {
+			return true
+		}
+		listed[iri] = true
+		updatedAt := util.InstantUTC(feed.UpdatedAt(item), s.options.Location)
+		if updatedAt.After(highWater) && !updatedAt.After(now) {
+			highWater = updatedAt
+		}
+
+		change := Change[T]{Kind: Updated, IRI: iri, UpdatedAt: updatedAt, Item: item}
+		seen, inSeen := cursor.Seen[iri]
+		_, known := cursor.Known[iri]
+		if known && !inSeen && updatedAt.Before(cursor.Pruned) {
+			// Emitted by an earlier run and pruned from Seen since
+			result.Unchanged++
+			return true
+		}
+		switch {
+		case feed.DeletedAt != nil && !util.InstantUTC(feed.DeletedAt(item), s.options.Location).IsZero():
+			if !known {
+				return true
+			}
+			change.Kind = Deleted
+		case !known:
+			change.Kind = Created
+		case !updatedAt.After(seen):
+			result.Unchanged++
+			return true
+		}
+		if emitErr = emit(change); emitErr != nil {
+			return false
+		}
+		result.count(change.Kind)
+		if change.Kind == Deleted {
+			delete(cursor.Seen, iri)
+			delete(cursor.Known, iri)
+		} else {
+			cursor.Seen[iri] = updatedAt
+			cursor.Known[iri] = struct{}{}
+		}
+		return true
+	})
+
+	complete := emitErr == nil && readErr == nil
+	if complete && result.FullScan {
+		for iri := range cursor.Known {
+			if listed[iri] {
+				continue
+			}
+			if emitErr = emit(Change[T]{Kind: Deleted, IRI: iri}); emitErr != nil {
+				complete = false
+				break
+			}
+			result.count(Deleted)
+			delete(cursor.Seen, iri)
+			delete(cursor.Known, iri)
+		}
+	}
+	if complete {
+		cursor.HighWater = highWater
+		if result.FullScan {
+			cursor.LastFullScan = now
+		}
+		s.prune(&cursor)
+	}
+	cursor.LastRun = now
+	result.HighWater = cursor.HighWater
+	if err := s.store.Save(ctx, key, cursor); err != nil {
+		return result, errors.Join(emitErr, readErr, fmt.Errorf("xplorsync: save cursor %s: %w", key, err))
+	}
+	if emitErr != nil {
+		return result, emitErr
+	}
+	return result, readErr
+}
+
+// prune drops from Seen the IRIs updated before the overlap window of the next run, records without
+// updatedAt included; they stay in Known, and Run compares their updatedAt with cursor.Pruned instead.
+func (s *Syncer) prune(cursor *Cursor) {
+	cutoff := cursor.HighWater.Add(-s.options.Overlap)
+	if cursor.HighWater.IsZero() || !cutoff.After(cursor.Pruned) {
+		return
+	}
+	for iri, updatedAt := range cursor.Seen {
+		if updatedAt.Before(cutoff) {
+			delete(cursor.Seen, iri)
+		}
+	}
+	cursor.Pruned = cutoff
+}
+
+func (r *Result) count(kind ChangeKind) {
+	switch kind {
+	case Created:
+		r.Created++
+	case Updated:
+		r.Updated++
+	case Deleted:
+		r.Deleted++
+	}
+}
@@ -0,0 +1,197 @@
+package xplorsync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorcore"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// contactsAPI answers the contacts requests of the test provider from records, IRI to updatedAt
+type contactsAPI struct {
+	records map[string]time.Time
+	since   []string // updatedAt[after] of each contacts request, empty for full reads
+}
+
+var (
+	providerOnce sync.Once
+	provider     *xplorcore.XplorProvider
+	currentAPI   *contactsAPI
+)
+
+// provider returns the provider shared by the tests, Init returning a single instance, now answering from api
+func (api *contactsAPI) provider() *xplorcore.XplorProvider {
+	providerOnce.Do(func() {
+		provider = xplorcore.Init(xplorcore.NewConfig("api.test", "v1", "enjoy", "id", "secret", nil, false))
+		provider.Use(func(xplorcore.Handler) xplorcore.Handler {
+			return func(r *http.Request) (*util.RawResponse, *xplorentities.ErrorResponse) {
+				return currentAPI.serve(r)
+			}
+		})
+	})
+	currentAPI = api
+	return provider
+}
+
+func (api *contactsAPI) serve(r *http.Request) (*util.RawResponse, *xplorentities.ErrorResponse) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/oauth/v2/token"):
+		return xplorcore.CannedResponse(http.StatusOK, []byte(`{"access_token":"token","expires_in":3600,"token_type":"bearer"}`))
+	case strings.Contains(r.URL.Path, "/network_nodes/"):
+		return xplorcore.CannedResponse(http.StatusOK, []byte(`{"@id":"/enjoy/network_nodes/2675","id":2675,"name":"Club","type":"club","clubId":"/enjoy/clubs/1249","children":[]}`))
+	case !strings.HasSuffix(r.URL.Path, "/contacts"):
+		return xplorcore.CannedResponse(http.StatusNotFound, []byte(`{}`))
+	}
+	since := r.URL.Query().Get("updatedAt[after]")
+	api.since = append(api.since, since)
+	members := []map[string]string{}
+	for iri, updatedAt := range api.records {
+		stamp := updatedAt.Format(xplorentities.APIDateTimeLayout)
+		if since == "" || stamp >= since {
+			members = append(members, map[string]string{"@id": iri, "updatedAt": stamp})
+		}
+	}
+	body, err := json.Marshal(map[string]any{"hydra:member": members, "hydra:totalItems": len(members)})
+	if err != nil {
+		return nil, &xplorentities.ErrorResponse{Code: http.StatusInternalServerError, Message: err.Error()}
+	}
+	return xplorcore.CannedResponse(http.StatusOK, body)
+}
+
+// syncRun runs the contact feed at now and returns the changes emitted by kind
+func syncRun(t *testing.T, syncer *Syncer, now *time.Time, at time.Time) (Result, map[ChangeKind][]string) {
+	t.Helper()
+	*now = at
+	changes := make(map[ChangeKind][]string)
+	result, err := Run(context.Background(), syncer, "2675", ContactFeed(xplorentities.XPlorContactsParams{}), func(change Change[xplorentities.XPlorContact]) error {
+		changes[change.Kind] = append(changes[change.Kind], change.IRI)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, changes
+}
+
+func clock(hour, minute int) time.Time {
+	return time.Date(2025, 6, 2, hour, minute, 0, 0, time.UTC)
+}
+
+func newTestSyncer(api *contactsAPI, now *time.Time, fullScanInterval time.Duration) (*Syncer, *MemoryStore) {
+	store := NewMemoryStore()
+	return New(api.provider(), store, Options{
+		FullScanInterval: fullScanInterval,
+		Now:              func() time.Time { return *now },
+	}), store
+}
+
+func TestRunCreatedUpdatedAndOverlap(t *testing.T) {
+	api := &contactsAPI{records: map[string]time.Time{
+		"/enjoy/contacts/1": clock(10, 0),
+		"/enjoy/contacts/2": clock(10, 1),
+	}}
+	var now time.Time
+	syncer, _ := newTestSyncer(api, &now, 0)
+
+	result, changes := syncRun(t, syncer, &now, clock(10, 2))
+	if !result.FullScan || result.Created != 2 || len(changes[Created]) != 2 {
+		t.Fatalf("first run: result %+v, changes %v", result, changes)
+	}
+	if !result.HighWater.Equal(clock(10, 1)) {
+		t.Errorf("high water = %v, want %v", result.HighWater, clock(10, 1))
+	}
+
+	// Both records are read again through the overlap and emitted once
+	result, changes = syncRun(t, syncer, &now, clock(10, 3))
+	if result.FullScan || len(changes) != 0 || result.Unchanged != 2 {
+		t.Fatalf("overlap run: result %+v, changes %v", result, changes)
+	}
+	if want := clock(9, 56).Format(xplorentities.APIDateTimeLayout); api.since[1] != want {
+		t.Errorf("updatedAt[after] = %q, want %q", api.since[1], want)
+	}
+
+	api.records["/enjoy/contacts/1"] = clock(10, 5)
+	api.records["/enjoy/contacts/3"] = clock(10, 6)
+	result, changes = syncRun(t, syncer, &now, clock(10, 7))
+	if len(changes[Updated]) != 1 || changes[Updated][0] != "/enjoy/contacts/1" {
+		t.Errorf("updated = %v, want [/enjoy/contacts/1]", changes[Updated])
+	}
+	if len(changes[Created]) != 1 || changes[Created][0] != "/enjoy/contacts/3" {
+		t.Errorf("created = %v, want [/enjoy/contacts/3]", changes[Created])
+	}
+	if result.Unchanged != 1 || !result.HighWater.Equal(clock(10, 6)) {
+		t.Errorf("result %+v, want 1 unchanged and high water 10:06", result)
+	}
+}
+
+func TestRunPrunesSeenAndKeepsKnown(t *testing.T) {
+	api := &contactsAPI{records: map[string]time.Time{
+		"/enjoy/contacts/1": clock(10, 0),
+		"/enjoy/contacts/2": clock(11, 0),
+	}}
+	var now time.Time
+	syncer, store := newTestSyncer(api, &now, time.Hour)
+
+	syncRun(t, syncer, &now, clock(11, 1))
+	cursor, _ := store.Load(context.Background(), Key{Resource: xplorentities.ResourceContacts, NodeID: "2675"})
+	if _, ok := cursor.Seen["/enjoy/contacts/1"]; ok {
+		t.Error("contact 1 updated before the overlap window is still in Seen")
+	}
+	if _, ok := cursor.Known["/enjoy/contacts/1"]; !ok {
+		t.Error("contact 1 was pruned from Known")
+	}
+	if !cursor.Pruned.Equal(clock(10, 55)) {
+		t.Errorf("pruned = %v, want %v", cursor.Pruned, clock(10, 55))
+	}
+
+	// A full scan reads contact 1 again: unchanged, not created
+	result, changes := syncRun(t, syncer, &now, clock(12, 2))
+	if !result.FullScan || len(changes) != 0 || result.Unchanged != 2 {
+		t.Fatalf("full scan: result %+v, changes %v", result, changes)
+	}
+
+	api.records["/enjoy/contacts/1"] = clock(12, 10)
+	_, changes = syncRun(t, syncer, &now, clock(12, 11))
+	if len(changes[Updated]) != 1 || len(changes[Created]) != 0 {
+		t.Errorf("pruned record updated again: changes %v, want one update", changes)
+	}
+}
+
+func TestRunFullScanReportsDeletes(t *testing.T) {
+	api := &contactsAPI{records: map[string]time.Time{
+		"/enjoy/contacts/1": clock(8, 0),
+		"/enjoy/contacts/2": clock(11, 0),
+	}}
+	var now time.Time
+	syncer, store := newTestSyncer(api, &now, time.Hour)
+	syncRun(t, syncer, &now, clock(11, 1))
+
+	// Incremental runs cannot see deletions
+	delete(api.records, "/enjoy/contacts/1")
+	result, changes := syncRun(t, syncer, &now, clock(11, 30))
+	if result.FullScan || len(changes) != 0 {
+		t.Fatalf("incremental run: result %+v, changes %v", result, changes)
+	}
+
+	result, changes = syncRun(t, syncer, &now, clock(12, 1))
+	if !result.FullScan || result.Deleted != 1 || len(changes[Deleted]) != 1 || changes[Deleted][0] != "/enjoy/contacts/1" {
+		t.Fatalf("full scan: result %+v, changes %v, want contact 1 deleted", result, changes)
+	}
+	cursor, _ := store.Load(context.Background(), Key{Resource: xplorentities.ResourceContacts, NodeID: "2675"})
+	if _, ok := cursor.Known["/enjoy/contacts/1"]; ok {
+		t.Error("deleted contact 1 is still known")
+	}
+
+	// The deletion is reported once
+	_, changes = syncRun(t, syncer, &now, clock(13, 2))
+	if len(changes) != 0 {
+		t.Errorf("next full scan: changes %v, want none", changes)
+	}
+}
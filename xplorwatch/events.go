@@ -0,0 +1,140 @@
+package xplorwatch
+
+import (
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// Event is one of AttendeeBooked, AttendeeCanceled, AttendeeQueued, AttendeePromoted, ClassFull and ClassCanceled
+type Event interface {
+	// ClassIRI returns the class the event happened in
+	ClassIRI() string
+	isEvent()
+}
+
+// ClassInfo describes the class of an event as it was last read
+type ClassInfo struct {
+	IRI            string    `json:"iri"`
+	Summary        string    `json:"summary"`
+	StartedAt      time.Time `json:"startedAt"`
+	EndedAt        time.Time `json:"endedAt"`
+	Club           string    `json:"club,omitempty"`
+	Studio         string    `json:"studio,omitempty"`
+	Activity       string    `json:"activity,omitempty"`
+	Coach          string    `json:"coach,omitempty"`
+	AttendingLimit *int      `json:"attendingLimit,omitempty"`
+}
+
+func (c ClassInfo) ClassIRI() string { return c.IRI }
+
+// AttendeeBooked is a contact taking a place in a class
+type AttendeeBooked struct {
+	ClassInfo
+	Attendee xplorentities.Attendee
+}
+
+// AttendeeQueued is a contact joining the waiting list of a class
+type AttendeeQueued struct {
+	ClassInfo
+	Attendee xplorentities.Attendee
+}
+
+// AttendeePromoted is a contact moving from the waiting list to a place
+type AttendeePromoted struct {
+	ClassInfo
+	Attendee xplorentities.Attendee
+}
+
+// AttendeeCanceled is a contact leaving a class or its waiting list.
+// Attendee is the last version read, or the canceled one when the API still lists it.
+type AttendeeCanceled struct {
+	ClassInfo
+	Attendee xplorentities.Attendee
+	Queued   bool // Whether the contact was on the waiting list
+}
+
+// ClassFull is a class whose places are all booked
+type ClassFull struct {
+	ClassInfo
+	Booked int
+	Queued int
+}
+
+// ClassCanceled is a class deleted, or no longer listed while it still falls in the watched window.
+// Attendees lists the booked and queued contacts it had.
+type ClassCanceled struct {
+	ClassInfo
+	Attendees []xplorentities.Attendee
+}
+
+func (AttendeeBooked) isEvent()   {}
+func (AttendeeQueued) isEvent()   {}
+func (AttendeePromoted) isEvent() {}
+func (AttendeeCanceled) isEvent() {}
+func (ClassFull) isEvent()        {}
+func (ClassCanceled) isEvent()    {}
+
+// diff returns the events turning previous into current, in class start order.
+// Classes entering the snapshot only report the bookings made after the previous poll.
+func diff(previous, current *Snapshot) []Event {
+	var events []Event
+	for _, iri := range current.order() {
+		class := current.Classes[iri]
+		before, known := previous.Classes[iri]
+		if class.Deleted {
+			if known && !before.Deleted {
+				events = append(events, ClassCanceled{ClassInfo: class.Info, Attendees: before.attendees()})
+			}
+			continue
+		}
+		// A class entering the window only reports what happened since the previous poll
+		recent := func(entry Entry) bool { return known || entry.At.After(previous.PolledAt) }
+
+		var changes []Event
+		filled := known // Whether a booking read now may have filled the class
+		for _, contact := range sortedKeys(class.Queued) {
+			entry := class.Queued[contact]
+			if _, queued := before.Queued[contact]; !queued && recent(entry) {
+				changes = append(changes, AttendeeQueued{ClassInfo: class.Info, Attendee: entry.Attendee})
+			}
+		}
+		for _, contact := range sortedKeys(class.Booked) {
+			entry := class.Booked[contact]
+			if _, booked := before.Booked[contact]; booked {
+				continue
+			}
+			if _, queued := before.Queued[contact]; queued {
+				changes = append(changes, AttendeePromoted{ClassInfo: class.Info, Attendee: entry.Attendee})
+			} else if recent(entry) {
+				changes = append(changes, AttendeeBooked{ClassInfo: class.Info, Attendee: entry.Attendee})
+				filled = true
+			}
+		}
+		for _, contact := range sortedKeys(before.Queued) {
+			_, queued := class.Queued[contact]
+			_, booked := class.Booked[contact]
+			if !queued && !booked {
+				changes = append(changes, AttendeeCanceled{ClassInfo: class.Info, Attendee: class.canceled(contact, before.Queued[contact]), Queued: true})
+			}
+		}
+		for _, contact := range sortedKeys(before.Booked) {
+			if _, booked := class.Booked[contact]; !booked {
+				changes = append(changes, AttendeeCanceled{ClassInfo: class.Info, Attendee: class.canceled(contact, before.Booked[contact])})
+			}
+		}
+		if class.Full && !before.Full && filled {
+			changes = append(changes, ClassFull{ClassInfo: class.Info, Booked: len(class.Booked), Queued: len(class.Queued)})
+		}
+		events = append(events, changes...)
+	}
+
+	for _, iri := range previous.order() {
+		before := previous.Classes[iri]
+		if _, listed := current.Classes[iri]; listed || before.Deleted || !current.inWindow(before.Info.StartedAt) {
+			continue
+		}
+		events = append(events, ClassCanceled{ClassInfo: before.Info, Attendees: before.attendees()})
+	}
+	return events
+}
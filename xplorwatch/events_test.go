@@ -0,0 +1,209 @@
+package xplorwatch
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+var (
+	polledAt = time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	start    = time.Date(2025, 6, 3, 18, 0, 0, 0, time.UTC)
+)
+
+// entries lists contacts booked or queued at the given minutes after the previous poll; negative ones are older
+func entries(minutes map[string]int) map[string]Entry {
+	list := make(map[string]Entry, len(minutes))
+	for contact, minute := range minutes {
+		id := contact
+		list[contact] = Entry{Attendee: xplorentities.Attendee{ContactID: &id}, At: polledAt.Add(time.Duration(minute) * time.Minute)}
+	}
+	return list
+}
+
+func classSnapshot(iri string, startedAt time.Time, limit int, booked, queued map[string]int) ClassSnapshot {
+	class := ClassSnapshot{
+		Info:   ClassInfo{IRI: iri, StartedAt: startedAt, EndedAt: startedAt.Add(time.Hour)},
+		Booked: entries(booked),
+		Queued: entries(queued),
+	}
+	if limit > 0 {
+		class.Info.AttendingLimit = &limit
+		class.Full = len(class.Booked) >= limit
+	}
+	return class
+}
+
+// snapshotAt holds classes polled at, watching a week from then
+func snapshotAt(at time.Time, classes ...ClassSnapshot) *Snapshot {
+	snapshot := &Snapshot{PolledAt: at, From: at, To: at.Add(7 * 24 * time.Hour), Classes: make(map[string]ClassSnapshot)}
+	for _, class := range classes {
+		snapshot.Classes[class.Info.IRI] = class
+	}
+	return snapshot
+}
+
+// describe renders events as "Kind class contact" for comparison
+func describe(events []Event) []string {
+	described := make([]string, 0, len(events))
+	for _, event := range events {
+		var detail string
+		switch e := event.(type) {
+		case AttendeeBooked:
+			detail = util.Deref(e.Attendee.ContactID)
+		case AttendeeQueued:
+			detail = util.Deref(e.Attendee.ContactID)
+		case AttendeePromoted:
+			detail = util.Deref(e.Attendee.ContactID)
+		case AttendeeCanceled:
+			detail = fmt.Sprintf("%s queued=%v state=%s", util.Deref(e.Attendee.ContactID), e.Queued, e.Attendee.State)
+		case ClassFull:
+			detail = fmt.Sprintf("%d booked %d queued", e.Booked, e.Queued)
+		case ClassCanceled:
+			detail = fmt.Sprintf("%d attendees", len(e.Attendees))
+		}
+		described = append(described, fmt.Sprintf("%T %s %s", event, event.ClassIRI(), detail))
+	}
+	return described
+}
+
+func TestDiff(t *testing.T) {
+	next := polledAt.Add(time.Minute)
+	canceledA := classSnapshot("yoga", start, 10, nil, nil)
+	contact := "a"
+	canceledA.Canceled = map[string]xplorentities.Attendee{"a": {ContactID: &contact, State: "canceled"}}
+	deleted := classSnapshot("yoga", start, 10, nil, nil)
+	deleted.Deleted = true
+
+	tests := []struct {
+		name     string
+		previous *Snapshot
+		current  *Snapshot
+		want     []string
+	}{
+		{
+			name:     "booked then canceled",
+			previous: snapshotAt(polledAt, classSnapshot("yoga", start, 10, map[string]int{"a": -60}, nil)),
+			current:  snapshotAt(next, canceledA),
+			want:     []string{"xplorwatch.AttendeeCanceled yoga a queued=false state=canceled"},
+		},
+		{
+			name:     "queued then promoted",
+			previous: snapshotAt(polledAt, classSnapshot("yoga", start, 1, map[string]int{"a": -60}, map[string]int{"b": -30})),
+			current:  snapshotAt(next, classSnapshot("yoga", start, 1, map[string]int{"b": -30}, nil)),
+			want: []string{
+				"xplorwatch.AttendeePromoted yoga b",
+				"xplorwatch.AttendeeCanceled yoga a queued=false state=",
+			},
+		},
+		{
+			name:     "queued then left the waiting list",
+			previous: snapshotAt(polledAt, classSnapshot("yoga", start, 1, map[string]int{"a": -60}, map[string]int{"b": -30})),
+			current:  snapshotAt(next, classSnapshot("yoga", start, 1, map[string]int{"a": -60}, nil)),
+			want:     []string{"xplorwatch.AttendeeCanceled yoga b queued=true state="},
+		},
+		{
+			name:     "class entering the window only reports recent bookings",
+			previous: snapshotAt(polledAt),
+			current:  snapshotAt(next, classSnapshot("yoga", start, 10, map[string]int{"old": -60, "new": 1}, map[string]int{"waiting": 1})),
+			want: []string{
+				"xplorwatch.AttendeeQueued yoga waiting",
+				"xplorwatch.AttendeeBooked yoga new",
+			},
+		},
+		{
+			name:     "full class entering the window with old bookings is not reported full",
+			previous: snapshotAt(polledAt),
+			current:  snapshotAt(next, classSnapshot("yoga", start, 1, map[string]int{"old": -60}, nil)),
+			want:     nil,
+		},
+		{
+			name:     "class leaving the window is not canceled",
+			previous: snapshotAt(polledAt, classSnapshot("early", polledAt.Add(30*time.Second), 10, map[string]int{"a": -60}, nil)),
+			current:  snapshotAt(next),
+			want:     nil,
+		},
+		{
+			name:     "class no longer listed in the window is canceled",
+			previous: snapshotAt(polledAt, classSnapshot("yoga", start, 10, map[string]int{"a": -60}, map[string]int{"b": -60})),
+			current:  snapshotAt(next),
+			want:     []string{"xplorwatch.ClassCanceled yoga 2 attendees"},
+		},
+		{
+			name:     "deleted class",
+			previous: snapshotAt(polledAt, classSnapshot("yoga", start, 10, map[string]int{"a": -60}, nil)),
+			current:  snapshotAt(next, deleted),
+			want:     []string{"xplorwatch.ClassCanceled yoga 1 attendees"},
+		},
+		{
+			name:     "deleted class is canceled once",
+			previous: snapshotAt(polledAt, deleted),
+			current:  snapshotAt(next, deleted),
+			want:     nil,
+		},
+		{
+			name:     "booking filling the class",
+			previous: snapshotAt(polledAt, classSnapshot("yoga", start, 2, map[string]int{"a": -60}, nil)),
+			current:  snapshotAt(next, classSnapshot("yoga", start, 2, map[string]int{"a": -60, "b": 1}, nil)),
+			want: []string{
+				"xplorwatch.AttendeeBooked yoga b",
+				"xplorwatch.ClassFull yoga 2 booked 0 queued",
+			},
+		},
+		{
+			name:     "class still full",
+			previous: snapshotAt(polledAt, classSnapshot("yoga", start, 2, map[string]int{"a": -60, "b": 1}, nil)),
+			current:  snapshotAt(next, classSnapshot("yoga", start, 2, map[string]int{"a": -60, "b": 1}, map[string]int{"c": 1})),
+			want:     []string{"xplorwatch.AttendeeQueued yoga c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describe(diff(tt.previous, tt.current)); !slices.Equal(got, tt.want) {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttendeeLists(t *testing.T) {
+	contact := func(id string) *string { return &id }
+	state := func(s string) *string { return &s }
+	createdAt := "2025-06-02T09:30:00"
+	canceledAt := "2025-06-02T09:45:00"
+	booked, queued := attendeeLists([]xplorentities.XPlorAttendee{
+		{ContactId: contact("a"), State: state("booked"), CreatedAt: &createdAt},
+		{ContactId: contact("b"), State: state("queued"), QueuedAt: &createdAt},
+		{ContactId: contact("c"), State: state("canceled"), CanceledAt: &canceledAt},
+		{ContactId: contact("d"), State: state("validated"), Showed: true},
+	})
+
+	var bookedIds, queuedIds []string
+	for _, attendee := range booked {
+		bookedIds = append(bookedIds, *attendee.ContactID)
+	}
+	for _, attendee := range queued {
+		queuedIds = append(queuedIds, *attendee.ContactID)
+	}
+	if !slices.Equal(bookedIds, []string{"a", "c", "d"}) || !slices.Equal(queuedIds, []string{"b"}) {
+		t.Fatalf("booked %v, queued %v, want booked [a c d] and queued [b]", bookedIds, queuedIds)
+	}
+	if want := time.Date(2025, 6, 2, 9, 30, 0, 0, time.UTC); !booked[0].CreatedAt.Equal(want) {
+		t.Errorf("createdAt = %v, want %v", booked[0].CreatedAt, want)
+	}
+	if booked[1].CanceledAt == nil || queued[0].QueuedAt == nil || !booked[2].Showed {
+		t.Errorf("times or attendance lost: %+v %+v", booked, queued)
+	}
+
+	// A canceled record is left out of the snapshot lists like an embedded canceled attendee
+	watcher := New(nil, "2675", NewMemoryStore(), Options{})
+	iri := "/enjoy/class_events/1"
+	snapshot := watcher.classSnapshot(xplorentities.XPlorClass{ID: &iri, BookedAttendees: booked, QueuedAttendees: queued})
+	if len(snapshot.Booked) != 2 || len(snapshot.Queued) != 1 || len(snapshot.Canceled) != 1 {
+		t.Errorf("snapshot booked %d, queued %d, canceled %d, want 2, 1 and 1", len(snapshot.Booked), len(snapshot.Queued), len(snapshot.Canceled))
+	}
+}
@@ -0,0 +1,132 @@
+package xplorwatch
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// Snapshot is the state of the watched classes after a poll
+type Snapshot struct {
+	PolledAt time.Time                `json:"polledAt"`
+	From     time.Time                `json:"from"` // Window of the poll, [From, To)
+	To       time.Time                `json:"to"`
+	Classes  map[string]ClassSnapshot `json:"classes"` // By class IRI
+}
+
+// ClassSnapshot is the state of a class after a poll
+type ClassSnapshot struct {
+	Info    ClassInfo        `json:"info"`
+	Deleted bool             `json:"deleted,omitempty"`
+	Full    bool             `json:"full,omitempty"`
+	Booked  map[string]Entry `json:"booked"` // By contact IRI
+	Queued  map[string]Entry `json:"queued"`
+
+	// Canceled lists the canceled attendees the API still returns with the class; it is not persisted
+	Canceled map[string]xplorentities.Attendee `json:"-"`
+}
+
+// Entry is an attendee of a class and the instant it booked or joined the waiting list
+type Entry struct {
+	Attendee xplorentities.Attendee `json:"attendee"`
+	At       time.Time              `json:"at"`
+}
+
+// order returns the class IRIs by start time
+func (s *Snapshot) order() []string {
+	iris := sortedKeys(s.Classes)
+	slices.SortStableFunc(iris, func(a, b string) int {
+		return s.Classes[a].Info.StartedAt.Compare(s.Classes[b].Info.StartedAt)
+	})
+	return iris
+}
+
+func (s *Snapshot) inWindow(t time.Time) bool {
+	return !t.Before(s.From) && t.Before(s.To)
+}
+
+// attendees returns the booked and then the queued attendees, by contact
+func (c ClassSnapshot) attendees() []xplorentities.Attendee {
+	var attendees []xplorentities.Attendee
+	for _, entries := range []map[string]Entry{c.Booked, c.Queued} {
+		for _, contact := range sortedKeys(entries) {
+			attendees = append(attendees, entries[contact].Attendee)
+		}
+	}
+	return attendees
+}
+
+// canceled returns the canceled version of an attendee when the class still lists it, else last
+func (c ClassSnapshot) canceled(contact string, last Entry) xplorentities.Attendee {
+	if attendee, ok := c.Canceled[contact]; ok {
+		return attendee
+	}
+	return last.Attendee
+}
+
+func sortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// SnapshotStore persists the last snapshot of each watcher, so restarts do not report known bookings again
+type SnapshotStore interface {
+	// Load returns the snapshot saved under key, nil when there is none
+	Load(ctx context.Context, key string) (*Snapshot, error)
+	Save(ctx context.Context, key string, snapshot *Snapshot) error
+}
+
+// MemoryStore keeps snapshots in memory, for tests and short-lived processes
+type MemoryStore struct {
+	docs *util.JSONStore
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{docs: util.NewMemoryJSONStore()}
+}
+
+func (s *MemoryStore) Load(_ context.Context, key string) (*Snapshot, error) {
+	return loadSnapshot(s.docs, key)
+}
+
+func (s *MemoryStore) Save(_ context.Context, key string, snapshot *Snapshot) error {
+	return s.docs.Save(key, snapshot)
+}
+
+// FileStore keeps each snapshot in a JSON file of a directory, replaced atomically on save
+type FileStore struct {
+	docs *util.JSONStore
+}
+
+// NewFileStore returns a FileStore writing under dir, created when missing
+func NewFileStore(dir string) (*FileStore, error) {
+	docs, err := util.NewFileJSONStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{docs: docs}, nil
+}
+
+func (s *FileStore) Load(_ context.Context, key string) (*Snapshot, error) {
+	return loadSnapshot(s.docs, url.PathEscape(key))
+}
+
+func (s *FileStore) Save(_ context.Context, key string, snapshot *Snapshot) error {
+	return s.docs.Save(url.PathEscape(key), snapshot)
+}
+
+// loadSnapshot returns the snapshot stored under key, nil when there is none
+func loadSnapshot(docs *util.JSONStore, key string) (*Snapshot, error) {
+	var snapshot Snapshot
+	found, err := docs.Load(key, &snapshot)
+	if !found || err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
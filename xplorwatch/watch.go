@@ -0,0 +1,317 @@
+// Package xplorwatch turns periodic polls of the classes of a node into booking events.
+// Each poll reads the classes starting in a window relative to now, with their booked and queued attendees,
+// and compares them with the previous snapshot to publish typed events (AttendeeBooked, ClassFull, ...).
+// Snapshots are persisted, so a restarted watcher only reports what changed while it was down.
+//
+// Attendees are read from the lists embedded in the classes, or with Options.FetchAttendees from the attendees
+// endpoint, for nodes whose class listings leave those lists empty.
+package xplorwatch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorcore"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+	"github.com/angelbarreiros/XPlorGo/xplorexport"
+)
+
+const (
+	// DefaultInterval is the time between polls when none is given
+	DefaultInterval = time.Minute
+	// DefaultHorizon is the end of the watched window when none is given
+	DefaultHorizon = 7 * 24 * time.Hour
+)
+
+// Options tunes a Watcher
+type Options struct {
+	// Params filters the classes, e.g. by club or activity; their startedAt filters are replaced by the window
+	Params xplorentities.XPlorClassesParams
+	// From and Horizon bound the window of class starts, relative to each poll: [now+From, now+Horizon).
+	// A zero Horizon uses DefaultHorizon.
+	From, Horizon time.Duration
+	// Interval is the time between polls of Run; zero uses DefaultInterval
+	Interval time.Duration
+	// Location is the club time zone, in which naive class and booking times are read and the window is queried; nil uses UTC
+	Location *time.Location
+	// ItemsPerPage is the page size; zero uses xplorexport.DefaultItemsPerPage
+	ItemsPerPage int
+	// FetchAttendees polls the attendee records of each class of the window instead of the attendees embedded in the classes,
+	// for listings that leave them out. It costs at least one request per class and poll.
+	FetchAttendees bool
+	// Key names the snapshot in the store; empty uses the node ID. Watchers of one node with different Params need their own.
+	Key string
+	// OnError receives the errors of the polls made by Run and Events, which retry at the next interval
+	OnError func(err error)
+	// Now is the clock the watched window moves with, e.g. a fixed time in tests; nil uses time.Now
+	Now func() time.Time
+}
+
+// Watcher polls the classes of a node and reports the changes as events
+type Watcher struct {
+	provider *xplorcore.XplorProvider
+	nodeId   string
+	store    SnapshotStore
+	options  Options
+}
+
+// New returns a Watcher polling the classes of the node through provider and keeping its snapshot in store
+func New(provider *xplorcore.XplorProvider, nodeId string, store SnapshotStore, options Options) *Watcher {
+	if options.Horizon == 0 {
+		options.Horizon = DefaultHorizon
+	}
+	if options.Interval <= 0 {
+		options.Interval = DefaultInterval
+	}
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+	if options.Key == "" {
+		options.Key = nodeId
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	return &Watcher{provider: provider, nodeId: nodeId, store: store, options: options}
+}
+
+// Poll reads the classes of the window and returns the events since the previous poll.
+// The first poll of a watcher without a saved snapshot only records the current state and returns no events.
+// The snapshot is saved before Poll returns; Run and Events save it once the events are handled,
+// so an event may be reported again if the process stops meanwhile, but none is lost.
+func (w *Watcher) Poll(ctx context.Context) ([]Event, error) {
+	var events []Event
+	err := w.poll(ctx, func(event Event) bool {
+		events = append(events, event)
+		return true
+	})
+	return events, err
+}
+
+// poll passes the events of a poll to deliver and saves the new snapshot once every event was delivered.
+// When deliver returns false the snapshot is kept, so the next poll reports the events again.
+func (w *Watcher) poll(ctx context.Context, deliver func(event Event) bool) error {
+	previous, err := w.store.Load(ctx, w.options.Key)
+	if err != nil {
+		return fmt.Errorf("xplorwatch: load snapshot %s: %w", w.options.Key, err)
+	}
+	current, err := w.snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		for _, event := range diff(previous, current) {
+			if !deliver(event) {
+				return fmt.Errorf("xplorwatch: events of %s not delivered: %w", w.options.Key, context.Cause(ctx))
+			}
+		}
+	}
+	if err := w.store.Save(ctx, w.options.Key, current); err != nil {
+		return fmt.Errorf("xplorwatch: save snapshot %s: %w", w.options.Key, err)
+	}
+	return nil
+}
+
+// Run polls every Interval and passes the events to handle until ctx is done, which it returns.
+// Failed polls are reported to OnError and retried at the next interval.
+func (w *Watcher) Run(ctx context.Context, handle func(event Event)) error {
+	return w.run(ctx, func(event Event) bool {
+		handle(event)
+		return true
+	})
+}
+
+func (w *Watcher) run(ctx context.Context, deliver func(event Event) bool) error {
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+	for {
+		if err := w.poll(ctx, deliver); err != nil && ctx.Err() == nil && w.options.OnError != nil {
+			w.options.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Events runs the watcher in a goroutine and sends its events on the returned channel, closed once ctx is done.
+// Polls wait for the events of the previous one to be received. Events not received when ctx is done
+// leave the snapshot unsaved, so they are reported again by the next watcher of the store.
+func (w *Watcher) Events(ctx context.Context) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		w.run(ctx, func(event Event) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return events
+}
+
+// snapshot reads the classes of the current window
+func (w *Watcher) snapshot(ctx context.Context) (*Snapshot, error) {
+	now := w.options.Now()
+	current := &Snapshot{
+		PolledAt: now.UTC(),
+		From:     now.Add(w.options.From).UTC(),
+		To:       now.Add(w.options.Horizon).UTC(),
+		Classes:  make(map[string]ClassSnapshot),
+	}
+	params := w.options.Params
+	params.StartedAtBefore, params.StartedAtStrictlyBefore, params.StartedAtAfter, params.StartedAtStrictlyAfter = nil, nil, nil, nil
+	params.StartedAt = xplorentities.DateBetween(current.From.In(w.options.Location), current.To.In(w.options.Location))
+
+	classes := xplorexport.Pages(w.options.ItemsPerPage, func(pagination *xplorentities.XPlorPagination) ([]xplorentities.XPlorClass, xplorentities.PageInfo, *xplorentities.ErrorResponse) {
+		if err := ctx.Err(); err != nil {
+			return nil, xplorentities.PageInfo{}, &xplorentities.ErrorResponse{Code: http.StatusRequestTimeout, Message: "Poll cancelled: " + err.Error()}
+		}
+		page, err := w.provider.ClassesContext(ctx, w.nodeId, &params, pagination)
+		if err != nil {
+			return nil, xplorentities.PageInfo{}, err
+		}
+		return page.Members, page.PageInfo(), nil
+	})
+	var attendeesErr error
+	err := classes(func(class xplorentities.XPlorClass) bool {
+		if class.ID == nil {
+			return true
+		}
+		if w.options.FetchAttendees && (class.DeletedAt == nil || class.DeletedAt.IsZero()) {
+			if attendeesErr = w.fetchAttendees(ctx, &class); attendeesErr != nil {
+				return false
+			}
+		}
+		current.Classes[*class.ID] = w.classSnapshot(class)
+		return true
+	})
+	if err == nil {
+		err = attendeesErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("xplorwatch: %w", err)
+	}
+	return current, nil
+}
+
+// fetchAttendees replaces the attendee lists of class with its records from the attendees endpoint
+func (w *Watcher) fetchAttendees(ctx context.Context, class *xplorentities.XPlorClass) error {
+	classId, err := class.ClassEventID()
+	if err != nil {
+		return err
+	}
+	var records []xplorentities.XPlorAttendee
+	attendees := xplorexport.Pages(w.options.ItemsPerPage, func(pagination *xplorentities.XPlorPagination) ([]xplorentities.XPlorAttendee, xplorentities.PageInfo, *xplorentities.ErrorResponse) {
+		if err := ctx.Err(); err != nil {
+			return nil, xplorentities.PageInfo{}, &xplorentities.ErrorResponse{Code: http.StatusRequestTimeout, Message: "Poll cancelled: " + err.Error()}
+		}
+		page, err := w.provider.AttendeesContext(ctx, w.nodeId, &classId, pagination)
+		if err != nil {
+			return nil, xplorentities.PageInfo{}, err
+		}
+		return page.Members, page.PageInfo(), nil
+	})
+	if err := attendees(func(record xplorentities.XPlorAttendee) bool {
+		records = append(records, record)
+		return true
+	}); err != nil {
+		return fmt.Errorf("attendees of class %s: %w", classId, err)
+	}
+	class.BookedAttendees, class.QueuedAttendees = attendeeLists(records)
+	return nil
+}
+
+// attendeeLists splits attendee records into the booked and queued lists a class embeds, by their state
+func attendeeLists(records []xplorentities.XPlorAttendee) (booked, queued []xplorentities.Attendee) {
+	for _, record := range records {
+		attendee := xplorentities.Attendee{
+			ContactID:            record.ContactId,
+			ContactGivenName:     util.Deref(record.ContactGivenName),
+			ContactFamilyName:    util.Deref(record.ContactFamilyName),
+			ContactNumber:        record.ContactNumber,
+			ContactClubID:        record.ContactClubId,
+			ContactDetails:       record.ContactDetails,
+			ContactCreatedAt:     util.ParseLocalTime(util.Deref(record.ContactCreatedAt)),
+			ContactTagIdUsed:     record.ContactTagUsed,
+			ContactCounterIdUsed: record.ContactCounterUsed,
+			ContactPictureID:     record.ContactPictureId,
+			ContactChannelUsed:   record.ContactChannelUsed,
+			Warnings:             record.Warnings,
+			CreatedBy:            util.Deref(record.CreatedBy),
+			CanceledAt:           util.ParseLocalTime(util.Deref(record.CanceledAt)),
+			CanceledBy:           record.CanceledBy,
+			ValidatedAt:          util.ParseLocalTime(util.Deref(record.ValidatedAt)),
+			ValidatedBy:          record.ValidatedBy,
+			QueuedAt:             util.ParseLocalTime(util.Deref(record.QueuedAt)),
+			QueuedBy:             record.QueuedBy,
+			DeletedAt:            util.ParseLocalTime(util.Deref(record.DeletedAt)),
+			DeletedBy:            record.DeletedBy,
+			State:                util.Deref(record.State),
+			BookedItem:           record.BookedItem,
+			Showed:               record.Showed,
+			Broker:               record.Broker,
+			FromAttendeeGroup:    record.AttendeeGroup != nil,
+			CancelDelayOver:      record.CancelDelayOver,
+		}
+		if createdAt := util.ParseLocalTime(util.Deref(record.CreatedAt)); createdAt != nil {
+			attendee.CreatedAt = *createdAt
+		}
+		if attendee.State == "queued" {
+			queued = append(queued, attendee)
+		} else {
+			booked = append(booked, attendee)
+		}
+	}
+	return booked, queued
+}
+
+func (w *Watcher) classSnapshot(class xplorentities.XPlorClass) ClassSnapshot {
+	snapshot := ClassSnapshot{
+		Info: ClassInfo{
+			IRI:            *class.ID,
+			Summary:        class.Summary,
+			StartedAt:      util.InstantUTC(&class.StartedAt, w.options.Location),
+			EndedAt:        util.InstantUTC(&class.EndedAt, w.options.Location),
+			Club:           util.Deref(class.Club),
+			Studio:         util.Deref(class.Studio),
+			Activity:       util.Deref(class.Activity),
+			Coach:          util.Deref(class.Coach),
+			AttendingLimit: class.AttendingLimit,
+		},
+		Deleted:  class.DeletedAt != nil && !class.DeletedAt.IsZero(),
+		Booked:   make(map[string]Entry),
+		Queued:   make(map[string]Entry),
+		Canceled: make(map[string]xplorentities.Attendee),
+	}
+	lists := []struct {
+		attendees []xplorentities.Attendee
+		entries   map[string]Entry
+	}{{class.BookedAttendees, snapshot.Booked}, {class.QueuedAttendees, snapshot.Queued}}
+	for _, list := range lists {
+		for _, attendee := range list.attendees {
+			if attendee.ContactID == nil {
+				continue
+			}
+			if attendee.CanceledAt != nil || attendee.DeletedAt != nil {
+				snapshot.Canceled[*attendee.ContactID] = attendee
+				continue
+			}
+			at := util.InstantUTC(&attendee.CreatedAt, w.options.Location)
+			if queuedAt := util.InstantUTC(attendee.QueuedAt, w.options.Location); queuedAt.After(at) {
+				at = queuedAt
+			}
+			list.entries[*attendee.ContactID] = Entry{Attendee: attendee, At: at}
+		}
+	}
+	snapshot.Full = class.AttendingLimit != nil && *class.AttendingLimit > 0 && len(snapshot.Booked) >= *class.AttendingLimit
+	return snapshot
+}
@@ -0,0 +1,55 @@
+package xplorwatch
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/util"
+	"github.com/angelbarreiros/XPlorGo/xplorcore"
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+func TestPollFetchAttendees(t *testing.T) {
+	// The class listing leaves the attendee lists empty; the attendees endpoint answers them
+	attendees := `{"hydra:member":[{"contactId":"/enjoy/contacts/1","state":"booked","createdAt":"2025-06-02T09:00:00"}]}`
+	var classIds []string
+	provider := xplorcore.Init(xplorcore.NewConfig("api.test", "v1", "enjoy", "id", "secret", nil, false))
+	provider.Use(func(xplorcore.Handler) xplorcore.Handler {
+		return func(r *http.Request) (*util.RawResponse, *xplorentities.ErrorResponse) {
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/oauth/v2/token"):
+				return xplorcore.CannedResponse(http.StatusOK, []byte(`{"access_token":"token","expires_in":3600,"token_type":"bearer"}`))
+			case strings.Contains(r.URL.Path, "/network_nodes/"):
+				return xplorcore.CannedResponse(http.StatusOK, []byte(`{"@id":"/enjoy/network_nodes/2675","id":2675,"name":"Club","type":"club","clubId":"/enjoy/clubs/1249","children":[]}`))
+			case strings.HasSuffix(r.URL.Path, "/class_events"):
+				return xplorcore.CannedResponse(http.StatusOK, []byte(`{"hydra:member":[{"@id":"/enjoy/class_events/7","summary":"Yoga","startedAt":"2025-06-03T18:00:00","endedAt":"2025-06-03T19:00:00","attendingLimit":10}]}`))
+			case strings.HasSuffix(r.URL.Path, "/attendees"):
+				classIds = append(classIds, r.URL.Query().Get("class_id"))
+				return xplorcore.CannedResponse(http.StatusOK, []byte(attendees))
+			}
+			return xplorcore.CannedResponse(http.StatusNotFound, []byte(`{}`))
+		}
+	})
+
+	now := polledAt
+	watcher := New(provider, "2675", NewMemoryStore(), Options{FetchAttendees: true, Now: func() time.Time { return now }})
+	if _, err := watcher.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	attendees = `{"hydra:member":[{"contactId":"/enjoy/contacts/1","state":"booked","createdAt":"2025-06-02T09:00:00"},` +
+		`{"contactId":"/enjoy/contacts/2","state":"queued","createdAt":"2025-06-02T10:00:30","queuedAt":"2025-06-02T10:00:30"}]}`
+	now = polledAt.Add(time.Minute)
+	events, err := watcher.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := describe(events); len(got) != 1 || got[0] != "xplorwatch.AttendeeQueued /enjoy/class_events/7 /enjoy/contacts/2" {
+		t.Errorf("events = %q, want contact 2 queued", got)
+	}
+	if len(classIds) != 2 || classIds[0] != "7" {
+		t.Errorf("attendees requests for classes %v, want class 7 on each poll", classIds)
+	}
+}
@@ -0,0 +1,64 @@
+package xplorwebhooks
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultDedupWindow is how long MemoryDedup remembers an event ID when no window is given
+const DefaultDedupWindow = 24 * time.Hour
+
+// DedupStore remembers the events being or already handled, so redeliveries are acknowledged without handling them again
+type DedupStore interface {
+	// Claim records id and reports whether it was new; false means the event is a redelivery
+	Claim(ctx context.Context, id string) (bool, error)
+	// Release forgets id after its handling failed, so the next delivery is handled
+	Release(ctx context.Context, id string) error
+}
+
+// MemoryDedup keeps the event IDs of a window in memory.
+// Replicas behind a load balancer need a shared store instead.
+type MemoryDedup struct {
+	mu     sync.Mutex
+	window time.Duration
+	seen   map[string]time.Time
+	now    func() time.Time
+	pruned time.Time
+}
+
+// NewMemoryDedup returns a MemoryDedup remembering IDs for window; zero uses DefaultDedupWindow
+func NewMemoryDedup(window time.Duration) *MemoryDedup {
+	if window <= 0 {
+		window = DefaultDedupWindow
+	}
+	return &MemoryDedup{window: window, seen: make(map[string]time.Time), now: time.Now}
+}
+
+// Claim implements DedupStore
+func (d *MemoryDedup) Claim(_ context.Context, id string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	if now.Sub(d.pruned) >= d.window/10 {
+		for seenID, at := range d.seen {
+			if now.Sub(at) >= d.window {
+				delete(d.seen, seenID)
+			}
+		}
+		d.pruned = now
+	}
+	if at, ok := d.seen[id]; ok && now.Sub(at) < d.window {
+		return false, nil
+	}
+	d.seen[id] = now
+	return true, nil
+}
+
+// Release implements DedupStore
+func (d *MemoryDedup) Release(_ context.Context, id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.seen, id)
+	return nil
+}
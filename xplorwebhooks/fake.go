@@ -0,0 +1,116 @@
+package xplorwebhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// FakeSender delivers signed events the way HMACVerifier expects them, to test receivers without a configured sender.
+// Set Handler to call a Receiver in process, or URL to post to a running server.
+type FakeSender struct {
+	Handler http.Handler
+	URL     string
+	Client  *http.Client // Used with URL; nil uses http.DefaultClient
+
+	Secret          []byte
+	Header          string // Empty uses DefaultSignatureHeader
+	TimestampHeader string // Empty signs the body alone
+	// Now returns the signing time; nil uses time.Now
+	Now func() time.Time
+}
+
+// NewEnvelope returns an event of the given type with a random ID, created now
+func NewEnvelope(eventType string, data any) (Envelope, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Envelope{}, err
+	}
+	id := make([]byte, 16)
+	rand.Read(id)
+	return Envelope{ID: "evt_" + hex.EncodeToString(id), Type: eventType, CreatedAt: time.Now().UTC().Truncate(time.Second), Data: raw}, nil
+}
+
+// Send encodes, signs and delivers an envelope, and returns the response status.
+// Sending the same envelope again simulates a redelivery.
+func (s FakeSender) Send(ctx context.Context, envelope Envelope) (int, error) {
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return 0, err
+	}
+	return s.SendRaw(ctx, body)
+}
+
+// SendRaw signs and delivers body as is, e.g. to test malformed payloads
+func (s FakeSender) SendRaw(ctx context.Context, body []byte) (int, error) {
+	target := s.URL
+	if s.Handler != nil {
+		target = "http://webhooks.test/"
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	var timestamp string
+	if s.TimestampHeader != "" {
+		now := time.Now
+		if s.Now != nil {
+			now = s.Now
+		}
+		timestamp = strconv.FormatInt(now().Unix(), 10)
+		request.Header.Set(s.TimestampHeader, timestamp)
+	}
+	request.Header.Set(headerOr(s.Header, DefaultSignatureHeader), "sha256="+Sign(s.Secret, timestamp, body))
+
+	if s.Handler != nil {
+		recorder := &statusRecorder{header: make(http.Header)}
+		s.Handler.ServeHTTP(recorder, request)
+		return recorder.code(), nil
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	response.Body.Close()
+	return response.StatusCode, nil
+}
+
+// statusRecorder is the http.ResponseWriter of in-process deliveries; it keeps the status and drops the body
+type statusRecorder struct {
+	header http.Header
+	status int
+}
+
+func (r *statusRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return len(data), nil
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+// code returns the status written by the handler, 200 when it wrote none
+func (r *statusRecorder) code() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
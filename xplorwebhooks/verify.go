@@ -0,0 +1,121 @@
+package xplorwebhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSignatureHeader carries the HMAC signature when no header is given
+	DefaultSignatureHeader = "X-Signature"
+	// DefaultTimestampHeader carries the Unix time the signature was computed at
+	DefaultTimestampHeader = "X-Signature-Timestamp"
+	// DefaultTolerance bounds the age of a signed delivery when none is given
+	DefaultTolerance = 5 * time.Minute
+)
+
+// ErrUnauthorized is returned by verifiers for deliveries without a valid signature or secret
+var ErrUnauthorized = errors.New("xplorwebhooks: invalid signature")
+
+// Verifier authenticates a delivery before its payload is read
+type Verifier interface {
+	Verify(r *http.Request, body []byte) error
+}
+
+// HMACVerifier checks an HMAC-SHA256 signature of the body, hex encoded with an optional "sha256=" prefix.
+// With a timestamp header, the signed message is "<timestamp>.<body>" and older deliveries are rejected, so
+// a captured request cannot be replayed later.
+type HMACVerifier struct {
+	// Secrets are tried in order, so a new secret can be added before the sender switches to it
+	Secrets [][]byte
+	// Header carries the signature; empty uses DefaultSignatureHeader
+	Header string
+	// TimestampHeader carries the Unix time of the signature; empty signs the body alone
+	TimestampHeader string
+	// Tolerance is the accepted clock difference for timestamps; zero uses DefaultTolerance
+	Tolerance time.Duration
+	// Now is the clock timestamps are checked against; nil uses time.Now
+	Now func() time.Time
+}
+
+// Verify implements Verifier
+func (v HMACVerifier) Verify(r *http.Request, body []byte) error {
+	signature, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(r.Header.Get(headerOr(v.Header, DefaultSignatureHeader))), "sha256="))
+	if err != nil || len(signature) == 0 {
+		return ErrUnauthorized
+	}
+
+	var timestamp string
+	if v.TimestampHeader != "" {
+		timestamp = strings.TrimSpace(r.Header.Get(v.TimestampHeader))
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return ErrUnauthorized
+		}
+		now := time.Now
+		if v.Now != nil {
+			now = v.Now
+		}
+		tolerance := v.Tolerance
+		if tolerance <= 0 {
+			tolerance = DefaultTolerance
+		}
+		if age := now().Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+			return ErrUnauthorized
+		}
+	}
+
+	for _, secret := range v.Secrets {
+		if hmac.Equal(signature, mac(secret, timestamp, body)) {
+			return nil
+		}
+	}
+	return ErrUnauthorized
+}
+
+// Sign returns the hex signature HMACVerifier expects for body; timestamp is empty when the body is signed alone
+func Sign(secret []byte, timestamp string, body []byte) string {
+	return hex.EncodeToString(mac(secret, timestamp, body))
+}
+
+func mac(secret []byte, timestamp string, body []byte) []byte {
+	hash := hmac.New(sha256.New, secret)
+	if timestamp != "" {
+		hash.Write([]byte(timestamp + "."))
+	}
+	hash.Write(body)
+	return hash.Sum(nil)
+}
+
+// SharedSecret checks that a header carries one of the configured secrets, for senders that cannot sign.
+// Prefer HMACVerifier: the secret travels with every request.
+type SharedSecret struct {
+	// Header carries the secret, e.g. "Authorization" with "Bearer <secret>" values
+	Header  string
+	Secrets []string
+}
+
+// Verify implements Verifier
+func (v SharedSecret) Verify(r *http.Request, _ []byte) error {
+	value := r.Header.Get(v.Header)
+	for _, secret := range v.Secrets {
+		if secret != "" && subtle.ConstantTimeCompare([]byte(value), []byte(secret)) == 1 {
+			return nil
+		}
+	}
+	return ErrUnauthorized
+}
+
+func headerOr(header, fallback string) string {
+	if header == "" {
+		return fallback
+	}
+	return header
+}
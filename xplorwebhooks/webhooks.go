@@ -0,0 +1,211 @@
+// Package xplorwebhooks receives webhooks: an http.Handler that verifies each delivery, skips redeliveries
+// of an event already handled and passes the payload, decoded into the xplorentities types, to typed handlers.
+//
+// Deliveries are JSON envelopes {"id", "type", "createdAt", "data"} by default; set Receiver.Decode for other formats.
+package xplorwebhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+// DefaultMaxBodyBytes bounds the size of a delivery when no limit is given
+const DefaultMaxBodyBytes = 1 << 20
+
+// Envelope is a delivery before its data is decoded
+type Envelope struct {
+	ID        string          `json:"id"`   // Unique per event, repeated by redeliveries
+	Type      string          `json:"type"` // e.g. "contact.updated"
+	CreatedAt time.Time       `json:"createdAt,omitzero"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Event is a delivery whose data was decoded into T
+type Event[T any] struct {
+	ID        string
+	Type      string
+	CreatedAt time.Time
+	Data      T
+	Raw       json.RawMessage // Data as received
+}
+
+// Receiver is an http.Handler dispatching verified deliveries to the handlers registered with On
+type Receiver struct {
+	// Verifier authenticates deliveries; nil accepts every delivery, which is only suitable for tests
+	Verifier Verifier
+	// Dedup skips redeliveries; nil handles every delivery
+	Dedup DedupStore
+	// Decode reads the envelope of a delivery; nil reads the default JSON envelope
+	Decode func(body []byte) (Envelope, error)
+	// MaxBodyBytes bounds the size of a delivery; zero uses DefaultMaxBodyBytes
+	MaxBodyBytes int64
+	// OnError receives the errors of rejected or failed deliveries, e.g. to log them
+	OnError func(r *http.Request, err error)
+
+	mu       sync.RWMutex
+	handlers []route
+}
+
+type route struct {
+	pattern string
+	handle  func(ctx context.Context, envelope Envelope) error
+}
+
+// NewReceiver returns a Receiver verifying deliveries with verifier and remembering event IDs for a day
+func NewReceiver(verifier Verifier) *Receiver {
+	return &Receiver{Verifier: verifier, Dedup: NewMemoryDedup(0)}
+}
+
+// On registers handle for the events whose type matches pattern: an exact type, a prefix ending in "*"
+// such as "contact.*", or "*" for every event. The data of matching events is decoded into T,
+// typically xplorentities.XPlorContact, XPlorSubscription, XPlorAttendee or XPlorClass.
+// Every matching handler is called, in registration order; the first error fails the delivery,
+// which the sender retries from the first handler, so handlers should be idempotent.
+func On[T any](r *Receiver, pattern string, handle func(ctx context.Context, event Event[T]) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, route{pattern: pattern, handle: func(ctx context.Context, envelope Envelope) error {
+		event := Event[T]{ID: envelope.ID, Type: envelope.Type, CreatedAt: envelope.CreatedAt, Raw: envelope.Data}
+		if err := json.Unmarshal(envelope.Data, &event.Data); err != nil {
+			return &PayloadError{Type: envelope.Type, Err: err}
+		}
+		return handle(ctx, event)
+	}})
+}
+
+// PayloadError reports event data that does not match the type of its handler
+type PayloadError struct {
+	Type string
+	Err  error
+}
+
+func (e *PayloadError) Error() string {
+	return fmt.Sprintf("xplorwebhooks: decode %s data: %v", e.Type, e.Err)
+}
+
+func (e *PayloadError) Unwrap() error {
+	return e.Err
+}
+
+func matches(pattern, eventType string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(eventType, prefix)
+	}
+	return pattern == eventType
+}
+
+// ServeHTTP implements http.Handler.
+// Handled events, redeliveries and events without a handler get 204, so the sender does not retry them;
+// a failing handler gets 500 and its event is handled again on the next delivery.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		r.reject(w, req, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	limit := r.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, limit))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			r.reject(w, req, http.StatusRequestEntityTooLarge, fmt.Errorf("delivery larger than %d bytes", limit))
+			return
+		}
+		r.reject(w, req, http.StatusBadRequest, fmt.Errorf("read delivery: %w", err))
+		return
+	}
+	if r.Verifier != nil {
+		if err := r.Verifier.Verify(req, body); err != nil {
+			r.reject(w, req, http.StatusUnauthorized, err)
+			return
+		}
+	}
+
+	decode := r.Decode
+	if decode == nil {
+		decode = decodeEnvelope
+	}
+	envelope, err := decode(body)
+	if err != nil {
+		r.reject(w, req, http.StatusBadRequest, fmt.Errorf("decode delivery: %w", err))
+		return
+	}
+	if envelope.ID == "" || envelope.Type == "" {
+		r.reject(w, req, http.StatusBadRequest, errors.New("delivery without event id or type"))
+		return
+	}
+
+	status, err := r.dispatch(req.Context(), envelope)
+	if err != nil {
+		r.reject(w, req, status, err)
+		return
+	}
+	w.WriteHeader(status)
+}
+
+// dispatch runs the handlers of an event once per event ID
+func (r *Receiver) dispatch(ctx context.Context, envelope Envelope) (int, error) {
+	if r.Dedup != nil {
+		claimed, err := r.Dedup.Claim(ctx, envelope.ID)
+		if err != nil {
+			return http.StatusServiceUnavailable, fmt.Errorf("claim event %s: %w", envelope.ID, err)
+		}
+		if !claimed {
+			return http.StatusNoContent, nil
+		}
+	}
+
+	r.mu.RLock()
+	handlers := r.handlers
+	r.mu.RUnlock()
+	for _, handler := range handlers {
+		if !matches(handler.pattern, envelope.Type) {
+			continue
+		}
+		if err := handler.handle(ctx, envelope); err != nil {
+			if r.Dedup != nil {
+				err = errors.Join(err, r.Dedup.Release(context.WithoutCancel(ctx), envelope.ID))
+			}
+			var payloadErr *PayloadError
+			if errors.As(err, &payloadErr) {
+				return http.StatusUnprocessableEntity, err
+			}
+			return http.StatusInternalServerError, fmt.Errorf("handle event %s (%s): %w", envelope.ID, envelope.Type, err)
+		}
+	}
+	return http.StatusNoContent, nil
+}
+
+func (r *Receiver) reject(w http.ResponseWriter, req *http.Request, status int, err error) {
+	if r.OnError != nil {
+		r.OnError(req, err)
+	}
+	message := err.Error()
+	if status == http.StatusUnauthorized || status >= http.StatusInternalServerError {
+		// Do not tell the sender why, handler errors may carry internal details
+		message = http.StatusText(status)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(xplorentities.ErrorResponse{Code: status, Message: message})
+}
+
+func decodeEnvelope(body []byte) (Envelope, error) {
+	var envelope Envelope
+	err := json.Unmarshal(body, &envelope)
+	return envelope, err
+}
@@ -0,0 +1,125 @@
+package xplorwebhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/angelbarreiros/XPlorGo/xplorentities"
+)
+
+var secret = []byte("webhook-secret")
+
+// newReceiver returns a receiver checking signed timestamps and counting the contact events it handles
+func newReceiver(handle func(event Event[xplorentities.XPlorContact]) error) (*Receiver, *int) {
+	receiver := NewReceiver(HMACVerifier{Secrets: [][]byte{secret}, TimestampHeader: DefaultTimestampHeader})
+	calls := 0
+	On(receiver, "contact.*", func(_ context.Context, event Event[xplorentities.XPlorContact]) error {
+		calls++
+		return handle(event)
+	})
+	return receiver, &calls
+}
+
+func newContactEnvelope(t *testing.T) Envelope {
+	t.Helper()
+	envelope, err := NewEnvelope("contact.updated", map[string]any{"@id": "/enjoy/contacts/42", "givenName": "Jane"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return envelope
+}
+
+func TestSignatureRejected(t *testing.T) {
+	receiver, calls := newReceiver(func(Event[xplorentities.XPlorContact]) error { return nil })
+	envelope := newContactEnvelope(t)
+
+	senders := map[string]FakeSender{
+		"wrong secret": {Handler: receiver, Secret: []byte("other"), TimestampHeader: DefaultTimestampHeader},
+		"stale timestamp": {Handler: receiver, Secret: secret, TimestampHeader: DefaultTimestampHeader,
+			Now: func() time.Time { return time.Now().Add(-2 * DefaultTolerance) }},
+		"no timestamp": {Handler: receiver, Secret: secret},
+	}
+	for name, sender := range senders {
+		t.Run(name, func(t *testing.T) {
+			status, err := sender.Send(context.Background(), envelope)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", status, http.StatusUnauthorized)
+			}
+		})
+	}
+	if *calls != 0 {
+		t.Errorf("handler called %d times for rejected deliveries", *calls)
+	}
+
+	valid := FakeSender{Handler: receiver, Secret: secret, TimestampHeader: DefaultTimestampHeader}
+	if status, err := valid.Send(context.Background(), envelope); err != nil || status != http.StatusNoContent {
+		t.Fatalf("valid delivery: status = %d, err = %v", status, err)
+	}
+	if *calls != 1 {
+		t.Errorf("handler called %d times, want 1", *calls)
+	}
+}
+
+func TestRedeliveryHandledOnce(t *testing.T) {
+	var received []string
+	receiver, calls := newReceiver(func(event Event[xplorentities.XPlorContact]) error {
+		received = append(received, event.Data.GivenName)
+		return nil
+	})
+	sender := FakeSender{Handler: receiver, Secret: secret, TimestampHeader: DefaultTimestampHeader}
+	envelope := newContactEnvelope(t)
+
+	for delivery := 1; delivery <= 3; delivery++ {
+		status, err := sender.Send(context.Background(), envelope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != http.StatusNoContent {
+			t.Errorf("delivery %d: status = %d, want %d", delivery, status, http.StatusNoContent)
+		}
+	}
+	if *calls != 1 {
+		t.Errorf("handler called %d times, want 1", *calls)
+	}
+	if len(received) != 1 || received[0] != "Jane" {
+		t.Errorf("received %v, want [Jane]", received)
+	}
+}
+
+func TestFailedDeliveryRetried(t *testing.T) {
+	failures := 1
+	receiver, calls := newReceiver(func(Event[xplorentities.XPlorContact]) error {
+		if failures > 0 {
+			failures--
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+	var rejected []error
+	receiver.OnError = func(_ *http.Request, err error) { rejected = append(rejected, err) }
+	sender := FakeSender{Handler: receiver, Secret: secret, TimestampHeader: DefaultTimestampHeader}
+	envelope := newContactEnvelope(t)
+
+	want := []int{http.StatusInternalServerError, http.StatusNoContent, http.StatusNoContent}
+	for delivery, wantStatus := range want {
+		status, err := sender.Send(context.Background(), envelope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != wantStatus {
+			t.Errorf("delivery %d: status = %d, want %d", delivery+1, status, wantStatus)
+		}
+	}
+	if *calls != 2 {
+		t.Errorf("handler called %d times, want 2 (the failure and its retry)", *calls)
+	}
+	if len(rejected) != 1 {
+		t.Errorf("OnError called %d times, want 1", len(rejected))
+	}
+}